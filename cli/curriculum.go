@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"math/rand"
+
+	"crownet/config"
+)
+
+// presentation is one digit shown during an exposure epoch and the number of cycles
+// it is shown for.
+type presentation struct {
+	digit  int
+	cycles int
+}
+
+// planCurriculum returns the presentations of every epoch of an expose run, in order,
+// as configured by the curriculum settings of cliCfg. The random choices (shuffled
+// orders and presentation lengths) are drawn from a source of their own, seeded with
+// the run's seed, so that they do not change the network's random draws.
+func planCurriculum(cliCfg *config.CLIConfig) [][]presentation {
+	rng := rand.New(rand.NewSource(cliCfg.Seed))
+	repeats := func(digit int) int {
+		if len(cliCfg.DigitRepeats) > 0 {
+			return cliCfg.DigitRepeats[digit]
+		}
+		if cliCfg.PatternRepeats > 0 {
+			return cliCfg.PatternRepeats
+		}
+		return 1
+	}
+	order := func() []int {
+		digits := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		if cliCfg.CurriculumOrder == config.CurriculumShuffled {
+			rng.Shuffle(len(digits), func(i, j int) { digits[i], digits[j] = digits[j], digits[i] })
+		}
+		return digits
+	}
+	length := func() int {
+		if cliCfg.MaxCyclesPerPattern <= cliCfg.CyclesPerPattern {
+			return cliCfg.CyclesPerPattern
+		}
+		return cliCfg.CyclesPerPattern + rng.Intn(cliCfg.MaxCyclesPerPattern-cliCfg.CyclesPerPattern+1)
+	}
+
+	maxRepeats := 0
+	for digit := 0; digit <= 9; digit++ {
+		maxRepeats = max(maxRepeats, repeats(digit))
+	}
+	plan := make([][]presentation, cliCfg.Epochs)
+	for epoch := range plan {
+		if cliCfg.CurriculumSchedule == config.CurriculumBlocked {
+			for _, digit := range order() {
+				for i := 0; i < repeats(digit); i++ {
+					plan[epoch] = append(plan[epoch], presentation{digit: digit, cycles: length()})
+				}
+			}
+			continue
+		}
+		for pass := 0; pass < maxRepeats; pass++ {
+			for _, digit := range order() {
+				if pass < repeats(digit) {
+					plan[epoch] = append(plan[epoch], presentation{digit: digit, cycles: length()})
+				}
+			}
+		}
+	}
+	return plan
+}
@@ -5,8 +5,10 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath" // Added for path validation
 	"strings"       // Added for path validation messages
@@ -15,6 +17,7 @@ import (
 	"crownet/common"
 	"crownet/config"
 	"crownet/datagen"
+	"crownet/metrics"
 	"crownet/network"
 	"crownet/storage"  // For JSON persistence and SQLite logging
 	"crownet/synaptic" // For synaptic.NetworkWeights type in function signatures
@@ -25,6 +28,12 @@ type Orchestrator struct {
 	AppCfg *config.AppConfig
 	Net    *network.CrowNet
 	Logger *storage.SQLiteLogger
+	model  *storage.ModelBundle // Bundle the network is built from, if ModelFile named an existing one.
+	log    *slog.Logger         // Receives all console reporting of the run (see SetLog).
+	// cycleObservers are notified after every cycle of sim and expose runs (e.g. the metrics stream).
+	cycleObservers []CycleObserver
+	// stimulus applies the StimulusFile schedule of sim runs before every cycle (see setupStimulusSchedule).
+	stimulus *stimulusRunner
 
 	// loadWeightsFn and saveWeightsFn allow for mocking persistence operations in tests.
 	// BUG-STORAGE-001: Changed signature of loadWeightsFn to reflect change in storage.LoadNetworkWeightsFromJSON
@@ -33,24 +42,52 @@ type Orchestrator struct {
 	saveWeightsFn func(weights *synaptic.NetworkWeights, filepath string) error
 }
 
+// CycleObserver receives the network after each cycle of sim and expose runs,
+// from the simulation goroutine.
+type CycleObserver interface {
+	ObserveCycle(net *network.CrowNet)
+}
+
+// ErrInterrupted is returned (wrapped) by Run when its context is canceled. The
+// cycle in progress is completed first, and an expose run saves its weights to
+// RecoveryPath(WeightsFile) before returning.
+var ErrInterrupted = errors.New("run interrupted")
+
 // NewOrchestrator creates a new orchestrator with the given application configuration.
-// It defaults to using actual file system operations for loading/saving weights.
+// It defaults to using actual file system operations for loading/saving weights,
+// with the file format (JSON or binary, optionally gzipped) chosen by extension.
 func NewOrchestrator(appCfg *config.AppConfig) *Orchestrator {
 	return &Orchestrator{
 		AppCfg:        appCfg,
-		loadWeightsFn: storage.LoadNetworkWeightsFromJSON, // BUG-STORAGE-001: This now returns map, handled by o.loadWeights
-		saveWeightsFn: storage.SaveNetworkWeightsToJSON,   // BUG-STORAGE-001: This will be updated to take *NetworkWeights
+		log:           slog.Default(),
+		loadWeightsFn: storage.LoadNetworkWeights,
+		saveWeightsFn: storage.SaveNetworkWeights,
 	}
 }
 
-// Run executes the selected simulation mode. It's the main entry point for the orchestrator.
-func (o *Orchestrator) Run() error {
-	fmt.Println("CrowNet Initializing...")
-	fmt.Printf("Selected Mode: %s\n", o.AppCfg.Cli.Mode)
-	fmt.Printf("Base Configuration: Neurons=%d, WeightsFile='%s'\n",
-		o.AppCfg.Cli.TotalNeurons, o.AppCfg.Cli.WeightsFile)
-	o.printModeSpecificConfig()
+// SetLog replaces the structured logger that receives the run's progress and status
+// records (slog.Default() unless set). It is also handed to the network.
+func (o *Orchestrator) SetLog(logger *slog.Logger) {
+	o.log = logger
+	if o.Net != nil {
+		o.Net.SetLog(logger)
+	}
+}
 
+// Run executes the selected simulation mode. It's the main entry point for the orchestrator.
+// ctx is checked between cycles: once it is canceled, Run completes the current cycle,
+// closes the SQLite logger (writing everything queued) and returns an error wrapping
+// ErrInterrupted.
+func (o *Orchestrator) Run(ctx context.Context) error {
+	o.log.Info("CrowNet initializing", "mode", o.AppCfg.Cli.Mode,
+		"neurons", o.AppCfg.Cli.TotalNeurons, "weights_file", o.AppCfg.Cli.WeightsFile)
+	o.logModeSpecificConfig()
+
+	// The bundle replaces the configured simulation parameters, so it is read before
+	// the logger records the run's configuration.
+	if err := o.loadModelBundle(); err != nil {
+		return fmt.Errorf("model loading failed: %w", err)
+	}
 	if err := o.initializeLogger(); err != nil {
 		return fmt.Errorf("logger initialization failed: %w", err)
 	}
@@ -58,23 +95,43 @@ func (o *Orchestrator) Run() error {
 		defer func() {
 			if errClose := o.Logger.Close(); errClose != nil {
 				// Log error but don't override a primary error from Run()
-				log.Printf("Error closing SQLite logger: %v", errClose)
+				o.log.Error("Failed to close SQLite logger", "err", errClose)
+			}
+			if dropped := o.Logger.Dropped(); dropped > 0 {
+				o.log.Warn("SQLite log writes were dropped because the writer queue was full", "dropped", dropped)
 			}
 		}()
 	}
 
-	o.createNetwork()
+	stream, err := o.startMetricsStream()
+	if err != nil {
+		return fmt.Errorf("metrics stream initialization failed: %w", err)
+	}
+	if stream != nil {
+		defer stream.Close()
+	}
+	exporter, err := o.startMetricsExporter()
+	if err != nil {
+		return fmt.Errorf("metrics endpoint initialization failed: %w", err)
+	}
+	if exporter != nil {
+		defer exporter.Close()
+	}
+
+	if err := o.createNetwork(); err != nil {
+		return err
+	}
 
 	startTime := time.Now()
 	var errRun error
 
 	switch o.AppCfg.Cli.Mode {
 	case config.ModeSim:
-		errRun = o.runSimMode()
+		errRun = o.runSimMode(ctx)
 	case config.ModeExpose:
-		errRun = o.runExposeMode()
+		errRun = o.runExposeMode(ctx)
 	case config.ModeObserve:
-		errRun = o.runObserveMode()
+		errRun = o.runObserveMode(ctx)
 	case config.ModeLogUtil: // FEATURE-004
 		errRun = o.runLogUtilMode()
 	default:
@@ -86,16 +143,16 @@ func (o *Orchestrator) Run() error {
 		return fmt.Errorf("error during execution of mode '%s': %w", o.AppCfg.Cli.Mode, errRun)
 	}
 
-	duration := time.Since(startTime)
-	fmt.Printf("\nCrowNet session finished. Total duration: %s.\n", duration)
+	o.log.Info("CrowNet session finished", "duration", time.Since(startTime))
 	return nil
 }
 
 // initializeLogger sets up the SQLite logger if configured.
 func (o *Orchestrator) initializeLogger() error {
 	cfg := &o.AppCfg.Cli
-	// Logging is active for 'sim' mode, or 'expose' mode if periodic saving to DB is enabled.
-	if cfg.DbPath != "" && (cfg.Mode == config.ModeSim || (cfg.Mode == config.ModeExpose && cfg.SaveInterval > 0)) {
+	// Logging is active for 'sim' mode, or 'expose' mode if periodic saving, spike or synapse logging is enabled.
+	if cfg.DbPath != "" && (cfg.Mode == config.ModeSim ||
+		(cfg.Mode == config.ModeExpose && (cfg.SaveInterval > 0 || cfg.LogSpikes || cfg.SynapseLogInterval > 0))) {
 		validatedDbPath, err := o.validatePath(cfg.DbPath, false) // false: for writing
 		if err != nil {
 			// Allow DbPath to be empty if not in sim mode or expose with saveInterval
@@ -106,11 +163,102 @@ func (o *Orchestrator) initializeLogger() error {
 		}
 		cfg.DbPath = validatedDbPath // Update with cleaned, absolute path
 
-		o.Logger, err = storage.NewSQLiteLogger(cfg.DbPath)
+		o.Logger, err = storage.NewSQLiteLogger(cfg.DbPath, storage.LoggerOptions{
+			QueueSize:    cfg.LogQueueSize,
+			DropWhenFull: cfg.LogBackpressure == config.LogBackpressureDrop,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to initialize SQLite logger at %s: %w", cfg.DbPath, err)
 		}
-		fmt.Printf("SQLite logging enabled: %s\n", cfg.DbPath)
+		if err = o.Logger.StartRun(o.AppCfg); err != nil {
+			o.Logger.Close()
+			o.Logger = nil
+			return fmt.Errorf("failed to record run in SQLite log at %s: %w", cfg.DbPath, err)
+		}
+		o.log.Info("SQLite logging enabled", "db_path", cfg.DbPath, "run_id", o.Logger.RunID(),
+			"log_spikes", cfg.LogSpikes)
+		if cfg.SynapseLogInterval > 0 {
+			o.log.Info("Synapse logging enabled", "interval", cfg.SynapseLogInterval, "mode", o.synapseLogMode())
+		}
+	}
+	return nil
+}
+
+// startMetricsStream serves the Server-Sent Events stream of per-cycle metrics if
+// StreamAddr is set (sim and expose modes). It returns nil if no stream is served.
+func (o *Orchestrator) startMetricsStream() (*metrics.Stream, error) {
+	cfg := &o.AppCfg.Cli
+	if cfg.StreamAddr == "" || (cfg.Mode != config.ModeSim && cfg.Mode != config.ModeExpose) {
+		return nil, nil
+	}
+	stream := metrics.NewStream(cfg.StreamEvery)
+	addr, err := stream.Start(cfg.StreamAddr)
+	if err != nil {
+		return nil, err
+	}
+	o.cycleObservers = append(o.cycleObservers, stream)
+	o.log.Info("Metrics stream started", "url", fmt.Sprintf("http://%s/events", addr))
+	return stream, nil
+}
+
+// startMetricsExporter serves the Prometheus metrics endpoint if MetricsAddr is set
+// (sim and expose modes). It returns nil if no endpoint is served. It must run after
+// initializeLogger, whose write latency it reports.
+func (o *Orchestrator) startMetricsExporter() (*metrics.Exporter, error) {
+	cfg := &o.AppCfg.Cli
+	if cfg.MetricsAddr == "" || (cfg.Mode != config.ModeSim && cfg.Mode != config.ModeExpose) {
+		return nil, nil
+	}
+	var writeStats func() storage.WriteStats
+	if o.Logger != nil {
+		writeStats = o.Logger.WriteStats
+	}
+	exporter := metrics.NewExporter(writeStats)
+	addr, err := exporter.Start(cfg.MetricsAddr)
+	if err != nil {
+		return nil, err
+	}
+	o.cycleObservers = append(o.cycleObservers, exporter)
+	o.log.Info("Prometheus metrics endpoint started", "url", fmt.Sprintf("http://%s/metrics", addr))
+	return exporter, nil
+}
+
+// observeCycle notifies the cycle observers of the cycle just run.
+func (o *Orchestrator) observeCycle() {
+	for _, observer := range o.cycleObservers {
+		observer.ObserveCycle(o.Net)
+	}
+}
+
+// logSpikes records the firings of the cycle that just completed, if spike logging is enabled.
+func (o *Orchestrator) logSpikes() error {
+	if o.Logger == nil || !o.AppCfg.Cli.LogSpikes {
+		return nil
+	}
+	// CycleCount is incremented at the end of RunCycle.
+	if err := o.Logger.LogSpikes(o.Net.CycleCount-1, o.Net.FiredLastCycle()); err != nil {
+		return fmt.Errorf("failed to log spikes at cycle %d: %w", o.Net.CycleCount-1, err)
+	}
+	return nil
+}
+
+// synapseLogMode returns the configured synapse snapshot mode, defaulting to full matrices.
+func (o *Orchestrator) synapseLogMode() string {
+	if o.AppCfg.Cli.SynapseLogMode == "" {
+		return config.SynapseLogFull
+	}
+	return o.AppCfg.Cli.SynapseLogMode
+}
+
+// logSynapses stores a synapse snapshot if synapse logging is enabled and the interval is met.
+// It must run after any LogNetworkState call for the same cycle so both share one snapshot.
+func (o *Orchestrator) logSynapses() error {
+	interval := o.AppCfg.Cli.SynapseLogInterval
+	if o.Logger == nil || interval <= 0 || o.Net.CycleCount == 0 || int(o.Net.CycleCount)%interval != 0 {
+		return nil
+	}
+	if err := o.Logger.LogSynapseSnapshot(o.Net, o.synapseLogMode(), o.AppCfg.Cli.SynapseLogThreshold); err != nil {
+		return fmt.Errorf("failed to log synapse snapshot at cycle %d: %w", o.Net.CycleCount, err)
 	}
 	return nil
 }
@@ -190,7 +338,7 @@ func (o *Orchestrator) validatePath(rawPath string, forRead bool) (string, error
 // using the application configuration. It passes the necessary parameters
 // to network.NewCrowNet to construct and set up the network.
 // Note: This function assumes network.NewCrowNet handles detailed setup based on AppConfig.
-func (o *Orchestrator) createNetwork() {
+func (o *Orchestrator) createNetwork() error {
 	// cliCfg := &o.AppCfg.Cli // Unused variable
 	// TODO: The call to network.NewCrowNet here needs to be updated -> This TODO is now being addressed by BUG-CORE-001
 	// to match the signature network.NewCrowNet(appCfg *config.AppConfig).
@@ -198,14 +346,15 @@ func (o *Orchestrator) createNetwork() {
 
 	// BUG-CORE-001: Corrected the call to NewCrowNet to pass the AppConfig directly.
 	var err error
-	o.Net, err = network.NewCrowNet(o.AppCfg)
+	if o.model != nil {
+		o.Net, err = o.networkFromModel()
+	} else {
+		o.Net, err = network.NewCrowNet(o.AppCfg)
+	}
 	if err != nil {
-		// This is a critical failure during network initialization.
-		// Log and potentially panic or return an error that stops the orchestrator.
-		// For now, let's log.Fatalf which will print and exit.
-		// This aligns with how main.go handles errors from NewAppConfig.
-		log.Fatalf("Failed to create network: %v", err)
+		return fmt.Errorf("failed to create network: %w", err)
 	}
+	o.Net.SetLog(o.log)
 
 	// Log initial network state.
 	// Showing only the first few input/output neuron IDs for brevity.
@@ -214,13 +363,85 @@ func (o *Orchestrator) createNetwork() {
 	numInputs := len(o.Net.InputNeuronIDs)
 	numOutputs := len(o.Net.OutputNeuronIDs)
 
-	fmt.Printf("Network created: %d neurons. Input IDs: %v..., Output IDs: %v...\n",
-		len(o.Net.Neurons),
-		o.Net.InputNeuronIDs[:minInt(maxInputToShow, numInputs)],
-		o.Net.OutputNeuronIDs[:minInt(maxOutputToShow, numOutputs)],
-	)
-	fmt.Printf("Initial State: Cortisol=%.3f, Dopamine=%.3f\n",
-		o.Net.ChemicalEnv.CortisolLevel, o.Net.ChemicalEnv.DopamineLevel)
+	stats := o.Net.TopologyStats
+	o.log.Info("Network created",
+		"neurons", len(o.Net.Neurons),
+		"inputs", numInputs,
+		"outputs", numOutputs,
+		"first_input_ids", o.Net.InputNeuronIDs[:minInt(maxInputToShow, numInputs)],
+		"first_output_ids", o.Net.OutputNeuronIDs[:minInt(maxOutputToShow, numOutputs)],
+		"topology", stats.Generator,
+		"connections", stats.Connections,
+		"min_in_degree", stats.MinInDegree,
+		"mean_in_degree", stats.MeanInDegree,
+		"max_in_degree", stats.MaxInDegree,
+		"cortisol", float64(o.Net.ChemicalEnv.CortisolLevel),
+		"dopamine", float64(o.Net.ChemicalEnv.DopamineLevel))
+	return nil
+}
+
+// loadModelBundle reads the model bundle named by ModelFile for the observe and expose
+// modes. observe requires it to exist; expose resumes from it if it exists and
+// otherwise starts a new network (saved to ModelFile at the end). The bundle's
+// simulation parameters and neuron count replace the configured ones, except for
+// --set overrides, which are applied again on top of them; the result is validated
+// again, since a hand-edited bundle or an override may be invalid against it.
+func (o *Orchestrator) loadModelBundle() error {
+	cliCfg := &o.AppCfg.Cli
+	if cliCfg.ModelFile == "" || (cliCfg.Mode != config.ModeObserve && cliCfg.Mode != config.ModeExpose) {
+		return nil
+	}
+	if _, errStat := os.Stat(cliCfg.ModelFile); os.IsNotExist(errStat) && cliCfg.Mode == config.ModeExpose {
+		o.log.Info("Model file does not exist yet, starting with a new network", "path", cliCfg.ModelFile)
+		return nil
+	}
+	validatedFilepath, err := o.validatePath(cliCfg.ModelFile, true)
+	if err != nil {
+		return fmt.Errorf("invalid model file path '%s': %w", cliCfg.ModelFile, err)
+	}
+	bundle, err := storage.LoadModelBundle(validatedFilepath)
+	if err != nil {
+		return err
+	}
+	o.model = bundle
+	o.AppCfg.SimParams = bundle.SimParams
+	if err := o.AppCfg.ApplyOverrides(cliCfg.Overrides); err != nil {
+		return fmt.Errorf("failed to apply overrides to model parameters: %w", err)
+	}
+	cliCfg.TotalNeurons = len(bundle.Neurons)
+	if err := o.AppCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration with the parameters of model bundle '%s': %w", validatedFilepath, err)
+	}
+	o.log.Info("Model loaded", "path", validatedFilepath, "neurons", len(bundle.Neurons),
+		"synapses", len(bundle.Synapses), "format_version", bundle.FormatVersion,
+		"saved_at_cycle", bundle.Cycle, "software_version", bundle.SoftwareVersion)
+	return nil
+}
+
+// networkFromModel builds the network from the loaded model bundle.
+func (o *Orchestrator) networkFromModel() (*network.CrowNet, error) {
+	layout, weights, err := o.model.Layout()
+	if err != nil {
+		return nil, fmt.Errorf("invalid model bundle: %w", err)
+	}
+	return network.NewCrowNetFromLayout(o.AppCfg, layout, weights)
+}
+
+// saveModel saves the network's layout, weights and simulation parameters as a model bundle.
+func (o *Orchestrator) saveModel(rawFilepath string) error {
+	validatedFilepath, err := o.validatePath(rawFilepath, false) // false: for writing
+	if err != nil {
+		return fmt.Errorf("invalid model file path '%s' for saving: %w", rawFilepath, err)
+	}
+	bundle, err := storage.NewModelBundle(o.Net)
+	if err != nil {
+		return err
+	}
+	if err := storage.SaveModelBundle(bundle, validatedFilepath); err != nil {
+		return err
+	}
+	o.log.Info("Model saved", "path", validatedFilepath, "neurons", len(bundle.Neurons), "synapses", len(bundle.Synapses))
+	return nil
 }
 
 // loadWeights loads synaptic weights from the specified file.
@@ -248,7 +469,20 @@ func (o *Orchestrator) loadWeights(rawFilepath string) error {
 		return fmt.Errorf("critical error: SynapticWeights not initialized in CrowNet before loading")
 	}
 	o.Net.SynapticWeights.LoadWeights(weightsMap) // Populate the existing *NetworkWeights instance
-	fmt.Printf("Existing weights loaded from %s\n", validatedFilepath)
+	o.log.Info("Weights loaded", "path", validatedFilepath)
+
+	// Per-neuron parameters are optional: weight files written before they existed have none.
+	paramsPath := storage.NeuronParamsPath(validatedFilepath)
+	if _, errStat := os.Stat(paramsPath); errStat == nil {
+		params, errParams := storage.LoadNeuronParametersFromJSON(paramsPath)
+		if errParams != nil {
+			return fmt.Errorf("failed to load neuron parameters from %s: %w", paramsPath, errParams)
+		}
+		if errParams := o.Net.LoadNeuronParameters(params); errParams != nil {
+			return fmt.Errorf("failed to apply neuron parameters from %s: %w", paramsPath, errParams)
+		}
+		o.log.Info("Neuron parameters loaded", "path", paramsPath)
+	}
 	return nil
 }
 
@@ -267,26 +501,32 @@ func (o *Orchestrator) saveWeights(rawFilepath string) error {
 	if err := o.saveWeightsFn(o.Net.SynapticWeights, validatedFilepath); err != nil {
 		return fmt.Errorf("failed to save trained weights to %s: %w", validatedFilepath, err)
 	}
-	fmt.Printf("Trained weights saved to %s\n", validatedFilepath)
+	o.log.Info("Weights saved", "path", validatedFilepath)
+
+	paramsPath := storage.NeuronParamsPath(validatedFilepath)
+	if err := storage.SaveNeuronParametersToJSON(o.Net.NeuronParameters(), paramsPath); err != nil {
+		return fmt.Errorf("failed to save neuron parameters to %s: %w", paramsPath, err)
+	}
 	return nil
 }
 
-// printModeSpecificConfig outputs configuration details relevant to the current execution mode.
-func (o *Orchestrator) printModeSpecificConfig() {
+// logModeSpecificConfig records configuration details relevant to the current execution mode.
+func (o *Orchestrator) logModeSpecificConfig() {
 	cliCfg := o.AppCfg.Cli
 	switch cliCfg.Mode {
 	case config.ModeExpose:
-		fmt.Printf("  ModeExpose: Epochs=%d, BaseLearningRate=%.4f, CyclesPerPattern=%d\n",
-			cliCfg.Epochs, cliCfg.BaseLearningRate, cliCfg.CyclesPerPattern)
+		o.log.Info("Expose configuration", "epochs", cliCfg.Epochs,
+			"base_learning_rate", float64(cliCfg.BaseLearningRate), "cycles_per_pattern", cliCfg.CyclesPerPattern,
+			"curriculum_order", cliCfg.CurriculumOrder, "curriculum_schedule", cliCfg.CurriculumSchedule,
+			"rest_cycles", cliCfg.RestCycles)
 	case config.ModeObserve:
-		fmt.Printf("  ModeObserve: Digit=%d, CyclesToSettle=%d\n",
-			cliCfg.Digit, cliCfg.CyclesToSettle)
+		o.log.Info("Observe configuration", "digit", cliCfg.Digit, "cycles_to_settle", cliCfg.CyclesToSettle)
 	case config.ModeSim:
-		fmt.Printf("  ModeSim: TotalCycles=%d, DBPath='%s', DBSaveInterval=%d\n",
-			cliCfg.Cycles, cliCfg.DbPath, cliCfg.SaveInterval)
+		o.log.Info("Sim configuration", "cycles", cliCfg.Cycles, "db_path", cliCfg.DbPath,
+			"save_interval", cliCfg.SaveInterval)
 		if cliCfg.StimInputFreqHz > 0 && cliCfg.StimInputID != -2 { // -2 means stimulus disabled
-			fmt.Printf("  ModeSim: ContinuousStimulus: InputID=%d at %.1f Hz\n",
-				cliCfg.StimInputID, cliCfg.StimInputFreqHz)
+			o.log.Info("Sim continuous stimulus configured", "input_id", cliCfg.StimInputID,
+				"hz", cliCfg.StimInputFreqHz)
 		}
 	}
 }
@@ -320,25 +560,61 @@ func (o *Orchestrator) setupContinuousInputStimulus() error {
 		return fmt.Errorf("failed to configure frequency input for neuron %d at %.1f Hz: %w",
 			stimID, cliCfg.StimInputFreqHz, err)
 	}
-	fmt.Printf("Continuous stimulus: Input Neuron %d at %.1f Hz.\n", stimID, cliCfg.StimInputFreqHz)
+	o.log.Info("Continuous stimulus started", "input_id", stimID, "hz", cliCfg.StimInputFreqHz)
 	return nil
 }
 
-// runSimulationLoop executes the main simulation cycles.
-func (o *Orchestrator) runSimulationLoop() error {
+// logCycle records the progress of the cycle just run, out of a run of cycles cycles.
+func (o *Orchestrator) logCycle(ctx context.Context, level slog.Level, cycles int) {
+	if !o.log.Enabled(ctx, level) {
+		return
+	}
+	env := o.Net.ChemicalEnv
+	o.log.Log(ctx, level, "Cycle completed",
+		"cycle", int(o.Net.CycleCount-1), // CycleCount is incremented at the end of RunCycle
+		"cycles", cycles,
+		"cortisol", float64(env.CortisolLevel),
+		"dopamine", float64(env.DopamineLevel),
+		"lr_mod", float64(env.LearningRateModulationFactor),
+		"syn_mod", float64(env.SynaptogenesisModulationFactor),
+		"pulses", len(o.Net.ActivePulses.GetAll()))
+}
+
+// interrupted returns an error wrapping ErrInterrupted if ctx has been canceled.
+func (o *Orchestrator) interrupted(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return fmt.Errorf("%w after %d cycles: %v", ErrInterrupted, o.Net.CycleCount, context.Cause(ctx))
+}
+
+// runSimulationLoop executes the main simulation cycles. If ctx is canceled, it stops
+// after the current cycle and logs the final network state before returning.
+func (o *Orchestrator) runSimulationLoop(ctx context.Context) error {
 	cycles := o.AppCfg.Cli.Cycles
 	saveInterval := o.AppCfg.Cli.SaveInterval
 
+	completed := 0
+	var errInterrupted error
 	for i := 0; i < cycles; i++ {
+		if errInterrupted = o.interrupted(ctx); errInterrupted != nil {
+			break
+		}
+		completed++
+		if err := o.applyStimulus(i); err != nil {
+			return err
+		}
 		o.Net.RunCycle()
-		// Log progress periodically
+		o.observeCycle()
+		if err := o.logSpikes(); err != nil {
+			return err
+		}
+		// Log progress every 10 cycles, and every cycle at debug level
+		level := slog.LevelDebug
 		if i%10 == 0 || i == cycles-1 {
-			fmt.Printf("Cycle %d/%d: Cortisol:%.3f Dopamine:%.3f LRMod:%.3f SynMod:%.3f Pulses:%d\n",
-				o.Net.CycleCount-1, cycles, // CycleCount is incremented at the end of RunCycle
-				o.Net.ChemicalEnv.CortisolLevel, o.Net.ChemicalEnv.DopamineLevel,
-				o.Net.ChemicalEnv.LearningRateModulationFactor, o.Net.ChemicalEnv.SynaptogenesisModulationFactor,
-				len(o.Net.ActivePulses.GetAll())) // Use GetAll() for length
+			level = slog.LevelInfo
 		}
+		o.logCycle(ctx, level, cycles)
 
 		// Log network state to DB if enabled and interval is met
 		if o.Logger != nil && saveInterval > 0 && o.Net.CycleCount > 0 && int(o.Net.CycleCount)%saveInterval == 0 {
@@ -346,15 +622,18 @@ func (o *Orchestrator) runSimulationLoop() error {
 				return fmt.Errorf("failed to log network state to DB (periodic) at cycle %d: %w", o.Net.CycleCount, err)
 			}
 		}
+		if err := o.logSynapses(); err != nil {
+			return err
+		}
 	}
 
 	// Final log if DB is enabled and the last cycle wasn't a save interval point
-	if o.Logger != nil && cycles > 0 && (saveInterval == 0 || cycles%saveInterval != 0) {
+	if o.Logger != nil && completed > 0 && (saveInterval == 0 || completed%saveInterval != 0) {
 		if err := o.Logger.LogNetworkState(o.Net); err != nil {
 			return fmt.Errorf("failed to log final network state to DB: %w", err)
 		}
 	}
-	return nil
+	return errInterrupted
 }
 
 // reportMonitoredOutputFrequency prints the firing frequency of a monitored output neuron.
@@ -386,103 +665,189 @@ func (o *Orchestrator) reportMonitoredOutputFrequency() error {
 	if err != nil {
 		return fmt.Errorf("failed to get frequency for output neuron %d: %w", monitorID, err)
 	}
-	fmt.Printf("Frequency for Output Neuron %d: %.2f Hz (over last %.0f cycles).\n",
-		monitorID, freq, o.AppCfg.SimParams.Structure.OutputFrequencyWindowCycles)
+	o.log.Info("Output neuron frequency", "neuron_id", monitorID, "hz", freq,
+		"window_cycles", o.AppCfg.SimParams.Structure.OutputFrequencyWindowCycles)
 	return nil
 }
 
 // runSimMode handles the 'sim' execution mode.
-func (o *Orchestrator) runSimMode() error {
-	fmt.Printf("\nStarting General Simulation for %d cycles...\n", o.AppCfg.Cli.Cycles)
+func (o *Orchestrator) runSimMode(ctx context.Context) error {
+	o.log.Info("Starting simulation", "cycles", o.AppCfg.Cli.Cycles)
 	if err := o.setupContinuousInputStimulus(); err != nil {
 		return fmt.Errorf("error in stimulus setup: %w", err)
 	}
+	if err := o.setupStimulusSchedule(); err != nil {
+		return fmt.Errorf("error in stimulus schedule setup: %w", err)
+	}
 
 	o.Net.SetDynamicState(true, true, true) // Neurochemicals, learning, synaptogenesis active
 
-	if err := o.runSimulationLoop(); err != nil {
+	if err := o.runSimulationLoop(ctx); err != nil {
+		if errors.Is(err, ErrInterrupted) {
+			o.log.Warn("Simulation interrupted", "cycles_completed", int(o.Net.CycleCount))
+			return err
+		}
 		return fmt.Errorf("error during simulation loop: %w", err)
 	}
 	if err := o.reportMonitoredOutputFrequency(); err != nil {
 		return fmt.Errorf("error reporting monitored output frequency: %w", err)
 	}
 
-	fmt.Printf("Final State: Cortisol=%.3f, Dopamine=%.3f\n",
-		o.Net.ChemicalEnv.CortisolLevel, o.Net.ChemicalEnv.DopamineLevel)
+	o.log.Info("Final state", "cortisol", float64(o.Net.ChemicalEnv.CortisolLevel),
+		"dopamine", float64(o.Net.ChemicalEnv.DopamineLevel))
 	return nil
 }
 
-// runExposureEpochs handles the core loop for the 'expose' mode.
-func (o *Orchestrator) runExposureEpochs() error {
+// runExposureEpochs handles the core loop for the 'expose' mode: it presents the
+// digits of each epoch in the order and for the lengths planned by planCurriculum,
+// with the configured rest cycles after each presentation. If ctx is canceled, it
+// stops after the current cycle.
+func (o *Orchestrator) runExposureEpochs(ctx context.Context) error {
 	allPatterns, err := datagen.GetAllDigitPatterns(&o.AppCfg.SimParams)
 	if err != nil {
 		return fmt.Errorf("failed to load digit patterns: %w", err)
 	}
 
 	cliCfg := o.AppCfg.Cli
-	for epoch := 0; epoch < cliCfg.Epochs; epoch++ {
-		fmt.Printf("Epoch %d/%d starting...\n", epoch+1, cliCfg.Epochs)
+	plan := planCurriculum(&cliCfg)
+	totalCycles := 0
+	for _, presentations := range plan {
+		for _, p := range presentations {
+			totalCycles += p.cycles + cliCfg.RestCycles
+		}
+	}
+	for epoch, presentations := range plan {
+		o.log.Info("Epoch started", "epoch", epoch+1, "epochs", cliCfg.Epochs, "presentations", len(presentations))
 		patternsProcessedThisEpoch := 0
-		// Consider randomizing pattern order or using a defined sequence if needed.
-		// For now, iterating 0-9.
-		for digit := 0; digit <= 9; digit++ {
-			pattern, ok := allPatterns[digit]
+		for _, p := range presentations {
+			pattern, ok := allPatterns[p.digit]
 			if !ok {
-				return fmt.Errorf("pattern for digit %d not found in loaded set (epoch %d)", digit, epoch+1)
+				return fmt.Errorf("pattern for digit %d not found in loaded set (epoch %d)", p.digit, epoch+1)
 			}
 
 			o.Net.ResetNetworkStateForNewPattern()
 			if errPres := o.Net.PresentPattern(pattern); errPres != nil {
-				return fmt.Errorf("failed to present pattern for digit %d in epoch %d: %w", digit, epoch+1, errPres)
+				return fmt.Errorf("failed to present pattern for digit %d in epoch %d: %w", p.digit, epoch+1, errPres)
 			}
-
-			for cycleInPattern := 0; cycleInPattern < cliCfg.CyclesPerPattern; cycleInPattern++ {
-				o.Net.RunCycle()
-				// Log to DB if enabled and interval is met
-				if o.Logger != nil && cliCfg.SaveInterval > 0 && o.Net.CycleCount > 0 &&
-					int(o.Net.CycleCount)%cliCfg.SaveInterval == 0 {
-					if errLog := o.Logger.LogNetworkState(o.Net); errLog != nil {
-						return fmt.Errorf("failed to log network state (epoch %d, digit %d, cycle %d): %w",
-							epoch+1, digit, o.Net.CycleCount, errLog)
-					}
-				}
+			o.log.Debug("Pattern presented", "epoch", epoch+1, "digit", p.digit, "cycles", p.cycles)
+			if err := o.runExposureCycles(ctx, p.cycles, totalCycles, epoch, p.digit); err != nil {
+				return err
+			}
+			// Rest: no input, while the dynamics keep running.
+			if err := o.runExposureCycles(ctx, cliCfg.RestCycles, totalCycles, epoch, p.digit); err != nil {
+				return err
 			}
 			patternsProcessedThisEpoch++
 		}
-		fmt.Printf("Epoch %d/%d completed. Processed %d patterns. Cortisol: %.3f, Dopamine: %.3f, EffectiveLRFactor: %.4f\n",
-			epoch+1, cliCfg.Epochs, patternsProcessedThisEpoch,
-			o.Net.ChemicalEnv.CortisolLevel, o.Net.ChemicalEnv.DopamineLevel, o.Net.ChemicalEnv.LearningRateModulationFactor)
+		o.log.Info("Epoch completed", "epoch", epoch+1, "epochs", cliCfg.Epochs,
+			"patterns", patternsProcessedThisEpoch,
+			"cortisol", float64(o.Net.ChemicalEnv.CortisolLevel),
+			"dopamine", float64(o.Net.ChemicalEnv.DopamineLevel),
+			"lr_mod", float64(o.Net.ChemicalEnv.LearningRateModulationFactor))
+	}
+	return nil
+}
+
+// runExposureCycles runs cycles cycles of an exposure run of totalCycles cycles,
+// during or after the presentation of digit in epoch, with the per-cycle logging.
+func (o *Orchestrator) runExposureCycles(ctx context.Context, cycles, totalCycles, epoch, digit int) error {
+	saveInterval := o.AppCfg.Cli.SaveInterval
+	for i := 0; i < cycles; i++ {
+		if errInterrupted := o.interrupted(ctx); errInterrupted != nil {
+			return fmt.Errorf("%w (epoch %d, digit %d)", errInterrupted, epoch+1, digit)
+		}
+		o.Net.RunCycle()
+		o.observeCycle()
+		o.logCycle(ctx, slog.LevelDebug, totalCycles)
+		if errSpikes := o.logSpikes(); errSpikes != nil {
+			return errSpikes
+		}
+		// Log to DB if enabled and interval is met
+		if o.Logger != nil && saveInterval > 0 && o.Net.CycleCount > 0 &&
+			int(o.Net.CycleCount)%saveInterval == 0 {
+			if errLog := o.Logger.LogNetworkState(o.Net); errLog != nil {
+				return fmt.Errorf("failed to log network state (epoch %d, digit %d, cycle %d): %w",
+					epoch+1, digit, o.Net.CycleCount, errLog)
+			}
+		}
+		if errSyn := o.logSynapses(); errSyn != nil {
+			return errSyn
+		}
 	}
 	return nil
 }
 
 // runExposeMode handles the 'expose' execution mode for training the network.
-func (o *Orchestrator) runExposeMode() error {
+func (o *Orchestrator) runExposeMode(ctx context.Context) error {
 	cliCfg := o.AppCfg.Cli
-	fmt.Printf("\nStarting Exposure Phase for %d epochs (BaseLearningRate: %.4f, CyclesPerPattern: %d)...\n",
-		cliCfg.Epochs, cliCfg.BaseLearningRate, cliCfg.CyclesPerPattern)
+	o.log.Info("Starting exposure", "epochs", cliCfg.Epochs,
+		"base_learning_rate", float64(cliCfg.BaseLearningRate), "cycles_per_pattern", cliCfg.CyclesPerPattern)
 
 	// Attempt to load weights; if not found, network uses random weights (normal for initial training).
-	if err := o.loadWeights(cliCfg.WeightsFile); err != nil {
-		// Log the error but continue, as starting from random weights is acceptable.
-		fmt.Printf("Note: Could not load weights from %s (%v), starting with new/random weights.\n", cliCfg.WeightsFile, err)
+	// A network built from a model bundle already carries its weights.
+	if o.model == nil {
+		if err := o.loadWeights(cliCfg.WeightsFile); err != nil {
+			// Log the error but continue, as starting from random weights is acceptable.
+			o.log.Info("Could not load weights, starting with new random weights", "path", cliCfg.WeightsFile, "err", err)
+		}
 	}
 
 	o.Net.SetDynamicState(true, true, true) // Neurochemicals, learning, synaptogenesis active
 
-	if err := o.runExposureEpochs(); err != nil {
+	if err := o.runExposureEpochs(ctx); err != nil {
+		if errors.Is(err, ErrInterrupted) {
+			return o.saveRecovery(err)
+		}
 		return fmt.Errorf("error during exposure epochs: %w", err)
 	}
 
-	fmt.Println("Exposure phase completed.")
+	o.log.Info("Exposure completed")
 	if err := o.saveWeights(cliCfg.WeightsFile); err != nil {
 		return err // Error saving weights is critical after training
 	}
+	if cliCfg.ModelFile != "" {
+		if err := o.saveModel(cliCfg.ModelFile); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// RecoveryPath returns the file an interrupted expose run saves weightsFile (or a
+// model file) to: the name with ".interrupted" before its format extension, e.g.
+// weights.json -> weights.interrupted.json and weights.bin.gz -> weights.interrupted.bin.gz.
+// Saving there leaves the weights of the last completed run untouched.
+func RecoveryPath(weightsFile string) string {
+	base, gz := weightsFile, ""
+	if strings.HasSuffix(base, ".gz") {
+		base, gz = strings.TrimSuffix(base, ".gz"), ".gz"
+	}
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + ".interrupted" + ext + gz
+}
+
+// saveRecovery saves the weights (and the model, if ModelFile is set) of an interrupted
+// expose run to their recovery paths and returns errInterrupted, annotated with them.
+func (o *Orchestrator) saveRecovery(errInterrupted error) error {
+	cliCfg := o.AppCfg.Cli
+	o.log.Warn("Exposure interrupted, saving the partially trained network", "cycles_completed", int(o.Net.CycleCount))
+	weightsPath := RecoveryPath(cliCfg.WeightsFile)
+	if err := o.saveWeights(weightsPath); err != nil {
+		return fmt.Errorf("%w; saving recovery weights failed: %v", errInterrupted, err)
+	}
+	saved := "weights saved to " + weightsPath
+	if cliCfg.ModelFile != "" {
+		modelPath := RecoveryPath(cliCfg.ModelFile)
+		if err := o.saveModel(modelPath); err != nil {
+			return fmt.Errorf("%w; %s, saving recovery model failed: %v", errInterrupted, saved, err)
+		}
+		saved += ", model saved to " + modelPath
+	}
+	return fmt.Errorf("%w; %s", errInterrupted, saved)
+}
+
 // runObservationPattern presents a single pattern and runs the network for settling cycles.
-func (o *Orchestrator) runObservationPattern() ([]float64, error) {
+func (o *Orchestrator) runObservationPattern(ctx context.Context) ([]float64, error) {
 	cliCfg := o.AppCfg.Cli
 	patternToObserve, err := datagen.GetDigitPatternFn(cliCfg.Digit, &o.AppCfg.SimParams)
 	if err != nil {
@@ -495,6 +860,9 @@ func (o *Orchestrator) runObservationPattern() ([]float64, error) {
 	}
 
 	for i := 0; i < cliCfg.CyclesToSettle; i++ {
+		if err := o.interrupted(ctx); err != nil {
+			return nil, err
+		}
 		o.Net.RunCycle()
 	}
 
@@ -560,21 +928,22 @@ func (o *Orchestrator) displayOutputActivation(outputActivation []float64) {
 }
 
 // runObserveMode handles the 'observe' execution mode.
-func (o *Orchestrator) runObserveMode() error {
+func (o *Orchestrator) runObserveMode(ctx context.Context) error {
 	cliCfg := o.AppCfg.Cli
-	fmt.Printf("\nObserving Network Response for digit %d (%d settling cycles)...\n",
-		cliCfg.Digit, cliCfg.CyclesToSettle)
+	o.log.Info("Observing network response", "digit", cliCfg.Digit, "cycles_to_settle", cliCfg.CyclesToSettle)
 
-	// Loading weights is critical for observe mode.
-	if err := o.loadWeights(cliCfg.WeightsFile); err != nil {
-		return fmt.Errorf("failed to load weights for observe mode from %s: %w. Expose/train the network first",
-			cliCfg.WeightsFile, err)
+	// Loading weights is critical for observe mode, unless the network was built from a model bundle.
+	if o.model == nil {
+		if err := o.loadWeights(cliCfg.WeightsFile); err != nil {
+			return fmt.Errorf("failed to load weights for observe mode from %s: %w. Expose/train the network first",
+				cliCfg.WeightsFile, err)
+		}
 	}
 
 	// Disable dynamics that would alter the network state during observation.
 	o.Net.SetDynamicState(false, false, false)
 
-	outputActivation, err := o.runObservationPattern()
+	outputActivation, err := o.runObservationPattern(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to run observation pattern: %w", err)
 	}
@@ -623,12 +992,12 @@ func (o *Orchestrator) SetupContinuousInputStimulusForTest() error {
 
 // RunObserveModeForTest wraps runObserveMode for testing.
 func (o *Orchestrator) RunObserveModeForTest() error {
-	return o.runObserveMode()
+	return o.runObserveMode(context.Background())
 }
 
 // RunExposeModeForTest wraps runExposeMode for testing.
 func (o *Orchestrator) RunExposeModeForTest() error {
-	return o.runExposeMode()
+	return o.runExposeMode(context.Background())
 }
 
 // SetLoadWeightsFn allows tests to inject a mock loadWeightsFn.
@@ -649,13 +1018,13 @@ func (o *Orchestrator) InitializeLoggerForTest() error {
 }
 
 // CreateNetworkForTest wraps createNetwork for testing.
-func (o *Orchestrator) CreateNetworkForTest() {
-	o.createNetwork()
+func (o *Orchestrator) CreateNetworkForTest() error {
+	return o.createNetwork()
 }
 
 // RunSimModeForTest wraps runSimMode for testing.
 func (o *Orchestrator) RunSimModeForTest() error {
-	return o.runSimMode()
+	return o.runSimMode(context.Background())
 }
 
 // CloseLoggerForTest wraps closing the logger, for testing.
@@ -676,7 +1045,6 @@ func (o *Orchestrator) LoadWeightsForTest(filepath string) error {
 
 // runLogUtilMode handles the 'logutil' execution mode (FEATURE-004).
 func (o *Orchestrator) runLogUtilMode() error {
-	fmt.Println("\nCrowNet Log Utility...")
 	cliCfg := &o.AppCfg.Cli
 
 	// Path validation for LogUtilDbPath (read-only for export)
@@ -692,15 +1060,12 @@ func (o *Orchestrator) runLogUtilMode() error {
 	// We use cliCfg.LogUtilDbPath directly in the call to exporter,
 	// as validatePath was just for the check here. The exporter will use the raw path.
 
-	fmt.Printf("  Subcommand: %s\n", cliCfg.LogUtilSubcommand)
-	fmt.Printf("  Database: %s\n", cliCfg.LogUtilDbPath)
-	fmt.Printf("  Table: %s\n", cliCfg.LogUtilTable)
-	fmt.Printf("  Format: %s\n", cliCfg.LogUtilFormat)
-	if cliCfg.LogUtilOutput != "" {
-		fmt.Printf("  Output: %s\n", cliCfg.LogUtilOutput)
-	} else {
-		fmt.Println("  Output: stdout")
+	output := cliCfg.LogUtilOutput
+	if output == "" {
+		output = "stdout"
 	}
+	o.log.Info("CrowNet log utility", "subcommand", cliCfg.LogUtilSubcommand, "db_path", cliCfg.LogUtilDbPath,
+		"table", cliCfg.LogUtilTable, "format", cliCfg.LogUtilFormat, "run", cliCfg.LogUtilRun, "output", output)
 
 	if cliCfg.LogUtilSubcommand == "export" {
 		// Call the main export function (to be created in storage package)
@@ -709,11 +1074,12 @@ func (o *Orchestrator) runLogUtilMode() error {
 			cliCfg.LogUtilTable,
 			cliCfg.LogUtilFormat,
 			cliCfg.LogUtilOutput,
+			cliCfg.LogUtilRun,
 		)
 		if err != nil {
 			return fmt.Errorf("log export failed: %w", err)
 		}
-		fmt.Println("Log export completed successfully.")
+		o.log.Info("Log export completed")
 		return nil
 	}
 	// Should be caught by validation, but as a safeguard:
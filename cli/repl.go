@@ -0,0 +1,454 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/datagen"
+	"crownet/network"
+)
+
+// maxListedIDs limits how many neuron IDs are printed in one line of REPL output.
+const maxListedIDs = 20
+
+// replHelp lists the commands understood by Repl.Execute.
+const replHelp = `Commands:
+  step [n]                          Run n cycles (default 1)
+  present <digit>                   Reset transient activity and present a digit pattern (0-9)
+  freq <input-id> <hz>              Stimulate an input neuron at a frequency (0 stops it)
+  reset                             Clear accumulated potentials and active pulses
+  neuron <id>                       Show a neuron's state, potential, threshold and synapse counts
+  weights <id> [out|in]             List a neuron's outgoing (default) or incoming weights
+  outputs                           Show output neuron potentials and firing frequencies
+  chem                              Show neurochemical levels and modulation factors
+  dynamics [<name> on|off]          Show or toggle learning, synaptogenesis, chemical (or all)
+  save <file>                       Save synaptic weights (and neuron parameters)
+  load <file>                       Load synaptic weights (and neuron parameters)
+  source <file>                     Run the commands of a script file
+  history [save <file>]             Show the commands run so far, or save them as a script
+  help                              Show this help
+  quit                              Leave the REPL
+Lines starting with '#' are comments.
+`
+
+// Repl is an interactive shell around a live network, for stepping through its
+// dynamics one command at a time. Commands read from scripts and typed at the
+// prompt share the same history, which can be saved and replayed with 'source'.
+type Repl struct {
+	o       *Orchestrator
+	out     io.Writer
+	history []string
+}
+
+// NewRepl creates a network from appCfg and a REPL that controls it, writing
+// command output to out. Learning, synaptogenesis and chemical modulation start
+// enabled, as in sim mode. If appCfg.Cli.WeightsFile names an existing file, its
+// weights are loaded.
+func NewRepl(appCfg *config.AppConfig, out io.Writer) (*Repl, error) {
+	net, err := network.NewCrowNet(appCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network: %w", err)
+	}
+	o := NewOrchestrator(appCfg)
+	o.Net = net
+	o.Net.SetDynamicState(true, true, true)
+	if appCfg.Cli.WeightsFile != "" {
+		if _, errStat := os.Stat(appCfg.Cli.WeightsFile); errStat == nil {
+			if err := o.loadWeights(appCfg.Cli.WeightsFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+	fmt.Fprintf(out, "Network ready: %d neurons (%d input, %d output), %s topology with %d connections.\n",
+		len(net.Neurons), len(net.InputNeuronIDs), len(net.OutputNeuronIDs),
+		net.TopologyStats.Generator, net.TopologyStats.Connections)
+	return &Repl{o: o, out: out}, nil
+}
+
+// Net returns the network controlled by the REPL.
+func (r *Repl) Net() *network.CrowNet {
+	return r.o.Net
+}
+
+// History returns the commands executed so far, in order, as they would be
+// written to a script.
+func (r *Repl) History() []string {
+	return append([]string(nil), r.history...)
+}
+
+// Run reads commands from in until EOF or 'quit'. In interactive mode a prompt is
+// printed before each command and errors are reported without ending the session;
+// otherwise the first error stops Run and is returned with its line number.
+func (r *Repl) Run(in io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(in)
+	lineNo := 0
+	for {
+		if interactive {
+			fmt.Fprint(r.out, "crownet> ")
+		}
+		if !scanner.Scan() {
+			if interactive {
+				fmt.Fprintln(r.out)
+			}
+			return scanner.Err()
+		}
+		lineNo++
+		quit, err := r.Execute(scanner.Text())
+		if err != nil {
+			if !interactive {
+				return fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			fmt.Fprintf(r.out, "Error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// RunScript executes the commands of the file at path, stopping at the first
+// error. It reports whether the script ended with 'quit'.
+func (r *Repl) RunScript(path string) (quit bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open script %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		quit, err := r.Execute(scanner.Text())
+		if err != nil {
+			return false, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		if quit {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// Execute runs a single command line and reports whether it asked to quit.
+// Blank lines and comments are ignored. Successful commands that act on the
+// network are appended to the history.
+func (r *Repl) Execute(line string) (quit bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false, nil
+	}
+	args := strings.Fields(line)
+	command, args := strings.ToLower(args[0]), args[1:]
+
+	switch command {
+	case "quit", "exit":
+		return true, nil
+	case "help":
+		fmt.Fprint(r.out, replHelp)
+		return false, nil
+	case "history":
+		return false, r.cmdHistory(args)
+	case "source":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: source <file>")
+		}
+		return r.RunScript(args[0])
+	}
+
+	switch command {
+	case "step":
+		err = r.cmdStep(args)
+	case "present":
+		err = r.cmdPresent(args)
+	case "freq":
+		err = r.cmdFreq(args)
+	case "reset":
+		r.o.Net.ResetNetworkStateForNewPattern()
+		fmt.Fprintln(r.out, "Accumulated potentials and active pulses cleared.")
+	case "neuron":
+		err = r.cmdNeuron(args)
+	case "weights":
+		err = r.cmdWeights(args)
+	case "outputs":
+		err = r.cmdOutputs()
+	case "chem":
+		r.cmdChem()
+	case "dynamics":
+		err = r.cmdDynamics(args)
+	case "save":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: save <file>")
+		}
+		err = r.o.saveWeights(args[0])
+	case "load":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: load <file>")
+		}
+		err = r.o.loadWeights(args[0])
+	default:
+		return false, fmt.Errorf("unknown command '%s' (type 'help' for the list of commands)", command)
+	}
+	if err != nil {
+		return false, err
+	}
+	r.history = append(r.history, line)
+	return false, nil
+}
+
+func (r *Repl) cmdStep(args []string) error {
+	n := 1
+	if len(args) > 1 {
+		return fmt.Errorf("usage: step [n]")
+	}
+	if len(args) == 1 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n <= 0 {
+			return fmt.Errorf("step: invalid number of cycles '%s'", args[0])
+		}
+	}
+	firings := 0
+	for i := 0; i < n; i++ {
+		r.o.Net.RunCycle()
+		firings += len(r.o.Net.FiredLastCycle())
+	}
+	net := r.o.Net
+	fmt.Fprintf(r.out, "Ran %d cycle(s), now at cycle %d: %d firing(s), %d active pulse(s), cortisol %.3f, dopamine %.3f.\n",
+		n, net.CycleCount, firings, len(net.ActivePulses.GetAll()),
+		net.ChemicalEnv.CortisolLevel, net.ChemicalEnv.DopamineLevel)
+	fmt.Fprintf(r.out, "Fired in the last cycle: %s\n", formatNeuronIDs(net.FiredLastCycle()))
+	return nil
+}
+
+func (r *Repl) cmdPresent(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: present <digit>")
+	}
+	digit, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("present: invalid digit '%s'", args[0])
+	}
+	pattern, err := datagen.GetDigitPatternFn(digit, &r.o.AppCfg.SimParams)
+	if err != nil {
+		return fmt.Errorf("failed to get pattern for digit %d: %w", digit, err)
+	}
+	r.o.Net.ResetNetworkStateForNewPattern()
+	if err := r.o.Net.PresentPattern(pattern); err != nil {
+		return fmt.Errorf("failed to present pattern for digit %d: %w", digit, err)
+	}
+	fmt.Fprintf(r.out, "Digit %d presented to the input neurons.\n", digit)
+	return nil
+}
+
+func (r *Repl) cmdFreq(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: freq <input-id> <hz>")
+	}
+	id, err := parseNeuronID(args[0])
+	if err != nil {
+		return err
+	}
+	hz, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return fmt.Errorf("freq: invalid frequency '%s'", args[1])
+	}
+	if err := r.o.Net.ConfigureFrequencyInput(id, hz); err != nil {
+		return err
+	}
+	if hz <= 0 {
+		fmt.Fprintf(r.out, "Stimulus of input neuron %d stopped.\n", id)
+	} else {
+		fmt.Fprintf(r.out, "Input neuron %d stimulated at %.2f Hz.\n", id, hz)
+	}
+	return nil
+}
+
+func (r *Repl) cmdNeuron(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: neuron <id>")
+	}
+	id, err := parseNeuronID(args[0])
+	if err != nil {
+		return err
+	}
+	n, ok := r.o.Net.GetNeuron(id)
+	if !ok {
+		return fmt.Errorf("neuron %d not found", id)
+	}
+	outgoing, incoming := 0, 0
+	for from, targets := range r.o.Net.SynapticWeights.GetAllWeights() {
+		if from == id {
+			outgoing = len(targets)
+		}
+		if _, ok := targets[id]; ok {
+			incoming++
+		}
+	}
+	lastFired := "never fired"
+	if n.LastFiredCycle >= 0 {
+		lastFired = fmt.Sprintf("last fired at cycle %d", n.LastFiredCycle)
+	}
+	fmt.Fprintf(r.out, "Neuron %d (%s): %s for %d cycle(s), %s\n",
+		n.ID, n.Type, n.CurrentState, n.CyclesInCurrentState, lastFired)
+	fmt.Fprintf(r.out, "  Potential %.4f, threshold %.4f (base %.4f), decay rate %.4f\n",
+		n.AccumulatedPotential, n.CurrentFiringThreshold, n.BaseFiringThreshold, n.DecayRate)
+	fmt.Fprintf(r.out, "  Refractory cycles: %d absolute, %d relative\n",
+		n.AbsoluteRefractoryCycles, n.RelativeRefractoryCycles)
+	fmt.Fprintf(r.out, "  Synapses: %d outgoing, %d incoming (see 'weights %d out|in')\n", outgoing, incoming, id)
+	return nil
+}
+
+func (r *Repl) cmdWeights(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: weights <id> [out|in]")
+	}
+	id, err := parseNeuronID(args[0])
+	if err != nil {
+		return err
+	}
+	if _, ok := r.o.Net.GetNeuron(id); !ok {
+		return fmt.Errorf("neuron %d not found", id)
+	}
+	direction := "out"
+	if len(args) == 2 {
+		direction = strings.ToLower(args[1])
+	}
+
+	all := r.o.Net.SynapticWeights.GetAllWeights()
+	var weights map[common.NeuronID]common.SynapticWeight
+	var label, format string
+	switch direction {
+	case "out":
+		weights = all[id]
+		label, format = "outgoing", "  %d -> %d: %.4f\n"
+	case "in":
+		weights = make(map[common.NeuronID]common.SynapticWeight)
+		for from, targets := range all {
+			if w, ok := targets[id]; ok {
+				weights[from] = w
+			}
+		}
+		label, format = "incoming", "  %d <- %d: %.4f\n"
+	default:
+		return fmt.Errorf("weights: direction must be 'out' or 'in', got '%s'", args[1])
+	}
+
+	ids := make([]common.NeuronID, 0, len(weights))
+	for other := range weights {
+		ids = append(ids, other)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	fmt.Fprintf(r.out, "%d %s synapse(s) of neuron %d:\n", len(ids), label, id)
+	for _, other := range ids {
+		fmt.Fprintf(r.out, format, id, other, weights[other])
+	}
+	return nil
+}
+
+func (r *Repl) cmdOutputs() error {
+	activations, err := r.o.Net.GetOutputActivation()
+	if err != nil {
+		return err
+	}
+	for i, potential := range activations {
+		id := r.o.Net.OutputNeuronIDs[i]
+		freq, err := r.o.Net.GetOutputFrequency(id)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(r.out, "  Output[%d] (ID %d): potential %.4f, frequency %.2f Hz\n", i, id, potential, freq)
+	}
+	return nil
+}
+
+func (r *Repl) cmdChem() {
+	env := r.o.Net.ChemicalEnv
+	fmt.Fprintf(r.out, "Cortisol %.4f, dopamine %.4f, learning rate modulation %.4f, synaptogenesis modulation %.4f\n",
+		env.CortisolLevel, env.DopamineLevel, env.LearningRateModulationFactor, env.SynaptogenesisModulationFactor)
+}
+
+func (r *Repl) cmdDynamics(args []string) error {
+	learning, synaptogenesis, chemical := r.o.Net.DynamicState()
+	if len(args) != 0 {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: dynamics [learning|synaptogenesis|chemical|all on|off]")
+		}
+		var enabled bool
+		switch strings.ToLower(args[1]) {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			return fmt.Errorf("dynamics: expected 'on' or 'off', got '%s'", args[1])
+		}
+		switch strings.ToLower(args[0]) {
+		case "learning":
+			learning = enabled
+		case "synaptogenesis":
+			synaptogenesis = enabled
+		case "chemical":
+			chemical = enabled
+		case "all":
+			learning, synaptogenesis, chemical = enabled, enabled, enabled
+		default:
+			return fmt.Errorf("dynamics: unknown process '%s' (learning, synaptogenesis, chemical or all)", args[0])
+		}
+		r.o.Net.SetDynamicState(learning, synaptogenesis, chemical)
+	}
+	fmt.Fprintf(r.out, "Learning: %s, synaptogenesis: %s, chemical modulation: %s\n",
+		onOff(learning), onOff(synaptogenesis), onOff(chemical))
+	return nil
+}
+
+func (r *Repl) cmdHistory(args []string) error {
+	switch {
+	case len(args) == 0:
+		for i, line := range r.history {
+			fmt.Fprintf(r.out, "%4d  %s\n", i+1, line)
+		}
+		return nil
+	case len(args) == 2 && args[0] == "save":
+		data := strings.Join(r.history, "\n")
+		if data != "" {
+			data += "\n"
+		}
+		if err := os.WriteFile(args[1], []byte(data), 0644); err != nil {
+			return fmt.Errorf("failed to save history to %s: %w", args[1], err)
+		}
+		fmt.Fprintf(r.out, "%d command(s) saved to %s (replay with 'source %s').\n", len(r.history), args[1], args[1])
+		return nil
+	}
+	return fmt.Errorf("usage: history [save <file>]")
+}
+
+func parseNeuronID(s string) (common.NeuronID, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid neuron ID '%s'", s)
+	}
+	return common.NeuronID(id), nil
+}
+
+// formatNeuronIDs formats ids as a list, truncated to maxListedIDs entries.
+func formatNeuronIDs(ids []common.NeuronID) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	if len(ids) > maxListedIDs {
+		return fmt.Sprintf("%v ... (%d in total)", ids[:maxListedIDs], len(ids))
+	}
+	return fmt.Sprintf("%v", ids)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
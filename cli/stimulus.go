@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/datagen"
+	"crownet/network"
+)
+
+// stimulusRunner applies a config.StimulusSchedule to a network, one cycle at a time,
+// through ConfigureFrequencyInput and PresentPattern.
+type stimulusRunner struct {
+	schedule *config.StimulusSchedule
+	net      *network.CrowNet
+	log      *slog.Logger
+	inputs   [][]common.NeuronID // Input neurons driven by each event, resolved from its indices.
+	patterns [][]float64         // Pattern presented by each pattern event.
+	// applied holds the frequency currently configured on each input neuron, so that
+	// ConfigureFrequencyInput (which restarts the neuron's firing timer) is only called
+	// when it changes.
+	applied map[common.NeuronID]float64
+}
+
+// newStimulusRunner resolves the input indices and patterns of schedule against net.
+func newStimulusRunner(net *network.CrowNet, schedule *config.StimulusSchedule,
+	simParams *config.SimulationParameters, log *slog.Logger) (*stimulusRunner, error) {
+	r := &stimulusRunner{
+		schedule: schedule,
+		net:      net,
+		log:      log,
+		inputs:   make([][]common.NeuronID, len(schedule.Events)),
+		patterns: make([][]float64, len(schedule.Events)),
+		applied:  make(map[common.NeuronID]float64),
+	}
+	var digits map[int][]float64
+	for i, e := range schedule.Events {
+		switch e.Type {
+		case config.StimulusFrequency, config.StimulusRamp, config.StimulusBurst:
+			if len(e.Inputs) == 0 {
+				r.inputs[i] = net.InputNeuronIDs
+				continue
+			}
+			for _, idx := range e.Inputs {
+				if idx >= len(net.InputNeuronIDs) {
+					return nil, fmt.Errorf("stimulus event %d: input index %d out of range, the network has %d input neurons",
+						i+1, idx, len(net.InputNeuronIDs))
+				}
+				r.inputs[i] = append(r.inputs[i], net.InputNeuronIDs[idx])
+			}
+		case config.StimulusPattern:
+			if e.Digit == nil {
+				if len(e.Pattern) != simParams.Pattern.PatternSize {
+					return nil, fmt.Errorf("stimulus event %d: pattern has %d values, expected PatternSize (%d)",
+						i+1, len(e.Pattern), simParams.Pattern.PatternSize)
+				}
+				r.patterns[i] = e.Pattern
+				continue
+			}
+			if digits == nil {
+				var err error
+				if digits, err = datagen.GetAllDigitPatterns(simParams); err != nil {
+					return nil, fmt.Errorf("failed to load digit patterns for the stimulus schedule: %w", err)
+				}
+			}
+			r.patterns[i] = digits[*e.Digit]
+		}
+	}
+	return r, nil
+}
+
+// apply configures the input neurons for the cycle-th cycle of the run, which is
+// about to be run.
+func (r *stimulusRunner) apply(cycle int) error {
+	resting := false
+	for i := range r.schedule.Events {
+		e := &r.schedule.Events[i]
+		if e.ActiveAt(cycle) && e.Type == config.StimulusRest {
+			resting = true
+		}
+		if cycle == e.Start {
+			r.log.Debug("Stimulus event started", "event", i+1, "type", e.Type, "cycle", cycle)
+		}
+	}
+
+	target := make(map[common.NeuronID]float64)
+	if !resting {
+		for i := range r.schedule.Events {
+			e := &r.schedule.Events[i]
+			if r.inputs[i] == nil || !e.ActiveAt(cycle) {
+				continue
+			}
+			hz := e.HzAt(cycle)
+			for _, id := range r.inputs[i] {
+				target[id] = hz
+			}
+		}
+	}
+	if err := r.configureFrequencies(target); err != nil {
+		return fmt.Errorf("stimulus at cycle %d: %w", cycle, err)
+	}
+
+	if resting {
+		return nil
+	}
+	for i := range r.schedule.Events {
+		e := &r.schedule.Events[i]
+		if !e.PresentsAt(cycle) {
+			continue
+		}
+		if e.Reset {
+			r.net.ResetNetworkStateForNewPattern()
+		}
+		if err := r.net.PresentPattern(r.patterns[i]); err != nil {
+			return fmt.Errorf("stimulus event %d at cycle %d: %w", i+1, cycle, err)
+		}
+	}
+	return nil
+}
+
+// configureFrequencies sets the frequency of every input neuron in target, where 0
+// silences it, and silences the neurons driven on the previous cycle that target
+// leaves out. Neurons are configured in ID order because ConfigureFrequencyInput
+// draws from the network's random number generator.
+func (r *stimulusRunner) configureFrequencies(target map[common.NeuronID]float64) error {
+	for id := range r.applied {
+		if _, ok := target[id]; !ok {
+			target[id] = 0
+		}
+	}
+	ids := make([]common.NeuronID, 0, len(target))
+	for id := range target {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+
+	for _, id := range ids {
+		hz := target[id]
+		if hz == r.applied[id] {
+			continue
+		}
+		if err := r.net.ConfigureFrequencyInput(id, hz); err != nil {
+			return fmt.Errorf("failed to configure frequency input for neuron %d at %.1f Hz: %w", id, hz, err)
+		}
+		if hz > 0 {
+			r.applied[id] = hz
+		} else {
+			delete(r.applied, id)
+		}
+	}
+	return nil
+}
+
+// setupStimulusSchedule loads the stimulus schedule named by StimulusFile, if any,
+// for the sim loop to apply before every cycle.
+func (o *Orchestrator) setupStimulusSchedule() error {
+	path := o.AppCfg.Cli.StimulusFile
+	if path == "" {
+		return nil
+	}
+	schedule, err := config.LoadStimulusSchedule(path)
+	if err != nil {
+		return err
+	}
+	runner, err := newStimulusRunner(o.Net, schedule, &o.AppCfg.SimParams, o.log)
+	if err != nil {
+		return err
+	}
+	o.stimulus = runner
+	o.log.Info("Stimulus schedule loaded", "path", path, "events", len(schedule.Events))
+	return nil
+}
+
+// applyStimulus applies the stimulus schedule, if any, for the cycle-th cycle of the run.
+func (o *Orchestrator) applyStimulus(cycle int) error {
+	if o.stimulus == nil {
+		return nil
+	}
+	return o.stimulus.apply(cycle)
+}
@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"time"
+
+	"crownet/config"
+	"crownet/datagen"
+	"crownet/network"
+	"crownet/storage"
+)
+
+// defaultEvalCycles is the number of settling cycles per digit when evaluating a
+// sweep run whose configuration sets no CyclesToSettle.
+const defaultEvalCycles = 50
+
+// SweepConfig is one sampled point of a sweep: a value for every swept parameter.
+type SweepConfig []storage.SweepParamValue
+
+// SweepConfigs returns the configurations a sweep runs, in order: every combination
+// of the parameters' values for grid sampling, or spec.Samples random draws made
+// with a generator seeded by seed.
+func SweepConfigs(spec *config.SweepSpec, seed int64) ([]SweepConfig, error) {
+	probe := &config.AppConfig{SimParams: config.DefaultSimulationParameters()}
+	rng := rand.New(rand.NewSource(seed))
+	isInt := make([]bool, len(spec.Params))
+	for i, p := range spec.Params {
+		kind, err := probe.FieldKind(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			isInt[i] = true
+		}
+	}
+
+	if spec.Sampling == config.SweepRandom {
+		configs := make([]SweepConfig, spec.Samples)
+		for s := range configs {
+			for i, p := range spec.Params {
+				var v any
+				if len(p.Values) > 0 {
+					v = p.Values[rng.Intn(len(p.Values))]
+				} else {
+					v = roundIf(isInt[i], interpolate(*p.Min, *p.Max, rng.Float64(), p.Log))
+				}
+				configs[s] = append(configs[s], storage.SweepParamValue{Name: p.Name, Value: v})
+			}
+		}
+		return configs, nil
+	}
+
+	// Grid: expand ranges into points, then take the cartesian product.
+	configs := []SweepConfig{nil}
+	for i, p := range spec.Params {
+		values := p.Values
+		if len(values) == 0 {
+			values = gridPoints(*p.Min, *p.Max, p.Steps, p.Log, isInt[i])
+		}
+		expanded := make([]SweepConfig, 0, len(configs)*len(values))
+		for _, c := range configs {
+			for _, v := range values {
+				next := append(append(SweepConfig(nil), c...), storage.SweepParamValue{Name: p.Name, Value: v})
+				expanded = append(expanded, next)
+			}
+		}
+		configs = expanded
+	}
+	return configs, nil
+}
+
+// gridPoints returns steps evenly spaced points from min to max (inclusive), in log
+// scale if logScale, with duplicates removed after rounding integer fields.
+func gridPoints(min, max float64, steps int, logScale, isInt bool) []any {
+	var points []any
+	seen := make(map[float64]bool)
+	for s := 0; s < steps; s++ {
+		t := 0.0
+		if steps > 1 {
+			t = float64(s) / float64(steps-1)
+		}
+		v := interpolate(min, max, t, logScale)
+		if isInt {
+			v = math.Round(v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			points = append(points, roundIf(isInt, v))
+		}
+	}
+	return points
+}
+
+// interpolate returns the point at fraction t of the way from min to max, in log
+// scale if logScale. It is written so that t = 0 and t = 1 give min and max exactly.
+func interpolate(min, max, t float64, logScale bool) float64 {
+	if logScale {
+		return math.Exp((1-t)*math.Log(min) + t*math.Log(max))
+	}
+	return (1-t)*min + t*max
+}
+
+func roundIf(isInt bool, v float64) any {
+	if isInt {
+		return int64(math.Round(v))
+	}
+	return v
+}
+
+// RunSweep runs every configuration of spec (see SweepConfigs) spec.Repeats times,
+// spec.Parallel runs at a time, each on its own copy of base and its own network.
+// Runs never log to SQLite or read or write weight and model files; sim runs apply
+// the stimulus schedule of base, if any. Results are returned in configuration order;
+// progress, if not nil, is called from the calling goroutine as each run finishes. A
+// run that fails records its error in the result instead of stopping the sweep.
+func RunSweep(spec *config.SweepSpec, base *config.AppConfig, progress func(storage.SweepResult)) ([]storage.SweepResult, error) {
+	seed := spec.Seed
+	if seed == 0 {
+		seed = base.Cli.Seed
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano() // One seed for the whole sweep, so runs stay comparable.
+	}
+	configs, err := SweepConfigs(spec, seed)
+	if err != nil {
+		return nil, err
+	}
+	repeats := spec.Repeats
+	if repeats == 0 {
+		repeats = 1
+	}
+	mode := spec.Mode
+	if mode == "" {
+		mode = config.ModeExpose
+	}
+
+	results := make([]storage.SweepResult, 0, len(configs)*repeats)
+	for i, c := range configs {
+		for r := 0; r < repeats; r++ {
+			results = append(results, storage.SweepResult{Index: i + 1, Repeat: r, Seed: seed + int64(r), Params: c})
+		}
+	}
+
+	parallel := spec.Parallel
+	if parallel == 0 {
+		parallel = runtime.NumCPU()
+	}
+	parallel = minInt(parallel, len(results))
+
+	jobs := make(chan int)
+	done := make(chan int)
+	for w := 0; w < parallel; w++ {
+		go func() {
+			for i := range jobs {
+				runSweepJob(base, mode, &results[i])
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range results {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+	for range results {
+		i := <-done
+		if progress != nil {
+			progress(results[i])
+		}
+	}
+	return results, nil
+}
+
+// runSweepJob runs one configuration and fills in the metrics of result.
+func runSweepJob(base *config.AppConfig, mode string, result *storage.SweepResult) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Error = fmt.Sprintf("panic: %v", r)
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	// A deep copy: jobs run in parallel, and the rules, overrides and repeats of the
+	// configuration are slices that a shallow copy would share between them.
+	cfg := *base.Clone()
+	for _, p := range result.Params {
+		if err := cfg.SetField(p.Name, p.Value); err != nil {
+			result.Error = err.Error()
+			return
+		}
+	}
+	cfg.Cli.Mode = mode
+	cfg.Cli.Seed = result.Seed
+	cfg.Cli.DbPath = ""
+	cfg.Cli.ModelFile = ""
+	cfg.Cli.SaveInterval = 0
+	cfg.Cli.LogSpikes = false
+	cfg.Cli.SynapseLogInterval = 0
+	cfg.Cli.StreamAddr = ""
+	cfg.Cli.MetricsAddr = ""
+	if err := cfg.Validate(); err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	net, err := network.NewCrowNet(&cfg)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create network: %v", err)
+		return
+	}
+	o := NewOrchestrator(&cfg)
+	o.Net = net
+	o.SetLog(slog.New(slog.DiscardHandler)) // Parallel jobs would interleave their progress records.
+
+	switch mode {
+	case config.ModeExpose:
+		o.Net.SetDynamicState(true, true, true)
+		if err := o.runExposureEpochs(context.Background()); err != nil {
+			result.Error = err.Error()
+			return
+		}
+		winners, err := o.evaluateDigits()
+		if err != nil {
+			result.Error = err.Error()
+			return
+		}
+		result.DistinctWinnersFrac = &winners
+	case config.ModeSim:
+		if err := o.setupContinuousInputStimulus(); err != nil {
+			result.Error = err.Error()
+			return
+		}
+		if err := o.setupStimulusSchedule(); err != nil {
+			result.Error = err.Error()
+			return
+		}
+		o.Net.SetDynamicState(true, true, true)
+		if err := o.runSimulationLoop(context.Background()); err != nil {
+			result.Error = err.Error()
+			return
+		}
+	}
+
+	result.Cortisol = float64(o.Net.ChemicalEnv.CortisolLevel)
+	result.Dopamine = float64(o.Net.ChemicalEnv.DopamineLevel)
+	result.LRModFactor = float64(o.Net.ChemicalEnv.LearningRateModulationFactor)
+	var sum float64
+	var count int
+	for _, toMap := range o.Net.SynapticWeights.GetAllWeights() {
+		for _, w := range toMap {
+			sum += math.Abs(float64(w))
+			count++
+		}
+	}
+	if count > 0 {
+		result.MeanAbsWeight = sum / float64(count)
+	}
+}
+
+// evaluateDigits presents each digit with learning, neurochemicals and synaptogenesis
+// disabled, as observe does, and returns the number of distinct output neurons that
+// are the most active one for at least one digit, divided by 10. It is not an accuracy
+// against labels, but it bounds the accuracy of any one-to-one labelling of output
+// neurons with digits: an output that wins several digits can be labelled with only
+// one of them. Digits that leave all outputs
+// equally active have no winner.
+func (o *Orchestrator) evaluateDigits() (float64, error) {
+	patterns, err := datagen.GetAllDigitPatterns(&o.AppCfg.SimParams)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load digit patterns: %w", err)
+	}
+	cycles := o.AppCfg.Cli.CyclesToSettle
+	if cycles <= 0 {
+		cycles = defaultEvalCycles
+	}
+
+	o.Net.SetDynamicState(false, false, false)
+	defer o.Net.SetDynamicState(true, true, true)
+	winners := make(map[int]bool) // Output indices that won at least one digit.
+	for digit := 0; digit <= 9; digit++ {
+		o.Net.ResetNetworkStateForNewPattern()
+		if err := o.Net.PresentPattern(patterns[digit]); err != nil {
+			return 0, fmt.Errorf("failed to present digit %d for evaluation: %w", digit, err)
+		}
+		for i := 0; i < cycles; i++ {
+			o.Net.RunCycle()
+		}
+		activation, err := o.Net.GetOutputActivation()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get output activation for digit %d: %w", digit, err)
+		}
+		best, tie := 0, true
+		for i, a := range activation {
+			if a > activation[best] {
+				best, tie = i, false
+			} else if i > 0 && a != activation[best] {
+				tie = false
+			}
+		}
+		if !tie {
+			winners[best] = true
+		}
+	}
+	return float64(len(winners)) / 10, nil
+}
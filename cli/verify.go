@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"crownet/config"
+	"crownet/network"
+)
+
+// VerifyResult describes the outcome of a reproducibility check.
+type VerifyResult struct {
+	CyclesCompared int    // Number of cycles (including the initial state) whose digests were compared.
+	Diverged       bool   // True if the two runs produced different state digests at some point.
+	DivergentCycle int    // Cycle at which the first mismatch was found (-1 for the initial state).
+	Component      string // Name of the first mismatching component (see network.DigestComponents).
+}
+
+// VerifyReproducibility builds two networks from the same configuration and runs
+// them in lockstep for appCfg.Cli.Cycles cycles in simulation mode (stimulus,
+// neurochemicals, learning and synaptogenesis active). After network creation and
+// after every cycle the state digests of both runs are compared; the first
+// mismatch is reported in the result. Neither run logs to SQLite or touches
+// weight files.
+func VerifyReproducibility(appCfg *config.AppConfig) (*VerifyResult, error) {
+	runs := make([]*Orchestrator, 2)
+	for i := range runs {
+		cfgCopy := appCfg.Clone() // Each run gets its own deep copy so neither can affect the other.
+		net, err := network.NewCrowNet(cfgCopy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create network for run %d: %w", i+1, err)
+		}
+		o := NewOrchestrator(cfgCopy)
+		o.Net = net
+		if err := o.setupContinuousInputStimulus(); err != nil {
+			return nil, fmt.Errorf("error in stimulus setup for run %d: %w", i+1, err)
+		}
+		if err := o.setupStimulusSchedule(); err != nil {
+			return nil, fmt.Errorf("error in stimulus schedule setup for run %d: %w", i+1, err)
+		}
+		o.Net.SetDynamicState(true, true, true)
+		runs[i] = o
+	}
+
+	result := &VerifyResult{}
+	compare := func(cycle int) bool {
+		result.CyclesCompared++
+		if component := runs[0].Net.Digest().Diff(runs[1].Net.Digest()); component != "" {
+			result.Diverged = true
+			result.DivergentCycle = cycle
+			result.Component = component
+			return false
+		}
+		return true
+	}
+
+	if !compare(-1) {
+		return result, nil
+	}
+	for cycle := 0; cycle < appCfg.Cli.Cycles; cycle++ {
+		for _, o := range runs {
+			if err := o.applyStimulus(cycle); err != nil {
+				return nil, err
+			}
+			o.Net.RunCycle()
+		}
+		if !compare(cycle) {
+			return result, nil
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"crownet/config"
+)
+
+// configCmd represents the base config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Utilitários para arquivos de configuração TOML.",
+	Long: `O comando config fornece subcomandos para inspecionar, validar, criar e
+converter os arquivos de configuração TOML usados com --configFile.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
+
+// configPath returns the configuration file named by the optional positional
+// argument, or by --configFile.
+func configPath(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return configFile
+}
+
+// describeUnknownKey formats an unknown configuration key, pointing legacy flat
+// [sim_params] keys to their place in the nested layout.
+func describeUnknownKey(k config.UnknownKey) string {
+	if k.NestedKey != "" {
+		return fmt.Sprintf("%s (layout antigo, agora %s)", k.Key, k.NestedKey)
+	}
+	return k.Key
+}
+
+// warnUnknownKeys logs the keys of a configuration file that were ignored.
+func warnUnknownKeys(filePath string, keys []config.UnknownKey) {
+	if len(keys) == 0 {
+		return
+	}
+	described := make([]string, len(keys))
+	for i, k := range keys {
+		described[i] = describeUnknownKey(k)
+	}
+	slog.Warn("Unknown configuration keys ignored; check them with 'crownet config validate'",
+		"path", filePath, "keys", strings.Join(described, ", "))
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"crownet/config"
+)
+
+var (
+	configInitOutput string
+	configInitForce  bool
+)
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Cria um arquivo de configuração com todos os valores padrão.",
+	Long: `Grava um arquivo de configuração TOML completo, com todos os campos nos seus
+valores padrão e um comentário explicando cada um. O arquivo pode ser editado e
+usado com --configFile; campos não alterados podem ser apagados.
+
+Um arquivo existente só é sobrescrito com --force. Use '--output -' para escrever
+na saída padrão.
+
+Exemplo:
+  crownet config init --output minha_config.toml`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		data := config.DefaultConfigTOML()
+		if configInitOutput == "-" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := writeNewFile(configInitOutput, data, configInitForce); err != nil {
+			return err
+		}
+		slog.Info("Default configuration written", "path", configInitOutput)
+		return nil
+	},
+}
+
+// writeNewFile writes data to path, refusing to replace an existing file unless force is set.
+func writeNewFile(path string, data []byte, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("o arquivo '%s' já existe; use --force para sobrescrevê-lo", path)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar '%s': %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configInitCmd)
+
+	configInitCmd.Flags().StringVarP(&configInitOutput, "output", "o", "crownet.toml",
+		"Arquivo de configuração a ser criado ('-' para a saída padrão).")
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false,
+		"Sobrescreve o arquivo de saída se ele já existir.")
+}
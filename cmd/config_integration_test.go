@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"crownet/config"
+)
+
+func TestConfigCommand_InitThenValidate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "crownet.toml")
+
+	rootCmd.SetArgs([]string{"config", "init", "--output", configPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init failed: %v", err)
+	}
+	rootCmd.SetArgs([]string{"config", "init", "--output", configPath})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("Expected config init to refuse to overwrite %s without --force", configPath)
+	}
+
+	rootCmd.SetArgs([]string{"config", "validate", configPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config validate failed on the generated default configuration: %v", err)
+	}
+}
+
+func TestConfigCommand_MigrateLegacyLayout(t *testing.T) {
+	tempDir := t.TempDir()
+	legacyPath := filepath.Join(tempDir, "legacy.toml")
+	migratedPath := filepath.Join(tempDir, "migrated.toml")
+	legacy := `[cli]
+TotalNeurons = 80
+cycles = 20
+
+[sim_params]
+space_max_dimension = 12.0
+hebbian_coincidence_window = 3
+`
+	if err := os.WriteFile(legacyPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy configuration: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"config", "validate", legacyPath})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "desconhecida") {
+		t.Fatalf("Expected config validate to reject the legacy layout, got %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"config", "migrate", "--input", legacyPath, "--output", migratedPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config migrate failed: %v", err)
+	}
+	rootCmd.SetArgs([]string{"config", "validate", migratedPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config validate failed on the migrated configuration: %v", err)
+	}
+
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	_, unknown, err := config.LoadFile(migratedPath, appCfg)
+	if err != nil {
+		t.Fatalf("Failed to load migrated configuration: %v", err)
+	}
+	if len(unknown) != 0 {
+		t.Errorf("Expected no unknown keys after migration, got %v", unknown)
+	}
+	if appCfg.Cli.TotalNeurons != 80 || appCfg.Cli.Cycles != 20 {
+		t.Errorf("Expected cli values 80 and 20, got %d and %d", appCfg.Cli.TotalNeurons, appCfg.Cli.Cycles)
+	}
+	if appCfg.SimParams.General.SpaceMaxDimension != 12.0 || appCfg.SimParams.Learning.HebbianCoincidenceWindow != 3 {
+		t.Errorf("Expected migrated sim_params values 12.0 and 3, got %v and %v",
+			appCfg.SimParams.General.SpaceMaxDimension, appCfg.SimParams.Learning.HebbianCoincidenceWindow)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+
+	"crownet/config"
+)
+
+var (
+	configMigrateInput  string
+	configMigrateOutput string
+	configMigrateForce  bool
+)
+
+// configMigrateCmd represents the config migrate command
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Converte um arquivo de configuração do layout antigo para o atual.",
+	Long: `Converte um arquivo de configuração no layout antigo, com todos os parâmetros
+da simulação diretamente em [sim_params] (ex: 'sim_params.space_max_dimension'),
+para o layout atual com tabelas aninhadas (ex: 'sim_params.general.space_max_dimension').
+Chaves escritas com o nome do campo em Go ou com outra capitalização
+(ex: 'TotalNeurons') são renomeadas para a chave TOML ('total_neurons').
+
+No layout antigo essas chaves eram ignoradas silenciosamente, então a conversão
+pode mudar o comportamento de uma execução: confira o resultado com
+'crownet config show'. Chaves sem campo correspondente interrompem a conversão.
+Os comentários do arquivo original não são preservados.
+
+Sem --output, o resultado é escrito na saída padrão.
+
+Exemplo:
+  crownet config migrate --input config.toml --output config_nova.toml`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		var doc map[string]any
+		if _, err := toml.DecodeFile(configMigrateInput, &doc); err != nil {
+			return fmt.Errorf("erro ao decodificar arquivo TOML '%s': %w", configMigrateInput, err)
+		}
+		changes, err := config.MigrateLegacy(doc)
+		if err != nil {
+			return fmt.Errorf("erro ao converter '%s': %w", configMigrateInput, err)
+		}
+
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "# Convertido de %s por 'crownet config migrate'.\n\n", configMigrateInput)
+		if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+			return fmt.Errorf("erro ao codificar a configuração convertida: %w", err)
+		}
+		// O resultado precisa ser lido sem chaves desconhecidas.
+		md, err := toml.Decode(buf.String(), config.DefaultAppConfig(config.ModeSim))
+		if err != nil {
+			return fmt.Errorf("erro ao reler a configuração convertida: %w", err)
+		}
+		if unknown := config.UnknownKeys(md); len(unknown) > 0 {
+			return fmt.Errorf("a configuração convertida ainda tem chaves desconhecidas, a começar por %s", unknown[0].Key)
+		}
+
+		if configMigrateOutput == "" {
+			_, err := os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := writeNewFile(configMigrateOutput, buf.Bytes(), configMigrateForce); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			slog.Info("Configuration key converted", "change", change)
+		}
+		slog.Info("Configuration migrated", "input", configMigrateInput, "output", configMigrateOutput,
+			"keys_converted", len(changes))
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configMigrateCmd)
+
+	configMigrateCmd.Flags().StringVarP(&configMigrateInput, "input", "i", "",
+		"Arquivo de configuração no layout antigo (obrigatório).")
+	configMigrateCmd.Flags().StringVarP(&configMigrateOutput, "output", "o", "",
+		"Arquivo de configuração convertido (vazio: saída padrão).")
+	configMigrateCmd.Flags().BoolVar(&configMigrateForce, "force", false,
+		"Sobrescreve o arquivo de saída se ele já existir.")
+	if err := configMigrateCmd.MarkFlagRequired("input"); err != nil {
+		log.Printf("Warning: could not mark 'input' as required for configMigrateCmd: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+
+	"crownet/config"
+)
+
+var (
+	configShowMode string
+	configShowSet  []string
+)
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show [arquivo.toml]",
+	Short: "Mostra a configuração efetiva e a origem de cada valor.",
+	Long: `Combina os valores padrão do modo escolhido, o arquivo de configuração (argumento
+ou --configFile) e as sobrescritas --set, na mesma ordem usada por 'sim', 'expose' e
+'observe', e imprime o resultado em TOML. Cada valor é anotado com sua origem:
+'padrão', 'arquivo' ou '--set'. Chaves do arquivo que não correspondem a nenhum
+campo (ignoradas na execução) são listadas no início.
+
+Flags explícitas dos outros comandos (ex: --neurons) não são consideradas.
+
+Exemplo:
+  crownet config show config.toml --mode expose --set learning.hebbian_coincidence_window=3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		switch configShowMode {
+		case config.ModeSim, config.ModeExpose, config.ModeObserve:
+		default:
+			return fmt.Errorf("flag --mode inválida '%s': use '%s', '%s' ou '%s'",
+				configShowMode, config.ModeSim, config.ModeExpose, config.ModeObserve)
+		}
+
+		appCfg := config.DefaultAppConfig(configShowMode)
+		filePath := configPath(args)
+		var md toml.MetaData
+		var unknown []config.UnknownKey
+		if filePath != "" {
+			var err error
+			if md, unknown, err = config.LoadFile(filePath, appCfg); err != nil {
+				return err
+			}
+		}
+		if err := appCfg.ApplyOverrides(configShowSet); err != nil {
+			return fmt.Errorf("erro nas sobrescritas --set: %w", err)
+		}
+		overridden := make(map[string]bool, len(configShowSet))
+		for _, assignment := range configShowSet {
+			key, _, _ := strings.Cut(assignment, "=")
+			if canonical, err := appCfg.CanonicalKey(strings.TrimSpace(key)); err == nil {
+				overridden[canonical] = true
+			}
+		}
+
+		if filePath != "" {
+			fmt.Printf("# Configuração efetiva do modo '%s' com o arquivo %s.\n", configShowMode, filePath)
+		} else {
+			fmt.Printf("# Configuração efetiva do modo '%s' (sem arquivo de configuração).\n", configShowMode)
+		}
+		for _, k := range unknown {
+			fmt.Printf("# Chave ignorada: %s\n", describeUnknownKey(k))
+		}
+
+		source := func(f config.Field) string {
+			switch {
+			case overridden[f.Key()]:
+				return "--set"
+			case filePath != "" && md.IsDefined(strings.Split(f.Key(), ".")...):
+				return "arquivo"
+			default:
+				return "padrão"
+			}
+		}
+		printConfigFields(appCfg.Fields(), source)
+		return nil
+	},
+}
+
+// printConfigFields prints fields as TOML tables, with the values of each table
+// aligned and annotated with their source.
+func printConfigFields(fields []config.Field, source func(config.Field) string) {
+	for start := 0; start < len(fields); {
+		end := start
+		for end < len(fields) && fields[end].Section == fields[start].Section {
+			end++
+		}
+		fmt.Printf("\n[%s]\n", fields[start].Section)
+		lines := make([]string, 0, end-start)
+		width := 0
+		for _, f := range fields[start:end] {
+			line := f.Name + " = " + config.FormatTOMLValue(f.Value)
+			lines = append(lines, line)
+			width = max(width, len(line))
+		}
+		for i, f := range fields[start:end] {
+			fmt.Printf("%-*s  # %s\n", width, lines[i], source(f))
+		}
+		start = end
+	}
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+
+	configShowCmd.Flags().StringVar(&configShowMode, "mode", config.ModeSim,
+		"Modo cujos valores padrão são usados: 'sim', 'expose' ou 'observe'.")
+	configShowCmd.Flags().StringArrayVar(&configShowSet, "set", nil,
+		"Sobrescreve um campo da configuração (ex: --set learning.hebbian_coincidence_window=3). Pode ser repetida.")
+}
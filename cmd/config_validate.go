@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"crownet/config"
+)
+
+var configValidateMode string
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [arquivo.toml]",
+	Short: "Valida um arquivo de configuração TOML.",
+	Long: `Verifica o arquivo de configuração (argumento ou --configFile) de forma estrita:
+toda chave precisa corresponder a um campo da configuração, em vez de ser ignorada
+silenciosamente como na execução. Chaves do layout antigo, com todos os parâmetros
+da simulação diretamente em [sim_params], são apontadas junto com sua tabela atual
+e podem ser convertidas com 'crownet config migrate'.
+
+Em seguida, a configuração resultante (valores padrão mais o arquivo) passa pelas
+mesmas validações feitas antes de uma execução. O modo validado vem de --mode, de
+'cli.mode' no arquivo ou, na falta de ambos, é 'sim'.
+
+Exemplo:
+  crownet config validate config.toml --mode expose`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := configPath(args)
+		if filePath == "" {
+			return fmt.Errorf("informe o arquivo de configuração como argumento ou com --configFile")
+		}
+
+		mode := configValidateMode
+		appCfg := config.DefaultAppConfig(mode)
+		md, unknown, err := config.LoadFile(filePath, appCfg)
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("mode") && md.IsDefined("cli", "mode") && appCfg.Cli.Mode != mode {
+			// Recarrega sobre os padrões do modo definido no arquivo.
+			mode = appCfg.Cli.Mode
+			appCfg = config.DefaultAppConfig(mode)
+			if _, _, err := config.LoadFile(filePath, appCfg); err != nil {
+				return err
+			}
+		}
+		appCfg.Cli.Mode = mode
+
+		if len(unknown) > 0 {
+			legacy := false
+			for _, k := range unknown {
+				slog.Warn("Unknown configuration key", "path", filePath, "key", describeUnknownKey(k))
+				legacy = legacy || k.NestedKey != ""
+			}
+			if legacy {
+				slog.Warn("The file uses the legacy [sim_params] layout; convert it with 'crownet config migrate'",
+					"path", filePath)
+			}
+			return fmt.Errorf("%d chave(s) desconhecida(s) em '%s'", len(unknown), filePath)
+		}
+		if err := appCfg.Validate(); err != nil {
+			return fmt.Errorf("configuração inválida em '%s' (modo %s): %w", filePath, mode, err)
+		}
+		slog.Info("Configuration is valid", "path", filePath, "mode", mode)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+
+	configValidateCmd.Flags().StringVar(&configValidateMode, "mode", config.ModeSim,
+		"Modo validado: 'sim', 'expose' ou 'observe' (padrão: 'cli.mode' do arquivo ou 'sim').")
+}
@@ -3,11 +3,11 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"log/slog"
 
 	"os"            // For pprof file creation
 	"runtime/pprof" // For CPU and memory profiling
 
-	"github.com/BurntSushi/toml" // FEATURE-CONFIG-001
 	"github.com/spf13/cobra"
 
 	"crownet/cli"
@@ -17,14 +17,31 @@ import (
 
 var (
 	// Flags para o commando expose
-	exposeEpochs           int
-	exposeCyclesPerPattern int
-	exposeTotalNeurons     int    // Duplicates global 'totalNeurons' but specific to expose if needed, or use global
-	exposeWeightsFile      string // Duplicates global 'weightsFile'
-	exposeBaseLearningRate float64
-	exposeDbPath           string // Duplicates global 'dbPath'
-	exposeSaveInterval     int    // Duplicates global 'saveInterval'
-	exposeDebugChem        bool   // Duplicates global 'debugChem'
+	exposeEpochs              int
+	exposeCyclesPerPattern    int
+	exposeCurriculumOrder     string
+	exposeCurriculumSchedule  string
+	exposePatternRepeats      int
+	exposeDigitRepeats        []int
+	exposeMaxCyclesPerPattern int
+	exposeRestCycles          int
+	exposeTotalNeurons        int    // Duplicates global 'totalNeurons' but specific to expose if needed, or use global
+	exposeWeightsFile         string // Duplicates global 'weightsFile'
+	exposeBaseLearningRate    float64
+	exposeDbPath              string // Duplicates global 'dbPath'
+	exposeSaveInterval        int    // Duplicates global 'saveInterval'
+	exposeDebugChem           bool   // Duplicates global 'debugChem'
+	exposeLogSpikes           bool
+	exposeSynapseLogInterval  int
+	exposeSynapseLogMode      string
+	exposeSynapseLogThreshold float64
+	exposeLogQueue            int
+	exposeLogBackpressure     string
+	exposeStreamAddr          string
+	exposeStreamEvery         int
+	exposeMetricsAddr         string
+	exposeModelFile           string
+	exposeSet                 []string // Sobrescritas genéricas 'secao.chave=valor' (--set)
 	// Profiling flags
 	exposeCPUProfileFile string // Renamed from exposeCpuProfileFile
 	exposeMemProfileFile string
@@ -35,7 +52,11 @@ var exposeCmd = &cobra.Command{
 	Short: "Executa o modo de exposição/treinamento da rede.",
 	Long: `O modo expose é usado para treinar a rede neural apresentando
 sequências de padrões de entrada (e.g. dígitos) e ajustando os pesos sinápticos
-através de aprendizado Hebbiano modulado por neuroquímicos.`,
+através de aprendizado Hebbiano modulado por neuroquímicos.
+
+Interrompido com Ctrl-C (ou SIGTERM), conclui o ciclo atual e salva a rede
+parcialmente treinada com o sufixo .interrupted (ex: pesos.interrupted.json),
+sem alterar o arquivo de pesos original.`,
 	RunE: func(cmd *cobra.Command, _ []string) error { // args renamed to _
 		// CPU Profiling
 		if exposeCPUProfileFile != "" {
@@ -48,35 +69,51 @@ através de aprendizado Hebbiano modulado por neuroquímicos.`,
 				log.Fatal("could not start CPU profile: ", err)
 			}
 			defer pprof.StopCPUProfile()
-			fmt.Printf("CPU profiling enabled, saving to %s\n", exposeCPUProfileFile)
+			slog.Info("CPU profiling enabled", "path", exposeCPUProfileFile)
 		}
 
-		fmt.Println("Executando modo expose via Cobra...")
-
 		// 1. Inicializar AppConfig com valores padrão das flags Cobra e SimParams defaults
 		appCfg := &config.AppConfig{
 			SimParams: config.DefaultSimulationParameters(),
 			Cli: config.CLIConfig{
-				Mode:             config.ModeExpose,
-				TotalNeurons:     exposeTotalNeurons,
-				Seed:             seed,
-				WeightsFile:      exposeWeightsFile,
-				BaseLearningRate: common.Rate(exposeBaseLearningRate),
-				Epochs:           exposeEpochs,
-				CyclesPerPattern: exposeCyclesPerPattern,
-				DbPath:           exposeDbPath,
-				SaveInterval:     exposeSaveInterval,
-				DebugChem:        exposeDebugChem,
+				Mode:                config.ModeExpose,
+				TotalNeurons:        exposeTotalNeurons,
+				Seed:                seed,
+				WeightsFile:         exposeWeightsFile,
+				BaseLearningRate:    common.Rate(exposeBaseLearningRate),
+				Epochs:              exposeEpochs,
+				CyclesPerPattern:    exposeCyclesPerPattern,
+				CurriculumOrder:     exposeCurriculumOrder,
+				CurriculumSchedule:  exposeCurriculumSchedule,
+				PatternRepeats:      exposePatternRepeats,
+				DigitRepeats:        exposeDigitRepeats,
+				MaxCyclesPerPattern: exposeMaxCyclesPerPattern,
+				RestCycles:          exposeRestCycles,
+				DbPath:              exposeDbPath,
+				SaveInterval:        exposeSaveInterval,
+				DebugChem:           exposeDebugChem,
+				LogSpikes:           exposeLogSpikes,
+				SynapseLogInterval:  exposeSynapseLogInterval,
+				SynapseLogMode:      exposeSynapseLogMode,
+				SynapseLogThreshold: exposeSynapseLogThreshold,
+				LogQueueSize:        exposeLogQueue,
+				LogBackpressure:     exposeLogBackpressure,
+				StreamAddr:          exposeStreamAddr,
+				StreamEvery:         exposeStreamEvery,
+				MetricsAddr:         exposeMetricsAddr,
+				ModelFile:           exposeModelFile,
 			},
 		}
 
 		// 2. Carregar de arquivo TOML se especificado
 		if configFile != "" {
-			fmt.Printf("Carregando configuração do arquivo TOML: %s\n", configFile)
+			slog.Info("Loading configuration file", "path", configFile)
 			cliCfgBeforeToml := appCfg.Cli
-			if _, err := toml.DecodeFile(configFile, appCfg); err != nil {
-				log.Printf("Aviso: erro ao decodificar arquivo TOML '%s': %v. Continuando.", configFile, err)
+			if _, unknown, err := config.LoadFile(configFile, appCfg); err != nil {
+				slog.Warn("Could not decode configuration file, continuing with defaults and flags", "path", configFile, "err", err)
 				appCfg.Cli = cliCfgBeforeToml
+			} else {
+				warnUnknownKeys(configFile, unknown)
 			}
 		}
 
@@ -99,6 +136,24 @@ através de aprendizado Hebbiano modulado por neuroquímicos.`,
 		if cmd.Flags().Changed("cyclesPerPattern") {
 			appCfg.Cli.CyclesPerPattern = exposeCyclesPerPattern
 		}
+		if cmd.Flags().Changed("curriculumOrder") {
+			appCfg.Cli.CurriculumOrder = exposeCurriculumOrder
+		}
+		if cmd.Flags().Changed("curriculumSchedule") {
+			appCfg.Cli.CurriculumSchedule = exposeCurriculumSchedule
+		}
+		if cmd.Flags().Changed("patternRepeats") {
+			appCfg.Cli.PatternRepeats = exposePatternRepeats
+		}
+		if cmd.Flags().Changed("digitRepeats") {
+			appCfg.Cli.DigitRepeats = exposeDigitRepeats
+		}
+		if cmd.Flags().Changed("maxCyclesPerPattern") {
+			appCfg.Cli.MaxCyclesPerPattern = exposeMaxCyclesPerPattern
+		}
+		if cmd.Flags().Changed("restCycles") {
+			appCfg.Cli.RestCycles = exposeRestCycles
+		}
 		if cmd.Flags().Changed("dbPath") {
 			appCfg.Cli.DbPath = exposeDbPath
 		}
@@ -108,13 +163,51 @@ através de aprendizado Hebbiano modulado por neuroquímicos.`,
 		if cmd.Flags().Changed("debugChem") {
 			appCfg.Cli.DebugChem = exposeDebugChem
 		}
+		if cmd.Flags().Changed("logSpikes") {
+			appCfg.Cli.LogSpikes = exposeLogSpikes
+		}
+		if cmd.Flags().Changed("synapseLogInterval") {
+			appCfg.Cli.SynapseLogInterval = exposeSynapseLogInterval
+		}
+		if cmd.Flags().Changed("synapseLogMode") {
+			appCfg.Cli.SynapseLogMode = exposeSynapseLogMode
+		}
+		if cmd.Flags().Changed("synapseLogThreshold") {
+			appCfg.Cli.SynapseLogThreshold = exposeSynapseLogThreshold
+		}
+		if cmd.Flags().Changed("logQueue") {
+			appCfg.Cli.LogQueueSize = exposeLogQueue
+		}
+		if cmd.Flags().Changed("logBackpressure") {
+			appCfg.Cli.LogBackpressure = exposeLogBackpressure
+		}
+		if cmd.Flags().Changed("streamAddr") {
+			appCfg.Cli.StreamAddr = exposeStreamAddr
+		}
+		if cmd.Flags().Changed("streamEvery") {
+			appCfg.Cli.StreamEvery = exposeStreamEvery
+		}
+		if cmd.Flags().Changed("metricsAddr") {
+			appCfg.Cli.MetricsAddr = exposeMetricsAddr
+		}
+		if cmd.Flags().Changed("modelFile") {
+			appCfg.Cli.ModelFile = exposeModelFile
+		}
+
+		// 4. Aplicar sobrescritas --set, que têm precedência sobre o TOML e as flags acima.
+		appCfg.Cli.Overrides = exposeSet
+		if err := appCfg.ApplyOverrides(exposeSet); err != nil {
+			return fmt.Errorf("flag --set inválida: %w", err)
+		}
 
 		if err := appCfg.Validate(); err != nil {
 			return fmt.Errorf("configuração inválida para o modo expose: %w", err)
 		}
 
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
 		orchestrator := cli.NewOrchestrator(appCfg)
-		runErr := orchestrator.Run() // Store error from Run
+		runErr := orchestrator.Run(ctx) // Store error from Run
 
 		// Memory Profiling (Heap)
 		if exposeMemProfileFile != "" && runErr == nil { // Only write mem profile if run was successful
@@ -127,7 +220,7 @@ através de aprendizado Hebbiano modulado por neuroquímicos.`,
 			if err := pprof.WriteHeapProfile(f); err != nil {
 				log.Fatal("could not write memory profile: ", err)
 			}
-			fmt.Printf("Memory heap profile saved to %s\n", exposeMemProfileFile)
+			slog.Info("Memory profile saved", "path", exposeMemProfileFile)
 		}
 
 		if runErr != nil {
@@ -144,6 +237,19 @@ func init() {
 	exposeCmd.Flags().IntVar(&exposeCyclesPerPattern, "cyclesPerPattern", 20,
 		"Número de ciclos de simulação por apresentação de padrão.")
 
+	// Currículo de exposição
+	exposeCmd.Flags().StringVar(&exposeCurriculumOrder, "curriculumOrder", config.CurriculumSequential,
+		"Ordem dos dígitos em cada época: 'sequential' (0 a 9) ou 'shuffled' (sorteada a cada época pela semente).")
+	exposeCmd.Flags().StringVar(&exposeCurriculumSchedule, "curriculumSchedule", config.CurriculumInterleaved,
+		"Repetições de cada dígito: 'interleaved' (passadas por todos os dígitos) ou 'blocked' (repetições em sequência).")
+	exposeCmd.Flags().IntVar(&exposePatternRepeats, "patternRepeats", 1, "Apresentações de cada dígito por época.")
+	exposeCmd.Flags().IntSliceVar(&exposeDigitRepeats, "digitRepeats", nil,
+		"Apresentações por época de cada dígito de 0 a 9 (10 valores, 0 omite o dígito, ex: 1,1,1,2,1,1,1,1,2,1); substitui --patternRepeats.")
+	exposeCmd.Flags().IntVar(&exposeMaxCyclesPerPattern, "maxCyclesPerPattern", 0,
+		"Se maior que --cyclesPerPattern, cada apresentação dura um número sorteado de ciclos entre os dois.")
+	exposeCmd.Flags().IntVar(&exposeRestCycles, "restCycles", 0,
+		"Ciclos sem entrada, com a dinâmica ativa, após cada apresentação.")
+
 	// Flags de simulação relevantes para expose
 	exposeCmd.Flags().IntVarP(&exposeTotalNeurons, "neurons", "n", 200, "Total de neurônios na rede.")
 	exposeCmd.Flags().StringVarP(&exposeWeightsFile, "weightsFile", "w", "crownet_weights.json",
@@ -151,12 +257,36 @@ func init() {
 	if err := exposeCmd.MarkFlagRequired("weightsFile"); err != nil { // Salvar pesos é essential após expose
 		log.Printf("Warning: could not mark 'weightsFile' as required for exposeCmd: %v", err)
 	}
+	exposeCmd.Flags().StringVar(&exposeModelFile, "modelFile", "",
+		"Arquivo de modelo (layout, pesos e parâmetros): retoma o treino dele se existir e o grava ao final.")
 	exposeCmd.Flags().Float64Var(&exposeBaseLearningRate, "lrBase", 0.01, "Taxa de aprendizado base.")
 	exposeCmd.Flags().StringVar(&exposeDbPath, "dbPath", "",
 		"Caminho opcional para o arquivo SQLite para logging durante o expose.")
 	exposeCmd.Flags().IntVar(&exposeSaveInterval, "saveInterval", 0,
 		"Intervalo de ciclos para salvar no BD durante expose (0 desabilita).")
-	exposeCmd.Flags().BoolVar(&exposeDebugChem, "debugChem", false, "Habilita logs de depuração para neuroquímicos.")
+	exposeCmd.Flags().BoolVar(&exposeDebugChem, "debugChem", false, "Registra o estado dos neuroquímicos a cada ciclo em nível info (sem a flag, só em --log-level debug).")
+	exposeCmd.Flags().BoolVar(&exposeLogSpikes, "logSpikes", false,
+		"Grava cada disparo de neurônio na tabela Spikes do BD (requer --dbPath).")
+	exposeCmd.Flags().IntVar(&exposeSynapseLogInterval, "synapseLogInterval", 0,
+		"Intervalo de ciclos para gravar os pesos sinápticos na tabela SynapseSnapshots (0 desabilita).")
+	exposeCmd.Flags().StringVar(&exposeSynapseLogMode, "synapseLogMode", "full",
+		"Modo dos snapshots de pesos: 'full' (matriz completa) ou 'delta' (só pesos alterados).")
+	exposeCmd.Flags().Float64Var(&exposeSynapseLogThreshold, "synapseLogThreshold", 0.0,
+		"No modo 'delta', variação mínima de peso para gravar uma sinapse.")
+	exposeCmd.Flags().IntVar(&exposeLogQueue, "logQueue", 0,
+		"Número de snapshots aguardando o gravador do BD em segundo plano (0 usa o padrão, 16).")
+	exposeCmd.Flags().StringVar(&exposeLogBackpressure, "logBackpressure", "block",
+		"Com a fila do gravador cheia: 'block' (a simulação espera) ou 'drop' (descarta a gravação; "+
+			"snapshots e lotes de disparos da tabela Spikes podem ser perdidos).")
+	exposeCmd.Flags().StringVar(&exposeStreamAddr, "streamAddr", "",
+		"Endereço (host:porta) para transmitir métricas por ciclo via Server-Sent Events em /events (vazio desabilita).")
+	exposeCmd.Flags().IntVar(&exposeStreamEvery, "streamEvery", 0,
+		"Transmite um registro de métricas a cada N ciclos (0 ou 1: todo ciclo).")
+	exposeCmd.Flags().StringVar(&exposeMetricsAddr, "metricsAddr", "",
+		"Endereço (host:porta) para expor métricas no formato Prometheus em /metrics (vazio desabilita).")
+
+	exposeCmd.Flags().StringArrayVar(&exposeSet, "set", nil,
+		"Sobrescreve qualquer campo da configuração pela chave TOML (ex: --set neurochemical.cortisol_decay_rate=0.01). Repetível.")
 
 	// Profiling flags
 	exposeCmd.Flags().StringVar(&exposeCPUProfileFile, "cpuprofile", "", "Escreve perfil de CPU para este arquivo.")
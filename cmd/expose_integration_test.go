@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time" // For unique temp dir names, though t.TempDir() handles this
 
@@ -11,6 +17,7 @@ import (
 	"crownet/cli"
 	"crownet/common"
 	"crownet/config"
+	"crownet/storage"
 )
 
 // Helper function to create a minimal AppConfig for expose tests
@@ -68,7 +75,7 @@ func TestExposeCommand_BasicRun(t *testing.T) {
 	// 5. Run the orchestrator
 	// We are primarily checking if the expose mode runs to completion without panic/error.
 	// Console output capture is optional as per task TSK-TEST-003.1.1 and can be complex.
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 
 	// 6. Assert that no error is returned
 	if err != nil {
@@ -91,7 +98,7 @@ func TestExposeCommand_NewWeightsFileCreation(t *testing.T) {
 	appCfg := newTestExposeAppConfig(tempDir, weightsFileName)
 
 	orchestrator := cli.NewOrchestrator(appCfg)
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 	if err != nil {
 		t.Fatalf("Orchestrator.Run() for new weights file creation failed: %v", err)
 	}
@@ -149,7 +156,7 @@ func TestExposeCommand_ModifyWeightsFile(t *testing.T) {
 	// For simplicity, we'll assume the new run with 50 neurons will overwrite it completely.
 
 	orchestrator := cli.NewOrchestrator(appCfg)
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 	if err != nil {
 		t.Fatalf("Orchestrator.Run() for modifying weights file failed: %v", err)
 	}
@@ -194,3 +201,278 @@ func TestExposeCommand_ModifyWeightsFile(t *testing.T) {
 			"This might be okay for minimal run, but check if intended.", tempWeightsFilePath)
 	}
 }
+
+func TestExposeCommand_ModelBundleRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	modelPath := filepath.Join(tempDir, "model.json")
+
+	exposeCfg := newTestExposeAppConfig(tempDir, "weights.json")
+	exposeCfg.Cli.ModelFile = modelPath
+	if err := cli.NewOrchestrator(exposeCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for expose with model file failed: %v", err)
+	}
+	bundle, err := storage.LoadModelBundle(modelPath)
+	if err != nil {
+		t.Fatalf("LoadModelBundle() failed: %v", err)
+	}
+	if bundle.FormatVersion != storage.ModelFormatVersion || len(bundle.Neurons) != 50 || len(bundle.Synapses) == 0 {
+		t.Fatalf("Unexpected bundle: version %d, %d neurons, %d synapses",
+			bundle.FormatVersion, len(bundle.Neurons), len(bundle.Synapses))
+	}
+
+	// observe with a different seed and neuron count must rebuild the saved network.
+	observeCfg := newTestObserveAppConfig("")
+	observeCfg.Cli.ModelFile = modelPath
+	observeCfg.Cli.TotalNeurons = 60
+	observeCfg.Cli.Seed = exposeCfg.Cli.Seed + 1
+	if err := observeCfg.Validate(); err != nil {
+		t.Fatalf("Constructed observe AppConfig is invalid: %v", err)
+	}
+	observer := cli.NewOrchestrator(observeCfg)
+	if err := observer.Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for observe with model file failed: %v", err)
+	}
+	if got := len(observer.Net.Neurons); got != 50 {
+		t.Fatalf("Observe network has %d neurons, want the 50 of the model", got)
+	}
+	for _, n := range bundle.Neurons {
+		var found bool
+		for _, on := range observer.Net.Neurons {
+			if on.ID == n.ID {
+				found = true
+				if on.Type.String() != n.Type || on.Position != n.Position {
+					t.Errorf("Neuron %d: type %s at %v, want %s at %v", n.ID, on.Type, on.Position, n.Type, n.Position)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Neuron %d of the model is missing from the observe network", n.ID)
+		}
+	}
+	for _, s := range bundle.Synapses {
+		if !observer.Net.SynapticWeights.HasConnection(s.From, s.To) {
+			t.Fatalf("Synapse %d->%d of the model is missing from the observe network", s.From, s.To)
+		}
+	}
+}
+
+// TestObserve_RejectsInvalidModelBundle checks that the simulation parameters of a
+// model bundle are validated like those of a configuration file.
+func TestObserve_RejectsInvalidModelBundle(t *testing.T) {
+	tempDir := t.TempDir()
+	modelPath := filepath.Join(tempDir, "model.json")
+	exposeCfg := newTestExposeAppConfig(tempDir, "weights.json")
+	exposeCfg.Cli.ModelFile = modelPath
+	if err := cli.NewOrchestrator(exposeCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for expose with model file failed: %v", err)
+	}
+	bundle, err := storage.LoadModelBundle(modelPath)
+	if err != nil {
+		t.Fatalf("LoadModelBundle() failed: %v", err)
+	}
+	bundle.SimParams.Homeostasis.Enabled = true
+	bundle.SimParams.Homeostasis.TargetFiringRateHz = 0
+	if err := storage.SaveModelBundle(bundle, modelPath); err != nil {
+		t.Fatalf("SaveModelBundle() failed: %v", err)
+	}
+
+	observeCfg := newTestObserveAppConfig("")
+	observeCfg.Cli.ModelFile = modelPath
+	err = cli.NewOrchestrator(observeCfg).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), modelPath) {
+		t.Errorf("Expected an invalid configuration error naming %s, got %v", modelPath, err)
+	}
+}
+
+func TestExposeCommand_BinaryWeightsAndConvert(t *testing.T) {
+	tempDir := t.TempDir()
+	binPath := filepath.Join(tempDir, "weights.bin.gz")
+
+	appCfg := newTestExposeAppConfig(tempDir, "weights.bin.gz")
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for expose with binary weights failed: %v", err)
+	}
+	binWeights, err := storage.LoadNetworkWeights(binPath)
+	if err != nil {
+		t.Fatalf("LoadNetworkWeights(%s) failed: %v", binPath, err)
+	}
+	if len(binWeights) == 0 {
+		t.Fatalf("Binary weights file %s has no synapses", binPath)
+	}
+
+	// Convert to JSON and back to binary; float64 conversions must be lossless.
+	jsonPath := filepath.Join(tempDir, "converted.json")
+	roundTripPath := filepath.Join(tempDir, "roundtrip.bin")
+	for _, args := range [][]string{
+		{"weights", "convert", "--input", binPath, "--output", jsonPath},
+		{"weights", "convert", "-i", jsonPath, "-o", roundTripPath},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("crownet %v failed: %v", args, err)
+		}
+	}
+	roundTrip, err := storage.LoadNetworkWeights(roundTripPath)
+	if err != nil {
+		t.Fatalf("LoadNetworkWeights(%s) failed: %v", roundTripPath, err)
+	}
+	if !reflect.DeepEqual(roundTrip, binWeights) {
+		t.Fatalf("Weights changed after converting %s -> JSON -> %s", binPath, roundTripPath)
+	}
+	if _, err := os.Stat(storage.NeuronParamsPath(jsonPath)); err != nil {
+		t.Errorf("Neuron parameters were not copied alongside %s: %v", jsonPath, err)
+	}
+
+	// A corrupted binary file must be rejected by the checksum.
+	data, err := os.ReadFile(roundTripPath)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", roundTripPath, err)
+	}
+	data[len(data)-1] ^= 0xFF
+	if err := os.WriteFile(roundTripPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", roundTripPath, err)
+	}
+	if _, err := storage.LoadNetworkWeights(roundTripPath); err == nil {
+		t.Fatalf("LoadNetworkWeights accepted a corrupted binary file")
+	}
+}
+
+func TestExposeCommand_InterruptSavesRecoveryWeights(t *testing.T) {
+	tempDir := t.TempDir()
+	appCfg := newTestExposeAppConfig(tempDir, "interrupted.json")
+	appCfg.Cli.ModelFile = filepath.Join(tempDir, "model.json")
+	appCfg.Cli.Epochs = 100
+	if err := appCfg.Validate(); err != nil {
+		t.Fatalf("Constructed AppConfig is invalid: %v", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("received interrupt"))
+	err := cli.NewOrchestrator(appCfg).Run(ctx)
+	if !errors.Is(err, cli.ErrInterrupted) {
+		t.Fatalf("Expected an interrupted run, got %v", err)
+	}
+
+	recoveryWeights := cli.RecoveryPath(appCfg.Cli.WeightsFile)
+	if recoveryWeights != filepath.Join(tempDir, "interrupted.interrupted.json") {
+		t.Errorf("Unexpected recovery path %s", recoveryWeights)
+	}
+	if _, errLoad := storage.LoadNetworkWeights(recoveryWeights); errLoad != nil {
+		t.Errorf("Recovery weights not readable: %v", errLoad)
+	}
+	if _, errBundle := storage.LoadModelBundle(cli.RecoveryPath(appCfg.Cli.ModelFile)); errBundle != nil {
+		t.Errorf("Recovery model not readable: %v", errBundle)
+	}
+	for _, path := range []string{appCfg.Cli.WeightsFile, appCfg.Cli.ModelFile} {
+		if _, errStat := os.Stat(path); !os.IsNotExist(errStat) {
+			t.Errorf("Interrupted run should not write %s (stat error: %v)", path, errStat)
+		}
+	}
+
+	if got := cli.RecoveryPath("w.bin.gz"); got != "w.interrupted.bin.gz" {
+		t.Errorf("RecoveryPath(w.bin.gz) = %s, want w.interrupted.bin.gz", got)
+	}
+}
+
+func TestExposeCommand_Curriculum(t *testing.T) {
+	resetCurriculumFlags := func() {
+		exposeCurriculumOrder, exposeCurriculumSchedule = config.CurriculumSequential, config.CurriculumInterleaved
+		exposePatternRepeats, exposeDigitRepeats, exposeMaxCyclesPerPattern, exposeRestCycles = 1, nil, 0, 0
+	}
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		logLevel, logFormat, seed = "info", logFormatText, 0
+		resetCurriculumFlags()
+		rootCmd.SetErr(nil)
+		slog.SetDefault(defaultLogger)
+	})
+
+	// runExpose runs a 2-epoch expose and returns the digits and lengths of the
+	// presentations and the number of cycles run, from the debug log records.
+	runExpose := func(extra ...string) (digits, lengths []int, cycles int, err error) {
+		resetCurriculumFlags() // Flag variables keep their values between executions.
+		var stderr bytes.Buffer
+		rootCmd.SetErr(&stderr)
+		args := append([]string{"expose", "--epochs", "2", "--neurons", "50", "--cyclesPerPattern", "2",
+			"--seed", "7", "--weightsFile", filepath.Join(t.TempDir(), "w.json"),
+			"--log-format", "json", "--log-level", "debug"}, extra...)
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			return nil, nil, 0, err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+			var record struct {
+				Msg    string `json:"msg"`
+				Digit  int    `json:"digit"`
+				Cycles int    `json:"cycles"`
+			}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("log line is not JSON: %q: %v", line, err)
+			}
+			switch record.Msg {
+			case "Pattern presented":
+				digits = append(digits, record.Digit)
+				lengths = append(lengths, record.Cycles)
+			case "Cycle completed":
+				cycles++
+			}
+		}
+		return digits, lengths, cycles, nil
+	}
+
+	digits, _, cycles, err := runExpose("--curriculumSchedule", "blocked",
+		"--digitRepeats", "0,2,0,0,0,0,0,0,0,1", "--restCycles", "3")
+	if err != nil {
+		t.Fatalf("blocked expose failed: %v", err)
+	}
+	if want := []int{1, 1, 9, 1, 1, 9}; !reflect.DeepEqual(digits, want) {
+		t.Errorf("blocked schedule presented %v, want %v", digits, want)
+	}
+	if want := 2 * 3 * (2 + 3); cycles != want {
+		t.Errorf("ran %d cycles, want %d (presentations of 2 cycles, each followed by 3 rest cycles)", cycles, want)
+	}
+
+	shuffled := []string{"--curriculumOrder", "shuffled", "--patternRepeats", "2", "--maxCyclesPerPattern", "4"}
+	digits, lengths, _, err := runExpose(shuffled...)
+	if err != nil {
+		t.Fatalf("shuffled expose failed: %v", err)
+	}
+	if len(digits) != 40 {
+		t.Fatalf("expected 2 epochs x 2 passes x 10 digits = 40 presentations, got %d", len(digits))
+	}
+	sequential := true
+	for pass := 0; pass < 4; pass++ {
+		seen := make(map[int]bool)
+		for i, d := range digits[pass*10 : pass*10+10] {
+			seen[d] = true
+			sequential = sequential && d == i
+		}
+		if len(seen) != 10 {
+			t.Errorf("pass %d presented %v, want each digit once", pass, digits[pass*10:pass*10+10])
+		}
+	}
+	if sequential {
+		t.Error("shuffled order presented the digits from 0 to 9 in every pass")
+	}
+	for _, n := range lengths {
+		if n < 2 || n > 4 {
+			t.Errorf("presentation of %d cycles, want between --cyclesPerPattern (2) and --maxCyclesPerPattern (4)", n)
+		}
+	}
+	again, lengthsAgain, _, err := runExpose(shuffled...)
+	if err != nil {
+		t.Fatalf("second shuffled expose failed: %v", err)
+	}
+	if !reflect.DeepEqual(again, digits) || !reflect.DeepEqual(lengthsAgain, lengths) {
+		t.Error("the same seed produced a different curriculum")
+	}
+
+	if _, _, _, err := runExpose("--curriculumOrder", "random"); err == nil {
+		t.Error("expected an error for an invalid --curriculumOrder")
+	}
+	appCfg := newTestExposeAppConfig(t.TempDir(), "w.json")
+	appCfg.Cli.DigitRepeats = []int{1, 2}
+	if err := appCfg.Validate(); err == nil {
+		t.Error("expected Validate to reject digitRepeats without one value per digit")
+	}
+}
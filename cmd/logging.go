@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Formatos aceitos por --log-format.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Flags globais de logging.
+var (
+	logLevel  string // Nível mínimo dos registros: debug, info, warn ou error.
+	logFormat string // Formato dos registros: text ou json.
+	quiet     bool   // Suprime registros de progresso (equivale a --log-level warn).
+)
+
+// newLogger cria o logger estruturado configurado pelas flags globais, escrevendo em w.
+func newLogger(w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return nil, fmt.Errorf("--log-level inválido %q: use debug, info, warn ou error", logLevel)
+	}
+	if quiet && level < slog.LevelWarn {
+		level = slog.LevelWarn
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(logFormat) {
+	case logFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case logFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("--log-format inválido %q: use %s ou %s", logFormat, logFormatText, logFormatJSON)
+	}
+}
+
+// setupLogging instala o logger das flags globais como slog.Default(), usado pelo
+// Orchestrator e pela rede. Os registros vão para a saída de erro do commando, de
+// modo que a saída padrão contém apenas resultados (ex: exportações para stdout).
+func setupLogging(cmd *cobra.Command, _ []string) error {
+	logger, err := newLogger(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+func init() {
+	// Executa também o PersistentPreRunE da raiz quando um subcomando define o seu (ex: logutil).
+	cobra.EnableTraverseRunHooks = true
+	rootCmd.PersistentPreRunE = setupLogging
+
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
+		"Nível mínimo dos registros de log: debug, info, warn ou error.")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText,
+		"Formato dos registros de log (na saída de erro): text ou json.")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false,
+		"Suprime os registros de progresso; mostra apenas avisos e erros.")
+}
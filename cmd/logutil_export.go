@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 
@@ -15,17 +16,20 @@ var (
 	logutilExportTable  string
 	logutilExportFormat string
 	logutilExportOutput string
+	logutilExportRun    string
 )
 
 // logutilExportCmd represents the logutil export command
 var logutilExportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Exporta dados de uma tabela do log SQLite para um formato especificado (ex: CSV).",
+	Short: "Exporta dados de uma tabela do log SQLite para CSV, NDJSON ou Arrow.",
 	Long: `Lê um arquivo de banco de dados SQLite gerado pelo CrowNet e exporta
-os dados da tabela especificada. Atualmente, suporta exportação para CSV.`,
+os dados da tabela especificada. Formatos suportados:
+  csv    - uma linha por registro; Position/Velocity como texto JSON em uma célula.
+  ndjson - um objeto JSON por linha; Position/Velocity como arrays aninhados.
+  arrow  - arquivo Arrow IPC (Feather v2) com colunas tipadas; Position/Velocity
+           divididos em colunas Float64 Position_0..Position_15 (requer --output).`,
 	RunE: func(_ *cobra.Command, _ []string) error { // cmd and args renamed to _
-		fmt.Println("Executando logutil export via Cobra...")
-
 		// Usar as flags globais e as locais para popular uma CLIConfig temporária para validação
 		// ou passar diretamente para a função de exportação.
 		// A validação das flags já é feita em AppConfig.Validate() se usarmos essa via.
@@ -38,6 +42,7 @@ os dados da tabela especificada. Atualmente, suporta exportação para CSV.`,
 			LogUtilTable:      logutilExportTable,
 			LogUtilFormat:     logutilExportFormat,
 			LogUtilOutput:     logutilExportOutput,
+			LogUtilRun:        logutilExportRun,
 		}
 		tempAppCfg := &config.AppConfig{Cli: tempCliCfg}
 		if err := tempAppCfg.Validate(); err != nil {
@@ -50,28 +55,26 @@ os dados da tabela especificada. Atualmente, suporta exportação para CSV.`,
 		// No entanto, a validação em config.AppConfig.Validate() já checa se LogUtilDbPath não está vazio.
 		// A validação de existência do arquivo será feita por storage.ExportLogData.
 
-		fmt.Printf("  Database: %s\n", logutilExportDbPath)
-		fmt.Printf("  Table: %s\n", logutilExportTable)
-		fmt.Printf("  Format: %s\n", logutilExportFormat)
-		if logutilExportOutput != "" {
-			fmt.Printf("  Output: %s\n", logutilExportOutput)
-		} else {
-			fmt.Println("  Output: stdout")
+		// Os registros vão para a saída de erro: sem --output, os dados exportados
+		// ocupam a saída padrão.
+		output := logutilExportOutput
+		if output == "" {
+			output = "stdout"
 		}
+		slog.Info("Exporting log table", "db_path", logutilExportDbPath, "table", logutilExportTable,
+			"format", logutilExportFormat, "run", logutilExportRun, "output", output)
 
 		err := storage.ExportLogData(
 			logutilExportDbPath,
 			logutilExportTable,
 			logutilExportFormat,
 			logutilExportOutput,
+			logutilExportRun,
 		)
 		if err != nil {
-			// Usar log.Printf para erros não fatais que não devem parar o Cobra em si,
-			// mas sim reportar o erro da operação.
-			log.Printf("Erro durante a exportação do log: %v", err)
-			return err // Retornar o erro para que Cobra o reporte
+			return fmt.Errorf("erro durante a exportação do log: %w", err)
 		}
-		fmt.Println("Exportação do log concluída com sucesso.")
+		slog.Info("Log export completed", "table", logutilExportTable, "output", output)
 		return nil
 	},
 }
@@ -86,13 +89,15 @@ func init() {
 	}
 
 	logutilExportCmd.Flags().StringVarP(&logutilExportTable, "table", "t", "",
-		"Tabela a ser exportada (ex: 'NetworkSnapshots', 'NeuronStates') (obrigatório).")
+		"Tabela a ser exportada ('Runs', 'NetworkSnapshots', 'NeuronStates', 'Spikes' ou 'SynapseSnapshots') (obrigatório).")
 	if err := logutilExportCmd.MarkFlagRequired("table"); err != nil {
 		log.Printf("Warning: could not mark 'table' as required for logutilExportCmd: %v", err)
 	}
 
 	logutilExportCmd.Flags().StringVarP(&logutilExportFormat, "format", "f", "csv",
-		"Formato de saída (atualmente apenas 'csv').")
+		"Formato de saída: 'csv', 'ndjson' ou 'arrow'.")
 	logutilExportCmd.Flags().StringVarP(&logutilExportOutput, "output", "o", "",
 		"Arquivo de saída (stdout se não especificado).")
+	logutilExportCmd.Flags().StringVar(&logutilExportRun, "run", "",
+		"Exporta apenas as linhas desta execução (ID listado por 'logutil runs'; todas se vazio).")
 }
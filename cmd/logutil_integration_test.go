@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"crownet/cli"
+	"crownet/storage"
+)
+
+// TestLogutilExportCommand_StdoutCarriesOnlyData checks that, without --output, the
+// exported rows are the only thing written to stdout, and that the status records
+// go to stderr.
+func TestLogutilExportCommand_StdoutCarriesOnlyData(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "export_stdout.db")
+	appCfg := newTestSimAppConfig(4, 50, dbPath, 2)
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() failed: %v", err)
+	}
+
+	defaultLogger := slog.Default()
+	originalStdout := os.Stdout
+	t.Cleanup(func() {
+		os.Stdout = originalStdout
+		rootCmd.SetErr(nil)
+		slog.SetDefault(defaultLogger)
+		logutilExportDbPath, logutilExportTable, logutilExportFormat = "", "", "csv"
+	})
+
+	rPipe, wPipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe for stdout capture: %v", err)
+	}
+	os.Stdout = wPipe
+	captured := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, rPipe)
+		captured <- buf.String()
+	}()
+
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"logutil", "export", "--dbPath", dbPath, "--table", "NetworkSnapshots"})
+	execErr := rootCmd.Execute()
+	wPipe.Close()
+	os.Stdout = originalStdout
+	stdout := <-captured
+	if execErr != nil {
+		t.Fatalf("logutil export failed: %v\n%s", execErr, stderr.String())
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("stdout is not valid CSV: %v\n%s", err, stdout)
+	}
+	if len(rows) != 3 || !strings.Contains(strings.Join(rows[0], ","), "Cycle") {
+		t.Errorf("expected a header and 2 snapshot rows on stdout, got:\n%s", stdout)
+	}
+	if !strings.Contains(stderr.String(), "Log export completed") {
+		t.Errorf("expected the completion record on stderr, got:\n%s", stderr.String())
+	}
+
+	rootCmd.SetArgs([]string{"logutil", "export", "--dbPath", filepath.Join(t.TempDir(), "missing.db"),
+		"--table", "NetworkSnapshots"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected logutil export to fail for a missing database")
+	}
+	if strings.Count(stderr.String(), "Log export completed") != 1 {
+		t.Errorf("a failed export must not report completion:\n%s", stderr.String())
+	}
+}
+
+// TestLogutilExportCommand_NDJSONToFile checks the --format and --output flags of
+// logutil export (the export formats themselves are tested in package storage).
+func TestLogutilExportCommand_NDJSONToFile(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "export_ndjson.db")
+	appCfg := newTestSimAppConfig(4, 50, dbPath, 2)
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		logutilExportDbPath, logutilExportTable, logutilExportFormat, logutilExportOutput = "", "", "csv", ""
+	})
+
+	ndjsonPath := filepath.Join(dir, "states.ndjson")
+	rootCmd.SetArgs([]string{"logutil", "export", "--dbPath", dbPath, "--table", "NeuronStates",
+		"--format", "ndjson", "--output", ndjsonPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("logutil export --format ndjson failed: %v", err)
+	}
+	data, err := os.ReadFile(ndjsonPath)
+	if err != nil {
+		t.Fatalf("Failed to read NDJSON export: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 100 { // 50 neurons at cycles 2 and 4.
+		t.Errorf("NDJSON export has %d lines, want 100", lines)
+	}
+}
+
+// TestLogutilStatsCommand_WritesJSON checks the --output flag of logutil stats (the
+// statistics themselves are tested in package storage).
+func TestLogutilStatsCommand_WritesJSON(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "stats.db")
+	appCfg := newTestSimAppConfig(4, 50, dbPath, 2)
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() failed: %v", err)
+	}
+	t.Cleanup(func() { logutilStatsDbPath, logutilStatsOutput = "", "" })
+
+	statsPath := filepath.Join(dir, "stats.json")
+	rootCmd.SetArgs([]string{"logutil", "stats", "--dbPath", dbPath, "--output", statsPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("logutil stats failed: %v", err)
+	}
+	data, err := os.ReadFile(statsPath)
+	if err != nil {
+		t.Fatalf("Failed to read statistics file: %v", err)
+	}
+	var stats storage.LogStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("Statistics file is not valid JSON: %v", err)
+	}
+	if stats.Snapshots != 2 || stats.FirstCycle != 2 || stats.LastCycle != 4 {
+		t.Errorf("Got %d snapshots over cycles %d-%d, want 2 over cycles 2-4",
+			stats.Snapshots, stats.FirstCycle, stats.LastCycle)
+	}
+}
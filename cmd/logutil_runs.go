@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"crownet/storage"
+)
+
+var logutilRunsDbPath string
+
+// logutilRunsCmd represents the logutil runs command
+var logutilRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Lista as execuções registradas em um log SQLite.",
+	Long: `Lista as execuções (tabela Runs) gravadas em um arquivo SQLite do CrowNet:
+ID, modo, início e fim, semente, número de snapshots e versão do software.
+Use o ID com 'logutil export --run' para exportar apenas os dados de uma execução.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		runs, err := storage.ListRuns(logutilRunsDbPath)
+		if err != nil {
+			return fmt.Errorf("erro ao listar execuções: %w", err)
+		}
+		if len(runs) == 0 {
+			slog.Info("No runs recorded in this database", "db_path", logutilRunsDbPath)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RunID\tMode\tStart\tEnd\tSeed\tSnapshots\tVersion")
+		for _, run := range runs {
+			end := "-"
+			if !run.EndTime.IsZero() {
+				end = run.EndTime.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+				run.RunID, run.Mode, run.StartTime.Format("2006-01-02 15:04:05"), end,
+				run.Seed, run.Snapshots, run.SoftwareVersion)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	logutilCmd.AddCommand(logutilRunsCmd)
+
+	logutilRunsCmd.Flags().StringVarP(&logutilRunsDbPath, "dbPath", "d", "",
+		"Caminho para o arquivo SQLite DB (obrigatório).")
+	if err := logutilRunsCmd.MarkFlagRequired("dbPath"); err != nil {
+		log.Printf("Warning: could not mark 'dbPath' as required for logutilRunsCmd: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"crownet/storage"
+)
+
+var (
+	logutilStatsDbPath string
+	logutilStatsRun    string
+	logutilStatsOutput string
+)
+
+// logutilStatsCmd represents the logutil stats command
+var logutilStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Mostra estatísticas resumidas de um log SQLite.",
+	Long: `Resume um arquivo SQLite do CrowNet sem exportá-lo: número de snapshots e
+intervalo de ciclos; mínimo, média e máximo de cortisol, dopamina e dos fatores de
+modulação; contagem de disparos por tipo de neurônio (registros de NeuronStates no
+estado Firing); e deslocamento médio dos neurônios entre o primeiro e o último snapshot.
+Com --output, as estatísticas também são gravadas em JSON.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		stats, err := storage.ComputeLogStats(logutilStatsDbPath, logutilStatsRun)
+		if err != nil {
+			return fmt.Errorf("erro ao calcular estatísticas: %w", err)
+		}
+		printLogStats(stats)
+
+		if logutilStatsOutput != "" {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return fmt.Errorf("erro ao codificar estatísticas em JSON: %w", err)
+			}
+			if err := os.WriteFile(logutilStatsOutput, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("erro ao gravar estatísticas em %s: %w", logutilStatsOutput, err)
+			}
+			slog.Info("Log statistics saved", "path", logutilStatsOutput)
+		}
+		return nil
+	},
+}
+
+// printLogStats prints a human-readable summary of stats to stdout.
+func printLogStats(stats *storage.LogStats) {
+	fmt.Printf("Database: %s\n", stats.DBPath)
+	if stats.RunID != "" {
+		fmt.Printf("Run: %s\n", stats.RunID)
+	}
+	if stats.Snapshots == 0 {
+		fmt.Println("Nenhum snapshot registrado.")
+		return
+	}
+	fmt.Printf("Snapshots: %d (ciclos %d a %d)\n", stats.Snapshots, stats.FirstCycle, stats.LastCycle)
+
+	fmt.Printf("%-26s %12s %12s %12s\n", "", "min", "mean", "max")
+	for _, row := range []struct {
+		name string
+		vs   storage.ValueStats
+	}{
+		{"Cortisol", stats.Cortisol},
+		{"Dopamine", stats.Dopamine},
+		{"LearningRateModFactor", stats.LearningRateModFactor},
+		{"SynaptogenesisModFactor", stats.SynaptogenesisModFactor},
+	} {
+		fmt.Printf("%-26s %12.4f %12.4f %12.4f\n", row.name, row.vs.Min, row.vs.Mean, row.vs.Max)
+	}
+
+	fmt.Println("Disparos por tipo de neurônio:")
+	types := make([]string, 0, len(stats.FiringCounts))
+	for t := range stats.FiringCounts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Printf("  %-14s %d\n", t, stats.FiringCounts[t])
+	}
+
+	fmt.Printf("Deslocamento médio: %.4f (%d neurônios)\n", stats.MeanDisplacement, stats.DisplacedNeurons)
+}
+
+func init() {
+	logutilCmd.AddCommand(logutilStatsCmd)
+
+	logutilStatsCmd.Flags().StringVarP(&logutilStatsDbPath, "dbPath", "d", "",
+		"Caminho para o arquivo SQLite DB (obrigatório).")
+	if err := logutilStatsCmd.MarkFlagRequired("dbPath"); err != nil {
+		log.Printf("Warning: could not mark 'dbPath' as required for logutilStatsCmd: %v", err)
+	}
+	logutilStatsCmd.Flags().StringVar(&logutilStatsRun, "run", "",
+		"Considera apenas esta execução (ID listado por 'logutil runs'; todas se vazio).")
+	logutilStatsCmd.Flags().StringVarP(&logutilStatsOutput, "output", "o", "",
+		"Arquivo JSON onde gravar as estatísticas (opcional).")
+}
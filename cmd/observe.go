@@ -2,9 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
-	"github.com/BurntSushi/toml" // FEATURE-CONFIG-001
 	"github.com/spf13/cobra"
 
 	"crownet/cli"
@@ -18,15 +17,18 @@ var (
 	observeTotalNeurons   int    // Duplicates global 'totalNeurons'
 	observeWeightsFile    string // Duplicates global 'weightsFile'
 	observeDebugChem      bool   // Duplicates global 'debugChem'
+	observeModelFile      string
+	observeSet            []string // Sobrescritas genéricas 'secao.chave=valor' (--set)
 )
 
 var observeCmd = &cobra.Command{
 	Use:   "observe",
 	Short: "Executa o modo de observação da rede.",
 	Long: `O modo observe é usado para apresentar um padrão específico (e.g. um dígito)
-à rede (com pesos previamente treinados) e observar o padrão de ativação dos neurônios de saída.`,
+à rede (com pesos previamente treinados) e observar o padrão de ativação dos neurônios de saída.
+Com --modelFile, a rede é reconstruída do arquivo de modelo gravado pelo expose (neurônios,
+posições, limiares, pesos e parâmetros de simulação), sem depender de --seed e --neurons.`,
 	RunE: func(cmd *cobra.Command, _ []string) error { // args renamed to _
-		fmt.Println("Executando modo observe via Cobra...")
 
 		// 1. Inicializar AppConfig com valores padrão das flags Cobra e SimParams defaults
 		appCfg := &config.AppConfig{
@@ -39,16 +41,19 @@ var observeCmd = &cobra.Command{
 				Digit:          observeDigit,
 				CyclesToSettle: observeCyclesToSettle,
 				DebugChem:      observeDebugChem,
+				ModelFile:      observeModelFile,
 			},
 		}
 
 		// 2. Carregar de arquivo TOML se especificado
 		if configFile != "" {
-			fmt.Printf("Carregando configuração do arquivo TOML: %s\n", configFile)
+			slog.Info("Loading configuration file", "path", configFile)
 			cliCfgBeforeToml := appCfg.Cli
-			if _, err := toml.DecodeFile(configFile, appCfg); err != nil {
-				log.Printf("Aviso: erro ao decodificar arquivo TOML '%s': %v. Continuando.", configFile, err)
+			if _, unknown, err := config.LoadFile(configFile, appCfg); err != nil {
+				slog.Warn("Could not decode configuration file, continuing with defaults and flags", "path", configFile, "err", err)
 				appCfg.Cli = cliCfgBeforeToml
+			} else {
+				warnUnknownKeys(configFile, unknown)
 			}
 		}
 
@@ -71,13 +76,24 @@ var observeCmd = &cobra.Command{
 		if cmd.Flags().Changed("debugChem") {
 			appCfg.Cli.DebugChem = observeDebugChem
 		}
+		if cmd.Flags().Changed("modelFile") {
+			appCfg.Cli.ModelFile = observeModelFile
+		}
+
+		// 4. Aplicar sobrescritas --set, que têm precedência sobre o TOML e as flags acima.
+		appCfg.Cli.Overrides = observeSet
+		if err := appCfg.ApplyOverrides(observeSet); err != nil {
+			return fmt.Errorf("flag --set inválida: %w", err)
+		}
 
 		if err := appCfg.Validate(); err != nil {
 			return fmt.Errorf("configuração inválida para o modo observe: %w", err)
 		}
 
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
 		orchestrator := cli.NewOrchestrator(appCfg)
-		if err := orchestrator.Run(); err != nil {
+		if err := orchestrator.Run(ctx); err != nil {
 			return fmt.Errorf("erro durante a execução do modo observe: %w", err)
 		}
 		return nil
@@ -94,9 +110,10 @@ func init() {
 	observeCmd.Flags().IntVarP(&observeTotalNeurons, "neurons", "n", 200,
 		"Total de neurônios na rede (deve corresponder à rede dos pesos carregados).")
 	observeCmd.Flags().StringVarP(&observeWeightsFile, "weightsFile", "w", "crownet_weights.json",
-		"Arquivo para carregar os pesos sinápticos.")
-	if err := observeCmd.MarkFlagRequired("weightsFile"); err != nil {
-		log.Printf("Warning: could not mark 'weightsFile' as required for observeCmd: %v", err)
-	}
-	observeCmd.Flags().BoolVar(&observeDebugChem, "debugChem", false, "Habilita logs de depuração para neuroquímicos.")
+		"Arquivo para carregar os pesos sinápticos (ignorado com --modelFile).")
+	observeCmd.Flags().StringVar(&observeModelFile, "modelFile", "",
+		"Arquivo de modelo gravado pelo expose; a rede é construída a partir dele.")
+	observeCmd.Flags().BoolVar(&observeDebugChem, "debugChem", false, "Registra o estado dos neuroquímicos a cada ciclo em nível info (sem a flag, só em --log-level debug).")
+	observeCmd.Flags().StringArrayVar(&observeSet, "set", nil,
+		"Sobrescreve qualquer campo da configuração pela chave TOML (ex: --set neurochemical.cortisol_decay_rate=0.01). Repetível.")
 }
@@ -2,8 +2,9 @@ package cmd
 
 import (
 	"bytes" // Needed for buffer
-	"io"    // Needed for MultiWriter and pipe reading
-	"os"    // Needed for stdout capture
+	"context"
+	"io" // Needed for MultiWriter and pipe reading
+	"os" // Needed for stdout capture
 	"path/filepath"
 	"strings" // Needed for output assertions
 	"testing"
@@ -71,7 +72,7 @@ func TestObserveCommand_BasicRun(t *testing.T) {
 	// 4. Run the orchestrator
 	// We are primarily checking if the observe mode runs to completion without panic/error
 	// using the fixture weights.
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 
 	// 5. Assert that no error is returned
 	if err != nil {
@@ -111,7 +112,7 @@ func TestObserveCommand_BasicRun(t *testing.T) {
 	// as the state of the previous 'orchestrator' might have been affected
 	// or might not be suitable for a re-run if it holds state across Run() calls.
 	orchestratorForCapture := cli.NewOrchestrator(appCfg)
-	runErrForCapture := orchestratorForCapture.Run()
+	runErrForCapture := orchestratorForCapture.Run(context.Background())
 
 	wPipe.Close()
 	os.Stdout = originalStdout // Restore stdout
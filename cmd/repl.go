@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"crownet/cli"
+	"crownet/config"
+)
+
+var (
+	replTotalNeurons int
+	replWeightsFile  string
+	replScript       string
+	replSet          []string
+)
+
+// replCmd represents the repl command
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Abre um shell interativo para depurar a dinâmica da rede passo a passo.",
+	Long: `Cria uma rede e abre um shell interativo que a controla um comando por vez:
+executar ciclos, apresentar dígitos, estimular neurônios de entrada, inspecionar o
+estado, o potencial e os pesos de um neurônio, ver os neuroquímicos, ligar e
+desligar aprendizado, sinaptogênese e modulação química, e salvar ou carregar pesos.
+Digite 'help' no shell para a lista de comandos.
+
+Os comandos executados podem ser salvos com 'history save <arquivo>' e repetidos
+com --script (ou 'source <arquivo>' no shell), para sessões de depuração
+reprodutíveis com a mesma --seed. Sem terminal na entrada padrão, os comandos são
+lidos dela sem prompt e o primeiro erro encerra o shell.
+
+Nenhum dado é gravado em SQLite.
+
+Exemplo:
+  crownet repl --neurons 100 --seed 42 --script sessao.txt`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		appCfg := config.DefaultAppConfig(config.ModeSim)
+		appCfg.Cli.TotalNeurons = replTotalNeurons
+		appCfg.Cli.Seed = seed
+		appCfg.Cli.WeightsFile = replWeightsFile
+
+		if configFile != "" {
+			slog.Info("Loading configuration file", "path", configFile)
+			_, unknown, err := config.LoadFile(configFile, appCfg)
+			if err != nil {
+				return err
+			}
+			warnUnknownKeys(configFile, unknown)
+		}
+		// O shell sempre controla uma rede do modo sim, sem logging.
+		appCfg.Cli.Mode = config.ModeSim
+		appCfg.Cli.DbPath = ""
+		appCfg.Cli.SaveInterval = 0
+
+		if cmd.Flags().Changed("seed") {
+			appCfg.Cli.Seed = seed
+		}
+		if cmd.Flags().Changed("neurons") {
+			appCfg.Cli.TotalNeurons = replTotalNeurons
+		}
+		if cmd.Flags().Changed("weightsFile") {
+			appCfg.Cli.WeightsFile = replWeightsFile
+		}
+		appCfg.Cli.Overrides = replSet
+		if err := appCfg.ApplyOverrides(replSet); err != nil {
+			return fmt.Errorf("erro nas sobrescritas --set: %w", err)
+		}
+		if err := appCfg.Validate(); err != nil {
+			return fmt.Errorf("configuração inválida para o modo repl: %w", err)
+		}
+
+		repl, err := cli.NewRepl(appCfg, cmd.OutOrStdout())
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar o shell: %w", err)
+		}
+		if replScript != "" {
+			quit, err := repl.RunScript(replScript)
+			if err != nil {
+				return fmt.Errorf("erro no script: %w", err)
+			}
+			if quit {
+				return nil
+			}
+		}
+
+		in := cmd.InOrStdin()
+		interactive := false
+		if f, ok := in.(*os.File); ok {
+			if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				interactive = true
+			}
+		}
+		if interactive {
+			fmt.Fprintln(cmd.OutOrStdout(), "Digite 'help' para a lista de comandos e 'quit' para sair.")
+		}
+		return repl.Run(in, interactive)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+
+	replCmd.Flags().IntVarP(&replTotalNeurons, "neurons", "n", 200, "Total de neurônios na rede.")
+	replCmd.Flags().StringVarP(&replWeightsFile, "weightsFile", "w", "",
+		"Arquivo de pesos carregado ao iniciar, se existir.")
+	replCmd.Flags().StringVar(&replScript, "script", "",
+		"Arquivo de comandos executado antes de ler a entrada padrão (ex: salvo com 'history save').")
+	replCmd.Flags().StringArrayVar(&replSet, "set", nil,
+		"Sobrescreve um campo da configuração (ex: --set general.space_max_dimension=12). Pode ser repetida.")
+}
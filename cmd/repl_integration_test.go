@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplCommand_ScriptAndHistoryReplay(t *testing.T) {
+	t.Cleanup(func() {
+		replScript = ""
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+	})
+	tempDir := t.TempDir()
+	scriptPath := filepath.Join(tempDir, "session.txt")
+	weightsPath := filepath.Join(tempDir, "weights.json")
+	historyPath := filepath.Join(tempDir, "history.txt")
+	script := strings.Join([]string{
+		"# Depuração de exemplo",
+		"step 3",
+		"present 3",
+		"step 2",
+		"neuron 0",
+		"weights 0 in",
+		"dynamics learning off",
+		"chem",
+		"outputs",
+		"save " + weightsPath,
+		"history save " + historyPath,
+	}, "\n")
+	if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+		t.Fatalf("Failed to write REPL script: %v", err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetIn(strings.NewReader("step\nquit\n"))
+	rootCmd.SetArgs([]string{"repl", "--neurons", "60", "--seed", "3", "--script", scriptPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("repl command failed: %v\nOutput:\n%s", err, out.String())
+	}
+	for _, want := range []string{"now at cycle 5", "Digit 3 presented", "Neuron 0 (", "Learning: off", "Cortisol "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected REPL output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "now at cycle 6") {
+		t.Errorf("Expected the command read from stdin after the script to run, got:\n%s", out.String())
+	}
+	if _, err := os.Stat(weightsPath); err != nil {
+		t.Errorf("Expected weights to be saved to %s: %v", weightsPath, err)
+	}
+
+	history, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved history: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(history)), "\n")
+	if len(lines) != 9 || lines[0] != "step 3" || lines[len(lines)-1] != "save "+weightsPath {
+		t.Fatalf("Expected the 9 network commands of the script in the history, got %q", lines)
+	}
+
+	// Replaying the history with the same seed reproduces the session.
+	var replay bytes.Buffer
+	rootCmd.SetOut(&replay)
+	rootCmd.SetIn(strings.NewReader(""))
+	rootCmd.SetArgs([]string{"repl", "--neurons", "60", "--seed", "3", "--script", historyPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("repl replay failed: %v\nOutput:\n%s", err, replay.String())
+	}
+	stepLines := func(output string) []string {
+		var steps []string
+		for _, line := range strings.Split(output, "\n") {
+			if strings.HasPrefix(line, "Ran ") {
+				steps = append(steps, line)
+			}
+		}
+		return steps
+	}
+	original, replayed := stepLines(out.String()), stepLines(replay.String())
+	if len(original) != 3 || len(replayed) != 2 {
+		t.Fatalf("Expected 3 step reports in the session and 2 in the replay, got %q and %q", original, replayed)
+	}
+	for i := range replayed {
+		if replayed[i] != original[i] {
+			t.Errorf("Replayed step %d differs:\n  session: %s\n  replay:  %s", i+1, original[i], replayed[i])
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
-	// "fmt" // Unused import
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	// Importar config para acessar AppConfig e CLIConfig futuramente, se necessário aqui
@@ -40,6 +44,27 @@ func Execute() {
 	}
 }
 
+// interruptContext retorna um contexto cancelado ao receber SIGINT (Ctrl-C) ou SIGTERM,
+// para que sim, expose e observe terminem o ciclo atual e salvem o estado antes de sair,
+// e para que serve encerre o servidor.
+// Após o primeiro sinal o tratamento padrão é restaurado: um segundo Ctrl-C encerra
+// o processo imediatamente.
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			slog.Warn("Signal received: finishing the current cycle and saving state (repeat to abort)", "signal", sig.String())
+			cancel(fmt.Errorf("received %v", sig))
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
 func init() {
 	// cobra.OnInitialize(initConfig) // Se precisar de inicialização de config via Viper, por exemplo
 
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"crownet/server"
+)
+
+var serveAddr string
+
+// serveShutdownTimeout limita a espera pelas requisições abertas ao encerrar o servidor.
+const serveShutdownTimeout = 10 * time.Second
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Disponibiliza uma API HTTP/JSON local para controlar uma rede.",
+	Long: `Inicia um servidor HTTP que controla uma rede CrowNet por requisições JSON, para
+uso a partir de notebooks e outras ferramentas sem executar a CLI:
+
+  POST /network             cria uma rede a partir de uma configuração JSON
+                            (mesmas chaves do arquivo TOML; corpo vazio usa os padrões)
+  GET  /network             resumo da rede
+  POST /network/run         executa {"cycles": n} ciclos
+  POST /network/present     apresenta {"digit": d} ou {"pattern": [...]}
+  POST /network/frequency   estimula {"neuron_id": id, "hz": f} (0 Hz interrompe)
+  POST /network/reset       zera potenciais acumulados e pulsos ativos
+  GET  /network/outputs     ativação e frequência dos neurônios de saída
+  GET  /network/weights     pesos sinápticos, no formato JSON dos arquivos de pesos
+  PUT  /network/weights     substitui os pesos sinápticos
+  GET  /network/chemicals   níveis de neuroquímicos e fatores de modulação
+  GET  /network/dynamics    processos dinâmicos ativos
+  PUT  /network/dynamics    liga/desliga {"learning", "synaptogenesis", "chemical_modulation"}
+
+As requisições são aplicadas uma de cada vez. A API não tem autenticação: por
+padrão escuta apenas em 127.0.0.1. Ctrl-C (ou SIGTERM) interrompe as execuções
+em andamento e encerra o servidor depois de responder às requisições abertas.
+
+Exemplo:
+  crownet serve --addr 127.0.0.1:8080
+  curl -X POST localhost:8080/network -d '{"cli": {"total_neurons": 100, "seed": 42}}'
+  curl -X POST localhost:8080/network/run -d '{"cycles": 50}'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		listener, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return fmt.Errorf("erro ao escutar em %s: %w", serveAddr, err)
+		}
+		slog.Info("CrowNet API listening", "url", fmt.Sprintf("http://%s", listener.Addr()))
+
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
+		srv := &http.Server{
+			Handler:           server.New().Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			// Os contextos das requisições derivam de ctx: um sinal interrompe as
+			// execuções (POST /network/run) em andamento.
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		}
+		served := make(chan error, 1)
+		go func() { served <- srv.Serve(listener) }()
+
+		select {
+		case err := <-served:
+			return err
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("erro ao encerrar o servidor: %w", err)
+		}
+		if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		slog.Info("CrowNet API stopped")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080",
+		"Endereço (host:porta) em que a API escuta.")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"crownet/server"
+)
+
+func TestServeAPI_NetworkLifecycle(t *testing.T) {
+	ts := httptest.NewServer(server.New().Handler())
+	defer ts.Close()
+
+	do := func(method, path, body string, wantStatus int, out any) []byte {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to build request %s %s: %v", method, path, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, wantStatus, resp.StatusCode, data)
+		}
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				t.Fatalf("%s %s: invalid JSON response %q: %v", method, path, data, err)
+			}
+		}
+		return data
+	}
+
+	do("GET", "/network", "", http.StatusNotFound, nil)
+	do("POST", "/network", `{"cli": {"total_neurons": 60}, "sim_params": {"bogus": 1}}`, http.StatusBadRequest, nil)
+
+	var info server.NetworkInfo
+	do("POST", "/network", `{"cli": {"total_neurons": 60, "seed": 11},
+		"sim_params": {"general": {"space_max_dimension": 12}}}`, http.StatusCreated, &info)
+	if info.Neurons != 60 || info.Cycle != 0 || !info.Dynamics.Learning || len(info.InputNeuronIDs) == 0 {
+		t.Fatalf("Unexpected network info after creation: %+v", info)
+	}
+
+	do("POST", "/network/present", `{"digit": 4}`, http.StatusOK, nil)
+	var run server.RunResult
+	do("POST", "/network/run", `{"cycles": 5}`, http.StatusOK, &run)
+	if run.Cycle != 5 {
+		t.Errorf("Expected to be at cycle 5, got %d", run.Cycle)
+	}
+	do("POST", "/network/run", `{"cycles": 0}`, http.StatusBadRequest, nil)
+
+	var outputs struct {
+		Outputs []server.OutputState `json:"outputs"`
+	}
+	do("GET", "/network/outputs", "", http.StatusOK, &outputs)
+	if len(outputs.Outputs) == 0 {
+		t.Errorf("Expected output neuron states, got none")
+	}
+
+	var dynamics server.DynamicState
+	do("PUT", "/network/dynamics", `{"learning": false}`, http.StatusOK, &dynamics)
+	if dynamics.Learning || !dynamics.Synaptogenesis || !dynamics.ChemicalModulation {
+		t.Errorf("Expected only learning to be disabled, got %+v", dynamics)
+	}
+	var chemicals server.ChemicalLevels
+	do("GET", "/network/chemicals", "", http.StatusOK, &chemicals)
+
+	// Weights round trip: zero one synapse and read it back.
+	var weights map[string]map[string]float64
+	do("GET", "/network/weights", "", http.StatusOK, &weights)
+	var from, to string
+	for f, targets := range weights {
+		for tt := range targets {
+			from, to = f, tt
+			break
+		}
+		break
+	}
+	if from == "" {
+		t.Fatalf("Expected at least one synapse in %v", weights)
+	}
+	weights[from][to] = 0
+	body, _ := json.Marshal(weights)
+	do("PUT", "/network/weights", string(body), http.StatusNoContent, nil)
+	var reread map[string]map[string]float64
+	do("GET", "/network/weights", "", http.StatusOK, &reread)
+	if reread[from][to] != 0 {
+		t.Errorf("Expected weight %s->%s to be 0 after PUT, got %v", from, to, reread[from][to])
+	}
+	do("PUT", "/network/weights", `{"99999": {"0": 0.5}}`, http.StatusBadRequest, nil)
+
+	// Concurrent runs are applied one at a time.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(ts.URL+"/network/run", "application/json", bytes.NewBufferString(`{"cycles": 2}`))
+			if err != nil {
+				t.Errorf("Concurrent run failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	do("GET", "/network", "", http.StatusOK, &info)
+	if info.Cycle != 5+8*2 {
+		t.Errorf("Expected cycle %d after concurrent runs, got %d", 5+8*2, info.Cycle)
+	}
+}
+
+// TestServeAPI_RunStopsWhenRequestIsCancelled checks that a long run gives up the
+// network as soon as its request is cancelled, instead of holding the lock for
+// every requested cycle.
+func TestServeAPI_RunStopsWhenRequestIsCancelled(t *testing.T) {
+	handler := server.New().Handler()
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(httptest.NewRequest("POST", "/network", strings.NewReader(`{"cli": {"total_neurons": 60, "seed": 3}}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /network: expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest("POST", "/network/run", strings.NewReader(`{"cycles": 1000000}`)).WithContext(ctx)
+	rec = serve(req)
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "interrupted after 0 of 1000000") {
+		t.Errorf("cancelled run: expected status %d reporting no cycles run, got %d: %s",
+			http.StatusServiceUnavailable, rec.Code, rec.Body)
+	}
+
+	var info server.NetworkInfo
+	rec = serve(httptest.NewRequest("GET", "/network", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil || info.Cycle != 0 {
+		t.Errorf("Expected the network to stay at cycle 0 after a cancelled run, got %+v (%v)", info, err)
+	}
+}
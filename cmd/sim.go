@@ -3,11 +3,11 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"log/slog"
 
 	"os"            // For pprof file creation
 	"runtime/pprof" // For CPU and memory profiling
 
-	"github.com/BurntSushi/toml" // Added for TOML decoding
 	"github.com/spf13/cobra"
 
 	"crownet/cli"
@@ -17,18 +17,29 @@ import (
 
 var (
 	// Flags para o commando sim
-	simCycles          int
-	simDbPath          string
-	simSaveInterval    int
-	simStimInputID     int
-	simStimInputFreqHz float64
-	simMonitorOutputID int
-	simDebugChem       bool
+	simCycles              int
+	simDbPath              string
+	simSaveInterval        int
+	simStimInputID         int
+	simStimInputFreqHz     float64
+	simMonitorOutputID     int
+	simStimulusFile        string
+	simDebugChem           bool
+	simLogSpikes           bool
+	simSynapseLogInterval  int
+	simSynapseLogMode      string
+	simSynapseLogThreshold float64
+	simLogQueue            int
+	simLogBackpressure     string
+	simStreamAddr          string
+	simStreamEvery         int
+	simMetricsAddr         string
 
 	// Flags que eram globais, agora específicas para commandos de simulação
 	simTotalNeurons     int
 	simWeightsFile      string
 	simBaseLearningRate float64
+	simSet              []string // Sobrescritas genéricas 'secao.chave=valor' (--set)
 
 	// Profiling flags
 	simCPUProfileFile string // Renamed from simCpuProfileFile
@@ -42,7 +53,6 @@ var simCmd = &cobra.Command{
 	Long: `Executa uma simulação geral com todas as dinâmicas da rede (aprendizado,
 sinaptogênese, neuromodulação) ativas. Útil para observação de comportamento
 ou logging detalhado para análise posterior.`,
-	// "github.com/BurntSushi/toml" // This was a misplaced import comment
 
 	RunE: func(cmd *cobra.Command, _ []string) error { // args renamed to _
 		// CPU Profiling
@@ -56,39 +66,49 @@ ou logging detalhado para análise posterior.`,
 				log.Fatal("could not start CPU profile: ", err)
 			}
 			defer pprof.StopCPUProfile()
-			fmt.Printf("CPU profiling enabled, saving to %s\n", simCPUProfileFile)
+			slog.Info("CPU profiling enabled", "path", simCPUProfileFile)
 		}
 
-		fmt.Println("Executando modo sim via Cobra...")
-
 		// 1. Inicializar AppConfig com valores padrão das flags Cobra e SimParams defaults
 		appCfg := &config.AppConfig{
 			SimParams: config.DefaultSimulationParameters(),
 			Cli: config.CLIConfig{ // Populate com os valores padrão das flags (que já estão nas vars)
-				Mode:             config.ModeSim,
-				TotalNeurons:     simTotalNeurons,
-				Seed:             seed, // da flag global
-				WeightsFile:      simWeightsFile,
-				BaseLearningRate: common.Rate(simBaseLearningRate),
-				Cycles:           simCycles,
-				DbPath:           simDbPath,
-				SaveInterval:     simSaveInterval,
-				StimInputID:      simStimInputID,
-				StimInputFreqHz:  simStimInputFreqHz,
-				MonitorOutputID:  simMonitorOutputID,
-				DebugChem:        simDebugChem,
+				Mode:                config.ModeSim,
+				TotalNeurons:        simTotalNeurons,
+				Seed:                seed, // da flag global
+				WeightsFile:         simWeightsFile,
+				BaseLearningRate:    common.Rate(simBaseLearningRate),
+				Cycles:              simCycles,
+				DbPath:              simDbPath,
+				SaveInterval:        simSaveInterval,
+				StimInputID:         simStimInputID,
+				StimInputFreqHz:     simStimInputFreqHz,
+				MonitorOutputID:     simMonitorOutputID,
+				StimulusFile:        simStimulusFile,
+				DebugChem:           simDebugChem,
+				LogSpikes:           simLogSpikes,
+				SynapseLogInterval:  simSynapseLogInterval,
+				SynapseLogMode:      simSynapseLogMode,
+				SynapseLogThreshold: simSynapseLogThreshold,
+				LogQueueSize:        simLogQueue,
+				LogBackpressure:     simLogBackpressure,
+				StreamAddr:          simStreamAddr,
+				StreamEvery:         simStreamEvery,
+				MetricsAddr:         simMetricsAddr,
 			},
 		}
 
 		// 2. Carregar de arquivo TOML se especificado (sobrescreve os padrões acima)
 		if configFile != "" {
-			fmt.Printf("Carregando configuração do arquivo TOML: %s\n", configFile)
-			// Salvar uma cópia da CLIConfig antes de DecodeFile, para aplicar flags CLI depois
-			cliCfgBeforeToml := appCfg.Cli                                 // Make sure toml is imported for DecodeFile
-			if _, err := toml.DecodeFile(configFile, appCfg); err != nil { // toml will be undefined if not imported
-				log.Printf("Aviso: erro ao decodificar arquivo TOML '%s': %v. Continuando com padrões/flags CLI.", configFile, err)
+			slog.Info("Loading configuration file", "path", configFile)
+			// Salvar uma cópia da CLIConfig antes de LoadFile, para aplicar flags CLI depois
+			cliCfgBeforeToml := appCfg.Cli
+			if _, unknown, err := config.LoadFile(configFile, appCfg); err != nil {
+				slog.Warn("Could not decode configuration file, continuing with defaults and flags", "path", configFile, "err", err)
 				// Restaurar CLIConfig se TOML falhou, para que flags CLI ainda possam funcionar sobre defaults
 				appCfg.Cli = cliCfgBeforeToml
+			} else {
+				warnUnknownKeys(configFile, unknown)
 			}
 		}
 
@@ -127,13 +147,45 @@ ou logging detalhado para análise posterior.`,
 		if cmd.Flags().Changed("monitorOutputID") {
 			appCfg.Cli.MonitorOutputID = simMonitorOutputID
 		}
+		if cmd.Flags().Changed("stimulusFile") {
+			appCfg.Cli.StimulusFile = simStimulusFile
+		}
 		if cmd.Flags().Changed("debugChem") {
 			appCfg.Cli.DebugChem = simDebugChem
 		}
+		if cmd.Flags().Changed("logSpikes") {
+			appCfg.Cli.LogSpikes = simLogSpikes
+		}
+		if cmd.Flags().Changed("synapseLogInterval") {
+			appCfg.Cli.SynapseLogInterval = simSynapseLogInterval
+		}
+		if cmd.Flags().Changed("synapseLogMode") {
+			appCfg.Cli.SynapseLogMode = simSynapseLogMode
+		}
+		if cmd.Flags().Changed("synapseLogThreshold") {
+			appCfg.Cli.SynapseLogThreshold = simSynapseLogThreshold
+		}
+		if cmd.Flags().Changed("logQueue") {
+			appCfg.Cli.LogQueueSize = simLogQueue
+		}
+		if cmd.Flags().Changed("logBackpressure") {
+			appCfg.Cli.LogBackpressure = simLogBackpressure
+		}
+		if cmd.Flags().Changed("streamAddr") {
+			appCfg.Cli.StreamAddr = simStreamAddr
+		}
+		if cmd.Flags().Changed("streamEvery") {
+			appCfg.Cli.StreamEvery = simStreamEvery
+		}
+		if cmd.Flags().Changed("metricsAddr") {
+			appCfg.Cli.MetricsAddr = simMetricsAddr
+		}
 
-		// Nota: Se flags CLI puderem modificar SimParams diretamente, essa lógica de merge
-		// precisaria ser estendida para SimParams também. Por ora, SimParams só vem de
-		// DefaultSimulationParameters() e do arquivo TOML.
+		// 4. Aplicar sobrescritas --set, que têm precedência sobre o TOML e as flags acima.
+		appCfg.Cli.Overrides = simSet
+		if err := appCfg.ApplyOverrides(simSet); err != nil {
+			return fmt.Errorf("flag --set inválida: %w", err)
+		}
 
 		if err := appCfg.Validate(); err != nil {
 			return fmt.Errorf("configuração inválida para o modo sim: %w", err)
@@ -143,8 +195,10 @@ ou logging detalhado para análise posterior.`,
 		// ainda não está implementada. Quando estiver, precisará ser integrada aqui
 		// para potencialmente sobrescrever SimParams ou Cli antes da validação.
 
+		ctx, stop := interruptContext(cmd.Context())
+		defer stop()
 		orchestrator := cli.NewOrchestrator(appCfg)
-		runErr := orchestrator.Run() // Run agora vai internamente chamar runSimMode
+		runErr := orchestrator.Run(ctx) // Run agora vai internamente chamar runSimMode
 
 		// Memory Profiling (Heap)
 		if simMemProfileFile != "" && runErr == nil { // Only write mem profile if run was successful
@@ -156,7 +210,7 @@ ou logging detalhado para análise posterior.`,
 			if err := pprof.WriteHeapProfile(f); err != nil {
 				log.Fatal("could not write memory profile: ", err)
 			}
-			fmt.Printf("Memory heap profile saved to %s\n", simMemProfileFile)
+			slog.Info("Memory profile saved", "path", simMemProfileFile)
 		}
 
 		if runErr != nil {
@@ -180,9 +234,30 @@ func init() {
 		"ID do neurônio de entrada para estímulo contínuo (-1: primeiro disponível, -2: desabilitado).")
 	simCmd.Flags().Float64Var(&simStimInputFreqHz, "stimInputFreqHz", 0.0,
 		"Frequência (Hz) para estímulo contínuo (0.0 desabilita).")
+	simCmd.Flags().StringVar(&simStimulusFile, "stimulusFile", "",
+		"Protocolo de estímulos (TOML ou JSON) com eventos por ciclo: frequências, rampas, rajadas, padrões e repouso.")
 	simCmd.Flags().IntVar(&simMonitorOutputID, "monitorOutputID", -1,
 		"ID do neurônio de saída para monitorar frequência (-1: primeiro disponível, -2: desabilitado).")
-	simCmd.Flags().BoolVar(&simDebugChem, "debugChem", false, "Habilita logs de depuração para produção de neuroquímicos.")
+	simCmd.Flags().BoolVar(&simDebugChem, "debugChem", false, "Registra o estado dos neuroquímicos a cada ciclo em nível info (sem a flag, só em --log-level debug).")
+	simCmd.Flags().BoolVar(&simLogSpikes, "logSpikes", false,
+		"Grava cada disparo de neurônio na tabela Spikes do BD (requer --dbPath).")
+	simCmd.Flags().IntVar(&simSynapseLogInterval, "synapseLogInterval", 0,
+		"Intervalo de ciclos para gravar os pesos sinápticos na tabela SynapseSnapshots (0 desabilita).")
+	simCmd.Flags().StringVar(&simSynapseLogMode, "synapseLogMode", "full",
+		"Modo dos snapshots de pesos: 'full' (matriz completa) ou 'delta' (só pesos alterados).")
+	simCmd.Flags().Float64Var(&simSynapseLogThreshold, "synapseLogThreshold", 0.0,
+		"No modo 'delta', variação mínima de peso para gravar uma sinapse.")
+	simCmd.Flags().IntVar(&simLogQueue, "logQueue", 0,
+		"Número de snapshots aguardando o gravador do BD em segundo plano (0 usa o padrão, 16).")
+	simCmd.Flags().StringVar(&simLogBackpressure, "logBackpressure", "block",
+		"Com a fila do gravador cheia: 'block' (a simulação espera) ou 'drop' (descarta a gravação; "+
+			"snapshots e lotes de disparos da tabela Spikes podem ser perdidos).")
+	simCmd.Flags().StringVar(&simStreamAddr, "streamAddr", "",
+		"Endereço (host:porta) para transmitir métricas por ciclo via Server-Sent Events em /events (vazio desabilita).")
+	simCmd.Flags().IntVar(&simStreamEvery, "streamEvery", 0,
+		"Transmite um registro de métricas a cada N ciclos (0 ou 1: todo ciclo).")
+	simCmd.Flags().StringVar(&simMetricsAddr, "metricsAddr", "",
+		"Endereço (host:porta) para expor métricas no formato Prometheus em /metrics (vazio desabilita).")
 
 	// Flags que eram "globais" mas são contextuais aos modos de simulação
 	simCmd.Flags().IntVarP(&simTotalNeurons, "neurons", "n", 200, "Total de neurônios na rede.")
@@ -191,6 +266,9 @@ func init() {
 	simCmd.Flags().Float64Var(&simBaseLearningRate, "lrBase", 0.01, "Taxa de aprendizado base para plasticidade Hebbiana.")
 	// A flag 'seed' é persistence no rootCmd
 
+	simCmd.Flags().StringArrayVar(&simSet, "set", nil,
+		"Sobrescreve qualquer campo da configuração pela chave TOML (ex: --set neurochemical.cortisol_decay_rate=0.01). Repetível.")
+
 	// Profiling flags
 	simCmd.Flags().StringVar(&simCPUProfileFile, "cpuprofile", "", "Escreve perfil de CPU para este arquivo.")
 	simCmd.Flags().StringVar(&simMemProfileFile, "memprofile", "", "Escreve perfil de memória para este arquivo.")
@@ -1,22 +1,29 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	// "path/filepath" // Not needed if not creating temp files for this basic test
 	// "os" // Not needed for this basic test
 
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt" // For Sprintf in SQLite row count query
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/BurntSushi/toml"
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 
 	"crownet/cli"
 	"crownet/common" // For common.Rate if setting BaseLearningRate explicitly
 	"crownet/config"
+	"crownet/storage"
 )
 
 // Helper function to create a minimal AppConfig for sim tests
@@ -58,7 +65,7 @@ func TestSimCommand_BasicRun(t *testing.T) {
 	// 3. Run the orchestrator
 	// We are primarily checking if the sim mode runs for the specified cycles
 	// without panic/error.
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 
 	// 4. Assert that no error is returned
 	if err != nil {
@@ -86,7 +93,7 @@ func TestSimCommand_SQLiteLogging(t *testing.T) {
 	}
 
 	orchestrator := cli.NewOrchestrator(appCfg)
-	err := orchestrator.Run()
+	err := orchestrator.Run(context.Background())
 	if err != nil {
 		t.Fatalf("Orchestrator.Run() for sim mode with SQLite logging failed: %v", err)
 	}
@@ -142,3 +149,363 @@ func TestSimCommand_SQLiteLogging(t *testing.T) {
 		}
 	}
 }
+
+func TestSimCommand_SpikeLogging(t *testing.T) {
+	tempDbPath := filepath.Join(t.TempDir(), "test_spikes.db")
+
+	cycles := 30
+	appCfg := newTestSimAppConfig(cycles, 50, tempDbPath, 0)
+	appCfg.Cli.LogSpikes = true
+	appCfg.Cli.StimInputID = -1 // First input neuron
+	appCfg.Cli.StimInputFreqHz = 50.0
+
+	if err := appCfg.Validate(); err != nil {
+		t.Fatalf("Constructed AppConfig for spike logging test is invalid: %v", err)
+	}
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for sim mode with spike logging failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", tempDbPath)
+	if err != nil {
+		t.Fatalf("Failed to open created SQLite DB '%s': %v", tempDbPath, err)
+	}
+	defer db.Close()
+
+	var spikes, runs, maxCycle int
+	if err := db.QueryRow("SELECT COUNT(*), COUNT(DISTINCT RunID), COALESCE(MAX(Cycle), -1) FROM Spikes").
+		Scan(&spikes, &runs, &maxCycle); err != nil {
+		t.Fatalf("Error querying Spikes table: %v", err)
+	}
+	if spikes == 0 {
+		t.Fatal("Expected the stimulated input neuron to produce rows in Spikes, found none")
+	}
+	if runs != 1 {
+		t.Errorf("Expected all spikes to share one RunID, found %d", runs)
+	}
+	if maxCycle >= cycles {
+		t.Errorf("Spike recorded at cycle %d, but only cycles 0-%d were run", maxCycle, cycles-1)
+	}
+}
+
+func TestSimCommand_SynapseLogging(t *testing.T) {
+	tempDbPath := filepath.Join(t.TempDir(), "test_synapses.db")
+
+	neurons := 20
+	appCfg := newTestSimAppConfig(10, neurons, tempDbPath, 0)
+	appCfg.Cli.SynapseLogInterval = 5
+	appCfg.Cli.SynapseLogMode = config.SynapseLogDelta
+
+	if err := appCfg.Validate(); err != nil {
+		t.Fatalf("Constructed AppConfig for synapse logging test is invalid: %v", err)
+	}
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() for sim mode with synapse logging failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", tempDbPath)
+	if err != nil {
+		t.Fatalf("Failed to open created SQLite DB '%s': %v", tempDbPath, err)
+	}
+	defer db.Close()
+
+	var snapshots, orphans int
+	if err := db.QueryRow(`SELECT COUNT(DISTINCT s.SnapshotID), COUNT(*) - COUNT(n.SnapshotID)
+                           FROM SynapseSnapshots s LEFT JOIN NetworkSnapshots n ON n.SnapshotID = s.SnapshotID`).
+		Scan(&snapshots, &orphans); err != nil {
+		t.Fatalf("Error querying SynapseSnapshots table: %v", err)
+	}
+	if snapshots < 1 || snapshots > 2 {
+		t.Errorf("Expected synapse snapshots at cycles 5 and 10 (a delta may be empty), found %d", snapshots)
+	}
+	if orphans != 0 {
+		t.Errorf("Found %d SynapseSnapshots rows without a NetworkSnapshots row", orphans)
+	}
+
+	// The first snapshot of a run is always a full matrix; all-to-all has n*(n-1) synapses.
+	var fullRows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM SynapseSnapshots WHERE IsDelta = 0").Scan(&fullRows); err != nil {
+		t.Fatalf("Error counting full snapshot rows: %v", err)
+	}
+	if want := neurons * (neurons - 1); fullRows != want {
+		t.Errorf("Full synapse snapshot has %d rows, want %d", fullRows, want)
+	}
+}
+
+func TestSimCommand_RunsShareDatabase(t *testing.T) {
+	tempDbPath := filepath.Join(t.TempDir(), "test_runs.db")
+
+	for i := 0; i < 2; i++ {
+		appCfg := newTestSimAppConfig(4, 20, tempDbPath, 2)
+		appCfg.Cli.Seed = int64(100 + i)
+		if err := appCfg.Validate(); err != nil {
+			t.Fatalf("Constructed AppConfig for run %d is invalid: %v", i, err)
+		}
+		if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err != nil {
+			t.Fatalf("Orchestrator.Run() for run %d failed: %v", i, err)
+		}
+	}
+
+	runs, err := storage.ListRuns(tempDbPath)
+	if err != nil {
+		t.Fatalf("ListRuns() failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs in the shared database, found %d", len(runs))
+	}
+	for i, run := range runs {
+		if run.Mode != config.ModeSim || run.Seed != int64(100+i) {
+			t.Errorf("Run %d: mode %q seed %d, want %q seed %d", i, run.Mode, run.Seed, config.ModeSim, 100+i)
+		}
+		if run.EndTime.IsZero() {
+			t.Errorf("Run %d has no end time", i)
+		}
+		if run.Snapshots != 2 { // Cycles 2 and 4.
+			t.Errorf("Run %d has %d snapshots, want 2", i, run.Snapshots)
+		}
+		var decoded config.AppConfig
+		if _, err := toml.Decode(run.Config, &decoded); err != nil {
+			t.Errorf("Run %d: stored configuration is not valid TOML: %v", i, err)
+		} else if decoded.Cli.Seed != run.Seed {
+			t.Errorf("Run %d: stored configuration has seed %d, want %d", i, decoded.Cli.Seed, run.Seed)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", tempDbPath)
+	if err != nil {
+		t.Fatalf("Failed to open created SQLite DB '%s': %v", tempDbPath, err)
+	}
+	defer db.Close()
+	var orphanStates int
+	if err := db.QueryRow("SELECT COUNT(*) FROM NeuronStates WHERE RunID IS NULL OR RunID NOT IN (SELECT RunID FROM Runs)").
+		Scan(&orphanStates); err != nil {
+		t.Fatalf("Error querying NeuronStates: %v", err)
+	}
+	if orphanStates != 0 {
+		t.Errorf("Found %d NeuronStates rows without a recorded run", orphanStates)
+	}
+}
+
+func TestSimCommand_AsyncLoggerBackpressure(t *testing.T) {
+	t.Cleanup(func() {
+		simDbPath, simSaveInterval = "crownet_sim_run.db", 100
+		simLogSpikes, simLogQueue, simLogBackpressure = false, 0, config.LogBackpressureBlock
+	})
+	for _, backpressure := range config.SupportedLogBackpressures {
+		t.Run(backpressure, func(t *testing.T) {
+			dbPath := filepath.Join(t.TempDir(), "async.db")
+			rootCmd.SetArgs([]string{"sim", "--cycles", "10", "--neurons", "50", "--dbPath", dbPath,
+				"--saveInterval", "1", "--monitorOutputID", "-2", "--logSpikes",
+				"--logQueue", "1", "--logBackpressure", backpressure})
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("sim command with --logBackpressure %s failed: %v", backpressure, err)
+			}
+
+			db, err := sql.Open("sqlite3", dbPath)
+			if err != nil {
+				t.Fatalf("Failed to open %s: %v", dbPath, err)
+			}
+			defer db.Close()
+			var snapshots int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM NetworkSnapshots`).Scan(&snapshots); err != nil {
+				t.Fatalf("Failed to count snapshots: %v", err)
+			}
+			if snapshots == 0 || (backpressure == config.LogBackpressureBlock && snapshots != 10) {
+				t.Errorf("Got %d snapshots with --logBackpressure %s", snapshots, backpressure)
+			}
+		})
+	}
+
+	rootCmd.SetArgs([]string{"sim", "--cycles", "1", "--neurons", "50", "--dbPath", "",
+		"--monitorOutputID", "-2", "--logBackpressure", "wait"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("Expected an error for an invalid --logBackpressure")
+	}
+}
+
+func TestSimCommand_SetOverrides(t *testing.T) {
+	t.Cleanup(func() { simSet = nil })
+	dbPath := filepath.Join(t.TempDir(), "set.db")
+	rootCmd.SetArgs([]string{"sim", "--cycles", "10", "--neurons", "50", "--dbPath", dbPath,
+		"--saveInterval", "1", "--monitorOutputID", "-2",
+		"--set", "neurochemical.cortisol_decay_rate=0.02",
+		"--set", "cli.cycles=3"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("sim command with --set failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", dbPath, err)
+	}
+	defer db.Close()
+	var snapshots int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM NetworkSnapshots`).Scan(&snapshots); err != nil {
+		t.Fatalf("Failed to count snapshots: %v", err)
+	}
+	if snapshots != 3 {
+		t.Errorf("Got %d snapshots, want 3 (--set cli.cycles=3 should take precedence over --cycles)", snapshots)
+	}
+
+	t.Cleanup(func() { exposeSet = nil })
+	rootCmd.SetArgs([]string{"expose", "--epochs", "1", "--neurons", "50",
+		"--weightsFile", filepath.Join(t.TempDir(), "w.json"),
+		"--set", "neurochemical.no_such_key=1"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("Expected an unknown key error from --set, got %v", err)
+	}
+}
+
+// TestSimCommand_MetricsStream checks that a sim run serving the stream completes
+// normally without clients (the stream itself is tested in package metrics).
+func TestSimCommand_MetricsStream(t *testing.T) {
+	t.Cleanup(func() { simStreamAddr, simDbPath = "", "crownet_sim_run.db" })
+	rootCmd.SetArgs([]string{"sim", "--cycles", "3", "--neurons", "50", "--dbPath", "",
+		"--monitorOutputID", "-2", "--streamAddr", "127.0.0.1:0"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("sim command with --streamAddr failed: %v", err)
+	}
+}
+
+// TestSimCommand_PrometheusMetrics checks that a sim run serving the metrics
+// endpoint with SQLite logging completes normally (the exporter itself is tested
+// in package metrics).
+func TestSimCommand_PrometheusMetrics(t *testing.T) {
+	t.Cleanup(func() { simMetricsAddr, simDbPath = "", "crownet_sim_run.db" })
+	rootCmd.SetArgs([]string{"sim", "--cycles", "3", "--neurons", "50",
+		"--dbPath", filepath.Join(t.TempDir(), "metrics.db"),
+		"--monitorOutputID", "-2", "--metricsAddr", "127.0.0.1:0"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("sim command with --metricsAddr failed: %v", err)
+	}
+}
+
+func TestSimCommand_StructuredLogging(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		logLevel, logFormat, quiet, simDbPath = "info", logFormatText, false, "crownet_sim_run.db"
+		rootCmd.SetErr(nil)
+		slog.SetDefault(defaultLogger)
+	})
+
+	runSim := func(extra ...string) (string, error) {
+		var stderr bytes.Buffer
+		rootCmd.SetErr(&stderr)
+		logLevel, logFormat, quiet = "info", logFormatText, false
+		args := append([]string{"sim", "--cycles", "3", "--neurons", "50", "--dbPath", "",
+			"--monitorOutputID", "-2"}, extra...)
+		rootCmd.SetArgs(args)
+		err := rootCmd.Execute()
+		return stderr.String(), err
+	}
+
+	out, err := runSim("--log-format", "json")
+	if err != nil {
+		t.Fatalf("sim --log-format json failed: %v", err)
+	}
+	var cycles []float64
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line is not JSON: %q: %v", line, err)
+		}
+		if record["msg"] == "Cycle completed" {
+			cycle, ok := record["cycle"].(float64)
+			if !ok {
+				t.Fatalf("cycle record without numeric cycle field: %v", record)
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+	if len(cycles) != 2 || cycles[0] != 0 || cycles[1] != 2 {
+		t.Errorf("expected info 'Cycle completed' records for cycles 0 and 2 (every 10th and the last), got %v", cycles)
+	}
+
+	out, err = runSim("--log-format", "json", "--log-level", "debug")
+	if err != nil {
+		t.Fatalf("sim --log-level debug failed: %v", err)
+	}
+	if n := strings.Count(out, `"msg":"Cycle completed"`); n != 3 {
+		t.Errorf("expected a 'Cycle completed' record per cycle at debug level, got %d", n)
+	}
+
+	out, err = runSim("--quiet")
+	if err != nil {
+		t.Fatalf("sim --quiet failed: %v", err)
+	}
+	if strings.Contains(out, "Cycle completed") || strings.Contains(out, "level=INFO") {
+		t.Errorf("--quiet should suppress info records, got:\n%s", out)
+	}
+
+	if _, err := runSim("--log-level", "verbose"); err == nil {
+		t.Error("expected an error for an invalid --log-level")
+	}
+}
+
+func TestSimCommand_StimulusSchedule(t *testing.T) {
+	dir := t.TempDir()
+	schedulePath := filepath.Join(dir, "protocol.toml")
+	schedule := `
+[[event]]
+type = "frequency"
+inputs = [0]
+hz = 100
+stop = 20
+
+[[event]]
+type = "rest"
+start = 20
+stop = 40
+
+[[event]]
+type = "pattern"
+digit = 1
+start = 40
+every = 5
+reset = true
+`
+	if err := os.WriteFile(schedulePath, []byte(schedule), 0o644); err != nil {
+		t.Fatalf("Failed to write stimulus schedule: %v", err)
+	}
+
+	dbPath := filepath.Join(dir, "stimulus.db")
+	appCfg := newTestSimAppConfig(50, 50, dbPath, 0)
+	appCfg.Cli.LogSpikes = true
+	appCfg.Cli.Seed = 42
+	appCfg.Cli.StimulusFile = schedulePath
+	if err := appCfg.Validate(); err != nil {
+		t.Fatalf("Constructed AppConfig for stimulus schedule test is invalid: %v", err)
+	}
+	o := cli.NewOrchestrator(appCfg)
+	if err := o.Run(context.Background()); err != nil {
+		t.Fatalf("Orchestrator.Run() with a stimulus schedule failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open created SQLite DB '%s': %v", dbPath, err)
+	}
+	defer db.Close()
+	var driven, rest int
+	if err := db.QueryRow(`SELECT COALESCE(SUM(Cycle < 20), 0), COALESCE(SUM(Cycle >= 20 AND Cycle < 40), 0)
+		FROM Spikes WHERE NeuronID = ?`, int(o.Net.InputNeuronIDs[0])).Scan(&driven, &rest); err != nil {
+		t.Fatalf("Error querying Spikes table: %v", err)
+	}
+	if driven < 5 {
+		t.Errorf("Expected the input driven at 100 Hz to fire repeatedly in cycles 0-19, got %d spikes", driven)
+	}
+	if rest >= driven {
+		t.Errorf("Expected fewer firings of the input during rest (%d) than while driven (%d)", rest, driven)
+	}
+
+	outOfRange := filepath.Join(dir, "out_of_range.toml")
+	if err := os.WriteFile(outOfRange, []byte("[[event]]\ntype = \"frequency\"\ninputs = [5000]\nhz = 10\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+	appCfg = newTestSimAppConfig(5, 50, "", 0)
+	appCfg.Cli.StimulusFile = outOfRange
+	if err := cli.NewOrchestrator(appCfg).Run(context.Background()); err == nil ||
+		!strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Expected an out of range input index error, got %v", err)
+	}
+}
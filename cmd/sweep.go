@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"crownet/cli"
+	"crownet/config"
+	"crownet/storage"
+)
+
+var (
+	sweepSpecFile string
+	sweepOutput   string
+	sweepParallel int
+	sweepDryRun   bool
+)
+
+// sweepCmd represents the sweep command
+var sweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Executa uma varredura de hiperparâmetros em paralelo.",
+	Long: `Lê uma especificação de varredura em TOML e executa a rede uma vez para cada
+configuração amostrada, várias execuções ao mesmo tempo, cada uma com sua própria
+rede. Qualquer campo simples de SimulationParameters ou CLIConfig pode ser variado,
+endereçado pela sua chave TOML (ex: 'sim_params.learning.hebb_positive_reinforce_factor'
+ou 'cli.cycles_per_pattern').
+
+A amostragem 'grid' executa todas as combinações dos valores; 'random' sorteia
+'samples' configurações. Os valores de cada parâmetro são uma lista ('values') ou
+um intervalo ('min', 'max', com 'steps' pontos na grade e 'log = true' para escala
+logarítmica).
+
+A configuração base vem dos padrões e do --configFile. As execuções não gravam logs
+SQLite nem arquivos de pesos. Para cada execução são registrados a acurácia de
+avaliação (modo expose), os níveis finais de cortisol e dopamina, o fator de
+modulação da taxa de aprendizado e o peso sináptico absoluto médio, em CSV ou, para
+'.db'/'.sqlite', na tabela SweepResults de um banco SQLite.
+
+Exemplo:
+  crownet sweep --spec sweep.toml --output resultados.csv --parallel 4`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		spec, err := config.LoadSweepSpec(sweepSpecFile)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("output") || spec.Output == "" {
+			spec.Output = sweepOutput
+		}
+		if cmd.Flags().Changed("parallel") {
+			if sweepParallel < 0 {
+				return fmt.Errorf("flag --parallel deve ser não negativa, recebido %d", sweepParallel)
+			}
+			spec.Parallel = sweepParallel
+		}
+
+		// Configuração base: os mesmos padrões das flags de 'expose' e 'sim'.
+		appCfg := config.DefaultAppConfig(config.ModeExpose)
+		appCfg.Cli.Seed = seed
+		if configFile != "" {
+			slog.Info("Loading base configuration file", "path", configFile)
+			_, unknown, err := config.LoadFile(configFile, appCfg)
+			if err != nil {
+				return err
+			}
+			warnUnknownKeys(configFile, unknown)
+		}
+		if cmd.Flags().Changed("seed") {
+			appCfg.Cli.Seed = seed
+		}
+
+		if sweepDryRun {
+			sampleSeed := spec.Seed
+			if sampleSeed == 0 {
+				sampleSeed = appCfg.Cli.Seed
+			}
+			configs, err := cli.SweepConfigs(spec, sampleSeed)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d configurações seriam executadas:\n", len(configs))
+			for i, c := range configs {
+				fmt.Printf("  %3d: %s\n", i+1, formatSweepParams(c))
+			}
+			return nil
+		}
+
+		slog.Info("Starting sweep", "spec", sweepSpecFile, "mode", orDefault(spec.Mode, config.ModeExpose),
+			"sampling", orDefault(spec.Sampling, config.SweepGrid))
+		start := time.Now()
+		failed := 0
+		results, err := cli.RunSweep(spec, appCfg, func(r storage.SweepResult) {
+			if r.Error != "" {
+				failed++
+				slog.Warn("Sweep run failed", "index", r.Index, "repeat", r.Repeat, "err", r.Error)
+				return
+			}
+			attrs := []any{"index", r.Index, "repeat", r.Repeat, "params", formatSweepParams(r.Params),
+				"cortisol", r.Cortisol, "dopamine", r.Dopamine, "duration", r.Duration.Round(time.Millisecond)}
+			if r.DistinctWinnersFrac != nil {
+				attrs = append(attrs, "distinct_winners_frac", *r.DistinctWinnersFrac)
+			}
+			slog.Info("Sweep run completed", attrs...)
+		})
+		if err != nil {
+			return err
+		}
+
+		sweepID := fmt.Sprintf("sweep-%d", start.UnixNano())
+		if err := storage.WriteSweepResults(spec.Output, sweepID, results); err != nil {
+			return fmt.Errorf("erro ao gravar resultados da varredura: %w", err)
+		}
+		slog.Info("Sweep completed", "runs", len(results), "failed", failed,
+			"duration", time.Since(start).Round(time.Millisecond), "output", spec.Output)
+		return nil
+	},
+}
+
+// formatSweepParams formats the parameter values of a sweep configuration as "name=value, ...".
+func formatSweepParams(params []storage.SweepParamValue) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = fmt.Sprintf("%s=%v", p.Name, p.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(sweepCmd)
+
+	sweepCmd.Flags().StringVarP(&sweepSpecFile, "spec", "s", "",
+		"Arquivo TOML com a especificação da varredura (obrigatório).")
+	sweepCmd.Flags().StringVarP(&sweepOutput, "output", "o", "sweep_results.csv",
+		"Arquivo de resultados (CSV, ou SQLite para '.db'/'.sqlite'); tem precedência sobre 'output' da especificação.")
+	sweepCmd.Flags().IntVarP(&sweepParallel, "parallel", "p", 0,
+		"Número de execuções simultâneas (0 usa o valor da especificação ou um por CPU).")
+	sweepCmd.Flags().BoolVar(&sweepDryRun, "dryRun", false,
+		"Apenas lista as configurações que seriam executadas.")
+	if err := sweepCmd.MarkFlagRequired("spec"); err != nil {
+		log.Printf("Warning: could not mark 'spec' as required for sweepCmd: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"crownet/cli"
+	"crownet/config"
+)
+
+func TestSweepCommand_GridWritesCSV(t *testing.T) {
+	tempDir := t.TempDir()
+	specPath := filepath.Join(tempDir, "sweep.toml")
+	outputPath := filepath.Join(tempDir, "results.csv")
+	spec := `mode = "expose"
+sampling = "grid"
+repeats = 2
+seed = 7
+parallel = 2
+
+[[param]]
+name = "cli.total_neurons"
+values = [50]
+
+[[param]]
+name = "cli.epochs"
+values = [1]
+
+[[param]]
+name = "cli.cycles_per_pattern"
+values = [2]
+
+[[param]]
+name = "cli.cycles_to_settle"
+values = [2]
+
+[[param]]
+name = "sim_params.learning.hebb_positive_reinforce_factor"
+min = 0.1
+max = 0.3
+steps = 2
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write sweep spec: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"sweep", "--spec", specPath, "--output", outputPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("sweep command failed: %v", err)
+	}
+
+	file, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to open sweep results: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read sweep results CSV: %v", err)
+	}
+
+	// Header plus 2 configurations x 2 repeats.
+	if len(records) != 5 {
+		t.Fatalf("Expected 5 CSV records, got %d: %v", len(records), records)
+	}
+	header := records[0]
+	column := make(map[string]int)
+	for i, name := range header {
+		column[name] = i
+	}
+	for _, name := range []string{"run", "repeat", "seed", "cli.total_neurons",
+		"sim_params.learning.hebb_positive_reinforce_factor", "distinct_winners_frac", "cortisol", "error"} {
+		if _, ok := column[name]; !ok {
+			t.Fatalf("Sweep results header %v has no column %s", header, name)
+		}
+	}
+	for _, record := range records[1:] {
+		if msg := record[column["error"]]; msg != "" {
+			t.Errorf("Run %s failed: %s", record[column["run"]], msg)
+		}
+		winners, err := strconv.ParseFloat(record[column["distinct_winners_frac"]], 64)
+		if err != nil || winners < 0 || winners > 1 {
+			t.Errorf("Run %s: distinct_winners_frac %q is not a fraction", record[column["run"]], record[column["distinct_winners_frac"]])
+		}
+	}
+	if got := records[3][column["sim_params.learning.hebb_positive_reinforce_factor"]]; got != "0.3" {
+		t.Errorf("Expected the second configuration to use 0.3, got %s", got)
+	}
+	if records[1][column["seed"]] != "7" || records[2][column["seed"]] != "8" {
+		t.Errorf("Expected repeats to use seeds 7 and 8, got %s and %s",
+			records[1][column["seed"]], records[2][column["seed"]])
+	}
+}
+
+// TestRunSweep_SimAppliesStimulusSchedule checks that sim runs load the stimulus
+// schedule of the base configuration: a schedule driving a nonexistent input must
+// fail every run.
+func TestRunSweep_SimAppliesStimulusSchedule(t *testing.T) {
+	schedulePath := filepath.Join(t.TempDir(), "protocol.toml")
+	if err := os.WriteFile(schedulePath, []byte("[[event]]\ntype = \"frequency\"\ninputs = [5000]\nhz = 10\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write stimulus schedule: %v", err)
+	}
+	base := newTestSimAppConfig(3, 50, "", 0)
+	base.Cli.StimulusFile = schedulePath
+	spec := &config.SweepSpec{Mode: config.ModeSim, Repeats: 2, Seed: 7, Parallel: 2,
+		Params: []config.SweepParam{{Name: "cli.cycles", Values: []any{int64(3)}}}}
+
+	results, err := cli.RunSweep(spec, base, nil)
+	if err != nil {
+		t.Fatalf("RunSweep() failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("RunSweep() returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if !strings.Contains(r.Error, "out of range") {
+			t.Errorf("Run %d (repeat %d): error %q, want an out of range input index error", r.Index, r.Repeat, r.Error)
+		}
+	}
+}
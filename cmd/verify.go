@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+
+	"crownet/cli"
+	"crownet/config"
+)
+
+var (
+	// Flags para o commando verify
+	verifyCycles          int
+	verifyTotalNeurons    int
+	verifyStimInputID     int
+	verifyStimInputFreqHz float64
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifica se uma configuração produz simulações reprodutíveis.",
+	Long: `Executa a mesma configuração duas vezes, lado a lado, no modo sim e compara
+hashes do estado da rede (neurônios, pesos, pulsos e neuroquímicos) a cada ciclo.
+Se as execuções divergirem, informa o primeiro ciclo e o componente divergente.
+Nenhum dado é gravado em SQLite ou em arquivos de pesos.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		appCfg := &config.AppConfig{
+			SimParams: config.DefaultSimulationParameters(),
+			Cli: config.CLIConfig{
+				Mode:            config.ModeSim,
+				TotalNeurons:    verifyTotalNeurons,
+				Seed:            seed, // da flag global
+				Cycles:          verifyCycles,
+				StimInputID:     verifyStimInputID,
+				StimInputFreqHz: verifyStimInputFreqHz,
+				MonitorOutputID: -2,
+			},
+		}
+
+		if configFile != "" {
+			slog.Info("Loading configuration file", "path", configFile)
+			cliCfgBeforeToml := appCfg.Cli
+			if _, err := toml.DecodeFile(configFile, appCfg); err != nil {
+				slog.Warn("Could not decode configuration file, continuing with defaults and flags", "path", configFile, "err", err)
+				appCfg.Cli = cliCfgBeforeToml
+			}
+			// verify sempre compara execuções do modo sim, sem logging nem arquivos de pesos.
+			appCfg.Cli.Mode = config.ModeSim
+			appCfg.Cli.DbPath = ""
+			appCfg.Cli.SaveInterval = 0
+		}
+
+		if cmd.Flags().Changed("seed") {
+			appCfg.Cli.Seed = seed
+		}
+		if cmd.Flags().Changed("neurons") {
+			appCfg.Cli.TotalNeurons = verifyTotalNeurons
+		}
+		if cmd.Flags().Changed("cycles") {
+			appCfg.Cli.Cycles = verifyCycles
+		}
+		if cmd.Flags().Changed("stimInputID") {
+			appCfg.Cli.StimInputID = verifyStimInputID
+		}
+		if cmd.Flags().Changed("stimInputFreqHz") {
+			appCfg.Cli.StimInputFreqHz = verifyStimInputFreqHz
+		}
+
+		if err := appCfg.Validate(); err != nil {
+			return fmt.Errorf("configuração inválida para o modo verify: %w", err)
+		}
+
+		slog.Info("Comparing two runs", "neurons", appCfg.Cli.TotalNeurons, "cycles", appCfg.Cli.Cycles,
+			"seed", appCfg.Cli.Seed)
+		result, err := cli.VerifyReproducibility(appCfg)
+		if err != nil {
+			return fmt.Errorf("erro durante a execução do modo verify: %w", err)
+		}
+
+		if result.Diverged {
+			cycleDesc := fmt.Sprintf("ciclo %d", result.DivergentCycle)
+			if result.DivergentCycle < 0 {
+				cycleDesc = "estado inicial"
+			}
+			return fmt.Errorf("execuções divergiram no %s (componente: %s)", cycleDesc, result.Component)
+		}
+		fmt.Printf("Execuções idênticas em todos os %d estados comparados.\n", result.CyclesCompared)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().IntVarP(&verifyCycles, "cycles", "c", 200, "Total de ciclos a comparar.")
+	verifyCmd.Flags().IntVarP(&verifyTotalNeurons, "neurons", "n", 200, "Total de neurônios na rede.")
+	verifyCmd.Flags().IntVar(&verifyStimInputID, "stimInputID", -1,
+		"ID do neurônio de entrada para estímulo contínuo (-1: primeiro disponível, -2: desabilitado).")
+	verifyCmd.Flags().Float64Var(&verifyStimInputFreqHz, "stimInputFreqHz", 10.0,
+		"Frequência (Hz) para estímulo contínuo (0.0 desabilita).")
+}
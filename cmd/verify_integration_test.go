@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"crownet/cli"
+)
+
+func TestVerify_SameSeedRunsAreIdentical(t *testing.T) {
+	appCfg := newTestSimAppConfig(20, 50, "", 0)
+	appCfg.Cli.Seed = 42
+	appCfg.Cli.StimInputID = -1
+	appCfg.Cli.StimInputFreqHz = 20.0
+
+	if err := appCfg.Validate(); err != nil {
+		t.Fatalf("Constructed AppConfig is invalid: %v", err)
+	}
+
+	result, err := cli.VerifyReproducibility(appCfg)
+	if err != nil {
+		t.Fatalf("VerifyReproducibility() failed: %v", err)
+	}
+	if result.Diverged {
+		t.Fatalf("Runs with the same seed diverged at cycle %d in component %q", result.DivergentCycle, result.Component)
+	}
+	if want := appCfg.Cli.Cycles + 1; result.CyclesCompared != want {
+		t.Errorf("CyclesCompared = %d, want %d", result.CyclesCompared, want)
+	}
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// weightsCmd represents the base weights command
+var weightsCmd = &cobra.Command{
+	Use:   "weights",
+	Short: "Utilitários para arquivos de pesos sinápticos.",
+	Long: `O comando weights fornece subcomandos para manipular os arquivos de pesos
+gravados por 'expose' (JSON ou binário, opcionalmente comprimidos com gzip).`,
+}
+
+func init() {
+	rootCmd.AddCommand(weightsCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"crownet/storage"
+)
+
+var (
+	weightsConvertInput  string
+	weightsConvertOutput string
+	weightsConvertDType  string
+)
+
+// weightsConvertCmd represents the weights convert command
+var weightsConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Converte um arquivo de pesos entre JSON e o formato binário.",
+	Long: `Lê um arquivo de pesos e o grava em outro formato. O formato de cada arquivo
+é escolhido pela extensão: '.bin' é o formato binário compacto (cabeçalho com
+versão, número de neurônios e sinapses, tipo dos pesos e checksum CRC-32C),
+qualquer outra extensão é JSON. Um sufixo '.gz' adicional (ex: 'pesos.bin.gz')
+aplica compressão gzip de forma transparente.
+
+Se o arquivo de parâmetros dos neurônios que acompanha a entrada
+(ex: 'pesos.json.neurons.json') existir, ele é copiado para acompanhar a saída.
+
+Exemplo:
+  crownet weights convert --input pesos.json --output pesos.bin.gz --dtype float32`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		dtype, err := storage.ParseWeightDType(weightsConvertDType)
+		if err != nil {
+			return fmt.Errorf("flag --dtype inválida: %w", err)
+		}
+		weights, err := storage.LoadNetworkWeights(weightsConvertInput)
+		if err != nil {
+			return fmt.Errorf("erro ao ler pesos: %w", err)
+		}
+		if err := storage.SaveWeightsMap(weights, weightsConvertOutput, dtype); err != nil {
+			return fmt.Errorf("erro ao gravar pesos: %w", err)
+		}
+
+		synapses := 0
+		for _, row := range weights {
+			synapses += len(row)
+		}
+		slog.Info("Weights converted", "input", weightsConvertInput, "output", weightsConvertOutput,
+			"presynaptic_neurons", len(weights), "synapses", synapses)
+
+		inParams := storage.NeuronParamsPath(weightsConvertInput)
+		outParams := storage.NeuronParamsPath(weightsConvertOutput)
+		if inParams != outParams {
+			if data, err := os.ReadFile(inParams); err == nil {
+				if err := os.WriteFile(outParams, data, 0644); err != nil {
+					return fmt.Errorf("erro ao copiar parâmetros dos neurônios para %s: %w", outParams, err)
+				}
+				slog.Info("Neuron parameters copied", "input", inParams, "output", outParams)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	weightsCmd.AddCommand(weightsConvertCmd)
+
+	weightsConvertCmd.Flags().StringVarP(&weightsConvertInput, "input", "i", "",
+		"Arquivo de pesos de entrada (obrigatório).")
+	weightsConvertCmd.Flags().StringVarP(&weightsConvertOutput, "output", "o", "",
+		"Arquivo de pesos de saída (obrigatório).")
+	weightsConvertCmd.Flags().StringVar(&weightsConvertDType, "dtype", "float64",
+		"Tipo dos pesos no formato binário: 'float64' (sem perdas) ou 'float32' (metade do tamanho).")
+	for _, name := range []string{"input", "output"} {
+		if err := weightsConvertCmd.MarkFlagRequired(name); err != nil {
+			log.Printf("Warning: could not mark '%s' as required for weightsConvertCmd: %v", name, err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"flag"
 	"fmt"
 	"path/filepath" // Added for path cleaning
+	"slices"
 	"strings"
 	"time"
 
@@ -25,6 +26,123 @@ const (
 	ModeLogUtil = "logutil" // FEATURE-004
 )
 
+// Boundary condition modes for neuron movement (GeneralParams.BoundaryMode).
+const (
+	// BoundaryClamp clamps each coordinate of a neuron leaving the space to the hypercube faces.
+	BoundaryClamp = "clamp"
+	// BoundaryReflect reflects neurons elastically off the hypersphere surface.
+	BoundaryReflect = "reflect"
+	// BoundaryPeriodic wraps neurons around a hypercube (toroidal space).
+	BoundaryPeriodic = "periodic"
+)
+
+// SupportedBoundaryModes lists all valid values for GeneralParams.BoundaryMode.
+var SupportedBoundaryModes = []string{BoundaryClamp, BoundaryReflect, BoundaryPeriodic}
+
+// Topology generators used to create the initial synapses (TopologyParams.Generator).
+const (
+	// TopologyAllToAll connects every neuron to every other neuron.
+	TopologyAllToAll = "all_to_all"
+	// TopologyErdosRenyi connects each ordered pair independently with probability ConnectionProbability.
+	TopologyErdosRenyi = "erdos_renyi"
+	// TopologyDistance connects pairs with a probability that decays exponentially with their 16D distance.
+	TopologyDistance = "distance"
+	// TopologyWattsStrogatz builds a ring lattice over neuron IDs and rewires its edges (small-world).
+	TopologyWattsStrogatz = "watts_strogatz"
+	// TopologyFixedInDegree gives every neuron exactly InDegree randomly chosen presynaptic partners.
+	TopologyFixedInDegree = "fixed_indegree"
+)
+
+// SupportedTopologies lists all valid values for TopologyParams.Generator.
+var SupportedTopologies = []string{
+	TopologyAllToAll, TopologyErdosRenyi, TopologyDistance, TopologyWattsStrogatz, TopologyFixedInDegree,
+}
+
+// Weight distributions for connectivity rules (WeightDistribution.Distribution).
+const (
+	// WeightUniform draws weights uniformly from [Min, Max).
+	WeightUniform = "uniform"
+	// WeightNormal draws weights from a normal distribution with Mean and StdDev.
+	WeightNormal = "normal"
+	// WeightLogNormal draws exp(x) where x is normal with Mean and StdDev.
+	WeightLogNormal = "log_normal"
+)
+
+// SupportedWeightDistributions lists all valid values for WeightDistribution.Distribution.
+var SupportedWeightDistributions = []string{WeightUniform, WeightNormal, WeightLogNormal}
+
+// Synapse snapshot modes for the SQLite log (CLIConfig.SynapseLogMode).
+const (
+	// SynapseLogFull stores every synapse in each snapshot.
+	SynapseLogFull = "full"
+	// SynapseLogDelta stores only synapses whose weight changed by more than
+	// CLIConfig.SynapseLogThreshold since the value last stored for them.
+	SynapseLogDelta = "delta"
+)
+
+// SupportedSynapseLogModes lists all valid values for CLIConfig.SynapseLogMode.
+var SupportedSynapseLogModes = []string{SynapseLogFull, SynapseLogDelta}
+
+// Backpressure policies of the asynchronous SQLite log writer (CLIConfig.LogBackpressure),
+// applied when its queue of pending snapshots is full.
+const (
+	// LogBackpressureBlock makes the simulation wait until the writer has room.
+	LogBackpressureBlock = "block"
+	// LogBackpressureDrop discards the write and keeps simulating, so snapshots and
+	// batches of Spikes rows can be lost; the number of discarded writes is reported
+	// when the logger is closed.
+	LogBackpressureDrop = "drop"
+)
+
+// SupportedLogBackpressures lists all valid values for CLIConfig.LogBackpressure.
+var SupportedLogBackpressures = []string{LogBackpressureBlock, LogBackpressureDrop}
+
+// Digit orders of the expose curriculum (CLIConfig.CurriculumOrder).
+const (
+	// CurriculumSequential presents the digits from 0 to 9.
+	CurriculumSequential = "sequential"
+	// CurriculumShuffled draws a new digit order, from the run's seed, for every epoch
+	// (for every pass over the digits with the interleaved schedule).
+	CurriculumShuffled = "shuffled"
+)
+
+// SupportedCurriculumOrders lists all valid values for CLIConfig.CurriculumOrder.
+var SupportedCurriculumOrders = []string{CurriculumSequential, CurriculumShuffled}
+
+// Schedules of the expose curriculum (CLIConfig.CurriculumSchedule), which decide how
+// the repeated presentations of each digit in an epoch are arranged.
+const (
+	// CurriculumInterleaved presents every digit once per pass, with as many passes as
+	// the largest repetition count.
+	CurriculumInterleaved = "interleaved"
+	// CurriculumBlocked presents all the repetitions of a digit in a row.
+	CurriculumBlocked = "blocked"
+)
+
+// SupportedCurriculumSchedules lists all valid values for CLIConfig.CurriculumSchedule.
+var SupportedCurriculumSchedules = []string{CurriculumInterleaved, CurriculumBlocked}
+
+// SupportedLogTables lists the SQLite log tables that logutil can export.
+var SupportedLogTables = []string{"Runs", "NetworkSnapshots", "NeuronStates", "Spikes", "SynapseSnapshots"}
+
+// Export formats for logutil export (CLIConfig.LogUtilFormat).
+const (
+	// LogFormatCSV writes one CSV record per row; positions and velocities are JSON arrays in a cell.
+	LogFormatCSV = "csv"
+	// LogFormatNDJSON writes one JSON object per line, keeping positions and velocities as arrays.
+	LogFormatNDJSON = "ndjson"
+	// LogFormatArrow writes an Arrow IPC (Feather v2) file with typed columns; positions and
+	// velocities are split into one Float64 column per dimension.
+	LogFormatArrow = "arrow"
+)
+
+// SupportedLogFormats lists all valid values for CLIConfig.LogUtilFormat.
+var SupportedLogFormats = []string{LogFormatCSV, LogFormatNDJSON, LogFormatArrow}
+
+// NeuronTypeNames lists the neuron type names accepted in ConnectivityRule.Pre/Post
+// (matched case-insensitively against neuron.Type.String()).
+var NeuronTypeNames = []string{"Excitatory", "Inhibitory", "Dopaminergic", "Input", "Output"}
+
 // SupportedModes lists all valid operation modes for the application.
 // It is used for validating the mode provided via CLI or configuration file.
 var SupportedModes = []string{ModeSim, ModeExpose, ModeObserve, ModeLogUtil} // FEATURE-004: Added ModeLogUtil
@@ -36,82 +154,169 @@ var SupportedModes = []string{ModeSim, ModeExpose, ModeObserve, ModeLogUtil} //
 
 // GeneralParams defines general spatial and time-related simulation parameters.
 type GeneralParams struct {
-	SpaceMaxDimension     float64     // Boundary of the N-dimensional simulation space.
-	CyclesPerSecond       float64     // Simulation cycles representing one second of real time.
-	PulsePropagationSpeed common.Rate // Speed at which pulses travel in the space.
+	SpaceMaxDimension     float64     `toml:"space_max_dimension"`     // Boundary of the N-dimensional simulation space.
+	CyclesPerSecond       float64     `toml:"cycles_per_second"`       // Simulation cycles representing one second of real time.
+	PulsePropagationSpeed common.Rate `toml:"pulse_propagation_speed"` // Speed at which pulses travel in the space.
+	BoundaryMode          string      `toml:"boundary_mode"`           // How neurons are kept inside the space: "clamp", "reflect" or "periodic".
 }
 
 // NeuronBehaviorParams defines parameters related to individual neuron behavior.
+// The four scalar values are the defaults for every neuron; TypeOverrides replace
+// them per neuron type and the jitter settings then vary them per neuron.
 type NeuronBehaviorParams struct {
-	BaseFiringThreshold       common.Threshold  // Base threshold for a neuron to fire.
-	AccumulatedPulseDecayRate common.Rate       // Rate at which accumulated pulse potential decays.
-	AbsoluteRefractoryCycles  common.CycleCount // Cycles a neuron cannot fire after firing.
-	RelativeRefractoryCycles  common.CycleCount // Cycles a neuron has increased threshold after firing.
+	BaseFiringThreshold       common.Threshold     `toml:"base_firing_threshold"`        // Base threshold for a neuron to fire.
+	AccumulatedPulseDecayRate common.Rate          `toml:"accumulated_pulse_decay_rate"` // Rate at which accumulated pulse potential decays.
+	AbsoluteRefractoryCycles  common.CycleCount    `toml:"absolute_refractory_cycles"`   // Cycles a neuron cannot fire after firing.
+	RelativeRefractoryCycles  common.CycleCount    `toml:"relative_refractory_cycles"`   // Cycles a neuron has increased threshold after firing.
+	TypeOverrides             []NeuronTypeOverride `toml:"type_overrides"`               // Per-type replacements of the values above.
+	ThresholdJitter           ParameterJitter      `toml:"threshold_jitter"`             // Per-neuron variation of BaseFiringThreshold.
+	DecayJitter               ParameterJitter      `toml:"decay_jitter"`                 // Per-neuron variation of AccumulatedPulseDecayRate.
+	RefractoryJitter          ParameterJitter      `toml:"refractory_jitter"`            // Per-neuron variation of both refractory lengths.
+}
+
+// NeuronTypeOverride replaces NeuronBehaviorParams values for all neurons of one type.
+// Fields left unset keep the global value.
+type NeuronTypeOverride struct {
+	Type                      string             `toml:"type"` // Neuron type (see NeuronTypeNames).
+	BaseFiringThreshold       *common.Threshold  `toml:"base_firing_threshold"`
+	AccumulatedPulseDecayRate *common.Rate       `toml:"accumulated_pulse_decay_rate"`
+	AbsoluteRefractoryCycles  *common.CycleCount `toml:"absolute_refractory_cycles"`
+	RelativeRefractoryCycles  *common.CycleCount `toml:"relative_refractory_cycles"`
+}
+
+// ParameterJitter describes seeded random per-neuron variation of a parameter.
+// Each neuron's value is multiplied by a factor with mean 1 and the given relative
+// standard deviation, clipped to [MinFactor, MaxFactor].
+type ParameterJitter struct {
+	Distribution   string  `toml:"distribution"`     // WeightNormal or WeightLogNormal; empty disables jitter.
+	RelativeStdDev float64 `toml:"relative_std_dev"` // Standard deviation of the factor (coefficient of variation).
+	MinFactor      float64 `toml:"min_factor"`       // Lower clipping bound of the factor.
+	MaxFactor      float64 `toml:"max_factor"`       // Upper clipping bound of the factor.
+}
+
+// Enabled reports whether the jitter draws random factors.
+func (j *ParameterJitter) Enabled() bool {
+	return j.Distribution != ""
 }
 
 // NeuronDistributionParams defines parameters for neuron type distribution and influence radii.
 type NeuronDistributionParams struct {
-	DopaminergicPercent      common.Percentage // Percentage of internal neurons that are dopaminergic.
-	InhibitoryPercent        common.Percentage // Percentage of internal neurons that are inhibitory.
-	ExcitatoryRadiusFactor   common.Factor     // Factor for excitatory neuron influence radius.
-	DopaminergicRadiusFactor common.Factor     // Factor for dopaminergic neuron influence radius.
-	InhibitoryRadiusFactor   common.Factor     // Factor for inhibitory neuron influence radius.
+	DopaminergicPercent      common.Percentage `toml:"dopaminergic_percent"`       // Percentage of internal neurons that are dopaminergic.
+	InhibitoryPercent        common.Percentage `toml:"inhibitory_percent"`         // Percentage of internal neurons that are inhibitory.
+	ExcitatoryRadiusFactor   common.Factor     `toml:"excitatory_radius_factor"`   // Factor for excitatory neuron influence radius.
+	DopaminergicRadiusFactor common.Factor     `toml:"dopaminergic_radius_factor"` // Factor for dopaminergic neuron influence radius.
+	InhibitoryRadiusFactor   common.Factor     `toml:"inhibitory_radius_factor"`   // Factor for inhibitory neuron influence radius.
 }
 
 // NetworkStructureParams defines parameters for overall network structure like I/O neurons.
 type NetworkStructureParams struct {
-	MinInputNeurons             int     // Minimum number of input neurons required.
-	MinOutputNeurons            int     // Minimum number of output neurons required.
-	OutputFrequencyWindowCycles float64 // Number of cycles to average output neuron firing frequency.
+	MinInputNeurons             int     `toml:"min_input_neurons"`              // Minimum number of input neurons required.
+	MinOutputNeurons            int     `toml:"min_output_neurons"`             // Minimum number of output neurons required.
+	OutputFrequencyWindowCycles float64 `toml:"output_frequency_window_cycles"` // Number of cycles to average output neuron firing frequency.
 }
 
 // PatternParams defines parameters related to input/output patterns.
 type PatternParams struct {
-	PatternHeight int // Height of the input patterns (e.g., for digits).
-	PatternWidth  int // Width of the input patterns.
-	PatternSize   int // Total size of the input patterns (Height * Width).
+	PatternHeight int `toml:"pattern_height"` // Height of the input patterns (e.g., for digits).
+	PatternWidth  int `toml:"pattern_width"`  // Width of the input patterns.
+	PatternSize   int `toml:"pattern_size"`   // Total size of the input patterns (Height * Width).
 }
 
 // LearningParams defines parameters for synaptic weights and learning rules.
 type LearningParams struct {
-	InitialSynapticWeightMin    common.SynapticWeight // Minimum initial synaptic weight.
-	InitialSynapticWeightMax    common.SynapticWeight // Maximum initial synaptic weight.
-	MaxSynapticWeight           common.SynapticWeight // Absolute maximum for any synaptic weight.
-	HebbianWeightMin            common.SynapticWeight // Minimum weight for Hebbian learning (can be negative).
-	HebbianWeightMax            common.SynapticWeight // Maximum weight for Hebbian learning.
-	SynapticWeightDecayRate     common.Rate           // Rate at which synaptic weights decay per cycle.
-	HebbianCoincidenceWindow    common.CycleCount     // Time window (cycles) for Hebbian learning co-activation.
-	HebbPositiveReinforceFactor common.Factor         // Factor for strengthening synaptic weights in Hebbian learning.
-	HebbNegativeReinforceFactor common.Factor         // Factor for weakening synaptic weights (if applicable, or for LTD).
-	MinLearningRateFactor       common.Factor         // Minimum modulation factor for learning rate.
+	InitialSynapticWeightMin    common.SynapticWeight `toml:"initial_synaptic_weight_min"`    // Minimum initial synaptic weight.
+	InitialSynapticWeightMax    common.SynapticWeight `toml:"initial_synaptic_weight_max"`    // Maximum initial synaptic weight.
+	MaxSynapticWeight           common.SynapticWeight `toml:"max_synaptic_weight"`            // Absolute maximum for any synaptic weight.
+	HebbianWeightMin            common.SynapticWeight `toml:"hebbian_weight_min"`             // Minimum weight for Hebbian learning (can be negative).
+	HebbianWeightMax            common.SynapticWeight `toml:"hebbian_weight_max"`             // Maximum weight for Hebbian learning.
+	SynapticWeightDecayRate     common.Rate           `toml:"synaptic_weight_decay_rate"`     // Rate at which synaptic weights decay per cycle.
+	HebbianCoincidenceWindow    common.CycleCount     `toml:"hebbian_coincidence_window"`     // Time window (cycles) for Hebbian learning co-activation.
+	HebbPositiveReinforceFactor common.Factor         `toml:"hebb_positive_reinforce_factor"` // Factor for strengthening synaptic weights in Hebbian learning.
+	HebbNegativeReinforceFactor common.Factor         `toml:"hebb_negative_reinforce_factor"` // Factor for weakening synaptic weights (if applicable, or for LTD).
+	MinLearningRateFactor       common.Factor         `toml:"min_learning_rate_factor"`       // Minimum modulation factor for learning rate.
+}
+
+// TopologyParams selects and parameterises the generator that creates the initial synapses.
+// Only the fields used by the selected generator are read.
+type TopologyParams struct {
+	Generator                   string  `toml:"generator"`                      // One of SupportedTopologies; empty means all-to-all.
+	ConnectionProbability       float64 `toml:"connection_probability"`         // Erdős–Rényi: probability of each directed connection.
+	DistanceMaxProbability      float64 `toml:"distance_max_probability"`       // Distance: connection probability at zero distance.
+	DistanceLengthScale         float64 `toml:"distance_length_scale"`          // Distance: length over which the probability falls by a factor of e.
+	SmallWorldNeighbors         int     `toml:"small_world_neighbors"`          // Watts–Strogatz: lattice degree K (even, K/2 neighbours per side).
+	SmallWorldRewireProbability float64 `toml:"small_world_rewire_probability"` // Watts–Strogatz: probability of rewiring each lattice edge.
+	InDegree                    int     `toml:"in_degree"`                      // Fixed in-degree: presynaptic partners per neuron.
+}
+
+// WeightDistribution describes how initial weights are drawn for a connectivity rule.
+// Drawn weights are clamped to the same limits as any other weight (see synaptic.SetWeight).
+type WeightDistribution struct {
+	Distribution string  `toml:"distribution"` // One of SupportedWeightDistributions; empty means uniform.
+	Min          float64 `toml:"min"`          // Uniform: lower bound.
+	Max          float64 `toml:"max"`          // Uniform: upper bound.
+	Mean         float64 `toml:"mean"`         // Normal: mean. Log-normal: mean of the underlying normal.
+	StdDev       float64 `toml:"std_dev"`      // Normal: standard deviation. Log-normal: std. dev. of the underlying normal.
+}
+
+// ConnectivityRule sets the connection probability and initial weight distribution
+// for synapses from neurons of type Pre to neurons of type Post. Rules require the
+// all_to_all topology, so Probability is the actual probability that each ordered
+// pair of that type pair is connected. Pairs without a rule are all connected.
+type ConnectivityRule struct {
+	Pre         string             `toml:"pre"`         // Presynaptic neuron type (see NeuronTypeNames).
+	Post        string             `toml:"post"`        // Postsynaptic neuron type (see NeuronTypeNames).
+	Probability float64            `toml:"probability"` // Probability that each ordered pair is connected.
+	Weight      WeightDistribution `toml:"weight"`      // Distribution of initial weights for kept connections; required if Probability > 0.
+}
+
+// HomeostasisParams configures homeostatic intrinsic plasticity: each neuron's base
+// firing threshold slowly moves so that its firing rate (measured from FiringHistory
+// over Structure.OutputFrequencyWindowCycles) approaches a target rate.
+// Neurochemical threshold modulation still applies on top of the adapted base threshold.
+type HomeostasisParams struct {
+	Enabled            bool                      `toml:"enabled"`               // Turns the mechanism on for all types unless overridden.
+	TargetFiringRateHz float64                   `toml:"target_firing_rate_hz"` // Firing rate each neuron is driven towards.
+	TimeConstantCycles float64                   `toml:"time_constant_cycles"`  // Cycles over which the threshold adapts (larger is slower).
+	MinThreshold       common.Threshold          `toml:"min_threshold"`         // Lower bound for adapted base thresholds.
+	MaxThreshold       common.Threshold          `toml:"max_threshold"`         // Upper bound for adapted base thresholds.
+	TypeOverrides      []HomeostasisTypeOverride `toml:"type_overrides"`        // Per-type replacements of the values above.
+}
+
+// HomeostasisTypeOverride replaces HomeostasisParams values for one neuron type.
+// Fields left unset keep the global value.
+type HomeostasisTypeOverride struct {
+	Type               string   `toml:"type"` // Neuron type (see NeuronTypeNames).
+	Enabled            *bool    `toml:"enabled"`
+	TargetFiringRateHz *float64 `toml:"target_firing_rate_hz"`
+	TimeConstantCycles *float64 `toml:"time_constant_cycles"`
 }
 
 // SynaptogenesisParams defines parameters for neuronal movement and structural plasticity.
 type SynaptogenesisParams struct {
-	SynaptogenesisInfluenceRadius common.Coordinate // Radius within which neurons influence each other for movement.
-	AttractionForceFactor         common.Factor     // Factor for attractive forces between neurons.
-	RepulsionForceFactor          common.Factor     // Factor for repulsive forces between neurons.
-	DampeningFactor               common.Factor     // Dampening factor for neuron movement.
-	MaxMovementPerCycle           common.Coordinate // Maximum distance a neuron can move in one cycle.
+	SynaptogenesisInfluenceRadius common.Coordinate `toml:"synaptogenesis_influence_radius"` // Radius within which neurons influence each other for movement.
+	AttractionForceFactor         common.Factor     `toml:"attraction_force_factor"`         // Factor for attractive forces between neurons.
+	RepulsionForceFactor          common.Factor     `toml:"repulsion_force_factor"`          // Factor for repulsive forces between neurons.
+	DampeningFactor               common.Factor     `toml:"dampening_factor"`                // Dampening factor for neuron movement.
+	MaxMovementPerCycle           common.Coordinate `toml:"max_movement_per_cycle"`          // Maximum distance a neuron can move in one cycle.
 }
 
 // NeurochemicalParams defines parameters for the neurochemical system and its influences.
 type NeurochemicalParams struct {
-	CortisolProductionRate        common.Rate   // Base rate of cortisol production.
-	CortisolDecayRate             common.Rate   // Rate at which cortisol decays.
-	CortisolProductionPerHit      common.Level  // Amount of cortisol produced per 'stress' event.
-	CortisolMaxLevel              common.Level  // Maximum possible cortisol level.
-	CortisolGlandPosition         common.Point  // Fixed N-dimensional coordinates of the cortisol gland.
-	DopamineProductionRate        common.Rate   // Base rate of dopamine production.
-	DopamineDecayRate             common.Rate   // Rate at which dopamine decays.
-	DopamineProductionPerEvent    common.Level  // Amount of dopamine produced per 'reward' event.
-	DopamineMaxLevel              common.Level  // Maximum possible dopamine level.
-	CortisolInfluenceOnLR         common.Factor // How cortisol influences the learning rate.
-	DopamineInfluenceOnLR         common.Factor // How dopamine influences the learning rate.
-	CortisolInfluenceOnSynapto    common.Factor // How cortisol influences synaptogenesis.
-	DopamineInfluenceOnSynapto    common.Factor // How dopamine influences synaptogenesis.
-	FiringThresholdIncreaseOnDopa common.Factor // How dopamine influences neuron firing thresholds.
-	FiringThresholdIncreaseOnCort common.Factor // How cortisol influences neuron firing thresholds.
+	CortisolProductionRate        common.Rate   `toml:"cortisol_production_rate"`          // Base rate of cortisol production.
+	CortisolDecayRate             common.Rate   `toml:"cortisol_decay_rate"`               // Rate at which cortisol decays.
+	CortisolProductionPerHit      common.Level  `toml:"cortisol_production_per_hit"`       // Amount of cortisol produced per 'stress' event.
+	CortisolMaxLevel              common.Level  `toml:"cortisol_max_level"`                // Maximum possible cortisol level.
+	CortisolGlandPosition         common.Point  `toml:"cortisol_gland_position"`           // Fixed N-dimensional coordinates of the cortisol gland.
+	DopamineProductionRate        common.Rate   `toml:"dopamine_production_rate"`          // Base rate of dopamine production.
+	DopamineDecayRate             common.Rate   `toml:"dopamine_decay_rate"`               // Rate at which dopamine decays.
+	DopamineProductionPerEvent    common.Level  `toml:"dopamine_production_per_event"`     // Amount of dopamine produced per 'reward' event.
+	DopamineMaxLevel              common.Level  `toml:"dopamine_max_level"`                // Maximum possible dopamine level.
+	CortisolInfluenceOnLR         common.Factor `toml:"cortisol_influence_on_lr"`          // How cortisol influences the learning rate.
+	DopamineInfluenceOnLR         common.Factor `toml:"dopamine_influence_on_lr"`          // How dopamine influences the learning rate.
+	CortisolInfluenceOnSynapto    common.Factor `toml:"cortisol_influence_on_synapto"`     // How cortisol influences synaptogenesis.
+	DopamineInfluenceOnSynapto    common.Factor `toml:"dopamine_influence_on_synapto"`     // How dopamine influences synaptogenesis.
+	FiringThresholdIncreaseOnDopa common.Factor `toml:"firing_threshold_increase_on_dopa"` // How dopamine influences neuron firing thresholds.
+	FiringThresholdIncreaseOnCort common.Factor `toml:"firing_threshold_increase_on_cort"` // How cortisol influences neuron firing thresholds.
 }
 
 // SimulationParameters is the main struct holding all simulation parameters, grouped into sub-structs.
@@ -122,6 +327,9 @@ type SimulationParameters struct {
 	Structure      NetworkStructureParams   `toml:"structure"`
 	Pattern        PatternParams            `toml:"pattern"`
 	Learning       LearningParams           `toml:"learning"`
+	Topology       TopologyParams           `toml:"topology"`
+	Connectivity   []ConnectivityRule       `toml:"connectivity"`
+	Homeostasis    HomeostasisParams        `toml:"homeostasis"`
 	Synaptogenesis SynaptogenesisParams     `toml:"synaptogenesis"`
 	Neurochemical  NeurochemicalParams      `toml:"neurochemical"`
 }
@@ -129,27 +337,55 @@ type SimulationParameters struct {
 // CLIConfig holds configuration parameters that are typically set or overridden
 // via command-line flags. It includes general settings as well as mode-specific options.
 type CLIConfig struct {
-	Mode              string      `json:"mode"`
-	LogUtilOutput     string      `json:"logutil_output"`
-	LogUtilFormat     string      `json:"logutil_format"`
-	WeightsFile       string      `json:"weights_file"`
-	LogUtilTable      string      `json:"logutil_table"`
-	LogUtilDbPath     string      `json:"logutil_dbpath"`
-	DbPath            string      `json:"db_path"`
-	LogUtilSubcommand string      `json:"logutil_subcommand"`
-	MonitorOutputID   int         `json:"monitor_output_id"`
-	StimInputFreqHz   float64     `json:"stim_input_freq_hz"`
-	StimInputID       int         `json:"stim_input_id"`
-	Epochs            int         `json:"epochs"`
-	CyclesPerPattern  int         `json:"cycles_per_pattern"`
-	Digit             int         `json:"digit"`
-	CyclesToSettle    int         `json:"cycles_to_settle"`
-	SaveInterval      int         `json:"save_interval"`
-	Cycles            int         `json:"cycles"`
-	BaseLearningRate  common.Rate `json:"base_learning_rate"`
-	Seed              int64       `json:"seed"`
-	TotalNeurons      int         `json:"total_neurons"`
-	DebugChem         bool        `json:"debug_chem"`
+	Mode              string      `json:"mode" toml:"mode"`
+	LogUtilOutput     string      `json:"logutil_output" toml:"logutil_output"`
+	LogUtilFormat     string      `json:"logutil_format" toml:"logutil_format"`
+	WeightsFile       string      `json:"weights_file" toml:"weights_file"`
+	LogUtilTable      string      `json:"logutil_table" toml:"logutil_table"`
+	LogUtilDbPath     string      `json:"logutil_dbpath" toml:"logutil_dbpath"`
+	DbPath            string      `json:"db_path" toml:"db_path"`
+	LogUtilSubcommand string      `json:"logutil_subcommand" toml:"logutil_subcommand"`
+	LogUtilRun        string      `json:"logutil_run" toml:"logutil_run"` // Restrict logutil export to this run ID (empty: all runs).
+	MonitorOutputID   int         `json:"monitor_output_id" toml:"monitor_output_id"`
+	StimInputFreqHz   float64     `json:"stim_input_freq_hz" toml:"stim_input_freq_hz"`
+	StimInputID       int         `json:"stim_input_id" toml:"stim_input_id"`
+	StimulusFile      string      `json:"stimulus_file" toml:"stimulus_file"` // Stimulus schedule of sim mode (see LoadStimulusSchedule); empty disables it.
+	Epochs            int         `json:"epochs" toml:"epochs"`
+	CyclesPerPattern  int         `json:"cycles_per_pattern" toml:"cycles_per_pattern"`
+	Digit             int         `json:"digit" toml:"digit"`
+	CyclesToSettle    int         `json:"cycles_to_settle" toml:"cycles_to_settle"`
+	SaveInterval      int         `json:"save_interval" toml:"save_interval"`
+	Cycles            int         `json:"cycles" toml:"cycles"`
+	BaseLearningRate  common.Rate `json:"base_learning_rate" toml:"base_learning_rate"`
+	Seed              int64       `json:"seed" toml:"seed"`
+	TotalNeurons      int         `json:"total_neurons" toml:"total_neurons"`
+	DebugChem         bool        `json:"debug_chem" toml:"debug_chem"`
+	LogSpikes         bool        `json:"log_spikes" toml:"log_spikes"` // Record every firing in the Spikes table (sim/expose with DbPath).
+	// Exposure curriculum (expose).
+	CurriculumOrder     string `json:"curriculum_order" toml:"curriculum_order"`             // One of SupportedCurriculumOrders; empty means sequential.
+	CurriculumSchedule  string `json:"curriculum_schedule" toml:"curriculum_schedule"`       // One of SupportedCurriculumSchedules; empty means interleaved.
+	PatternRepeats      int    `json:"pattern_repeats" toml:"pattern_repeats"`               // Presentations of each digit per epoch; 0 means 1.
+	DigitRepeats        []int  `json:"digit_repeats" toml:"digit_repeats"`                   // Presentations per epoch of digits 0-9 (10 values, 0 skips a digit); overrides PatternRepeats.
+	MaxCyclesPerPattern int    `json:"max_cycles_per_pattern" toml:"max_cycles_per_pattern"` // If above CyclesPerPattern, presentations last a random number of cycles in between.
+	RestCycles          int    `json:"rest_cycles" toml:"rest_cycles"`                       // Cycles run without input after each presentation.
+	// Model bundle (network layout, weights and SimulationParameters) that observe builds the
+	// network from and expose resumes from (if it exists) and saves to; empty disables it.
+	ModelFile string `json:"model_file" toml:"model_file"`
+	// Synaptic weight history (SynapseSnapshots table, sim/expose with DbPath).
+	SynapseLogInterval  int     `json:"synapse_log_interval" toml:"synapse_log_interval"`   // Cycles between synapse snapshots; 0 disables.
+	SynapseLogMode      string  `json:"synapse_log_mode" toml:"synapse_log_mode"`           // One of SupportedSynapseLogModes; empty means full.
+	SynapseLogThreshold float64 `json:"synapse_log_threshold" toml:"synapse_log_threshold"` // Delta mode: minimum weight change stored.
+	// Asynchronous SQLite writer (sim/expose with DbPath).
+	LogQueueSize    int    `json:"log_queue_size" toml:"log_queue_size"`     // Snapshots waiting to be written; 0 uses the default.
+	LogBackpressure string `json:"log_backpressure" toml:"log_backpressure"` // One of SupportedLogBackpressures; empty means block.
+	// Live Server-Sent Events stream of per-cycle metrics (sim/expose).
+	StreamAddr  string `json:"stream_addr" toml:"stream_addr"`   // Address (host:port) of the stream; empty disables it.
+	StreamEvery int    `json:"stream_every" toml:"stream_every"` // Cycles per streamed record; 0 means every cycle.
+	// Prometheus text-format metrics endpoint (sim/expose).
+	MetricsAddr string `json:"metrics_addr" toml:"metrics_addr"` // Address (host:port) of GET /metrics; empty disables it.
+	// "key=value" assignments from --set (see AppConfig.ApplyOverrides). They are applied
+	// before validation and again after a model bundle replaces SimParams.
+	Overrides []string `json:"overrides,omitempty" toml:"-"`
 }
 
 // AppConfig is the top-level configuration structure, aggregating both
@@ -168,12 +404,16 @@ func DefaultSimulationParameters() SimulationParameters {
 			SpaceMaxDimension:     10.0,
 			CyclesPerSecond:       100.0,
 			PulsePropagationSpeed: 1.0,
+			BoundaryMode:          BoundaryClamp,
 		},
 		NeuronBehavior: NeuronBehaviorParams{
 			BaseFiringThreshold:       1.0,
 			AccumulatedPulseDecayRate: common.Rate(0.1),
 			AbsoluteRefractoryCycles:  common.CycleCount(2),
 			RelativeRefractoryCycles:  common.CycleCount(3),
+			ThresholdJitter:           ParameterJitter{RelativeStdDev: 0.1, MinFactor: 0.5, MaxFactor: 1.5},
+			DecayJitter:               ParameterJitter{RelativeStdDev: 0.1, MinFactor: 0.5, MaxFactor: 1.5},
+			RefractoryJitter:          ParameterJitter{RelativeStdDev: 0.2, MinFactor: 0.5, MaxFactor: 2.0},
 		},
 		Distribution: NeuronDistributionParams{
 			DopaminergicPercent:      0.1,
@@ -204,6 +444,22 @@ func DefaultSimulationParameters() SimulationParameters {
 			HebbNegativeReinforceFactor: common.Factor(0.05),
 			MinLearningRateFactor:       common.Factor(0.1),
 		},
+		Topology: TopologyParams{
+			Generator:                   TopologyAllToAll,
+			ConnectionProbability:       0.1,
+			DistanceMaxProbability:      0.5,
+			DistanceLengthScale:         2.0,
+			SmallWorldNeighbors:         10,
+			SmallWorldRewireProbability: 0.1,
+			InDegree:                    20,
+		},
+		Homeostasis: HomeostasisParams{
+			Enabled:            false,
+			TargetFiringRateHz: 5.0,
+			TimeConstantCycles: 1000.0,
+			MinThreshold:       0.1,
+			MaxThreshold:       10.0,
+		},
 		Synaptogenesis: SynaptogenesisParams{
 			SynaptogenesisInfluenceRadius: common.Coordinate(2.0),
 			AttractionForceFactor:         common.Factor(0.01),
@@ -231,6 +487,74 @@ func DefaultSimulationParameters() SimulationParameters {
 	}
 }
 
+// DefaultCLIConfig returns the CLIConfig defaults of the command for mode, the same
+// values its flags default to. Only the sim command logs to SQLite by default.
+func DefaultCLIConfig(mode string) CLIConfig {
+	cfg := CLIConfig{
+		Mode:               mode,
+		TotalNeurons:       200,
+		WeightsFile:        "crownet_weights.json",
+		BaseLearningRate:   common.Rate(0.01),
+		Cycles:             1000,
+		StimInputID:        -1,
+		MonitorOutputID:    -1,
+		SynapseLogMode:     SynapseLogFull,
+		LogBackpressure:    LogBackpressureBlock,
+		Epochs:             50,
+		CyclesPerPattern:   20,
+		CurriculumOrder:    CurriculumSequential,
+		CurriculumSchedule: CurriculumInterleaved,
+		PatternRepeats:     1,
+		CyclesToSettle:     50,
+		LogUtilSubcommand:  "export",
+		LogUtilFormat:      LogFormatCSV,
+	}
+	if mode == ModeSim {
+		cfg.DbPath = "crownet_sim_run.db"
+		cfg.SaveInterval = 100
+	}
+	return cfg
+}
+
+// DefaultAppConfig returns the default configuration of the command for mode.
+func DefaultAppConfig(mode string) *AppConfig {
+	return &AppConfig{SimParams: DefaultSimulationParameters(), Cli: DefaultCLIConfig(mode)}
+}
+
+// Clone returns a deep copy of ac: the slices of rules, overrides and repeats, and
+// the optional values of the type overrides, are copied rather than shared, so the
+// copy can be changed without affecting ac.
+func (ac *AppConfig) Clone() *AppConfig {
+	c := *ac
+	c.SimParams.Connectivity = slices.Clone(ac.SimParams.Connectivity)
+	c.SimParams.NeuronBehavior.TypeOverrides = slices.Clone(ac.SimParams.NeuronBehavior.TypeOverrides)
+	for i := range c.SimParams.NeuronBehavior.TypeOverrides {
+		o := &c.SimParams.NeuronBehavior.TypeOverrides[i]
+		o.BaseFiringThreshold = clonePtr(o.BaseFiringThreshold)
+		o.AccumulatedPulseDecayRate = clonePtr(o.AccumulatedPulseDecayRate)
+		o.AbsoluteRefractoryCycles = clonePtr(o.AbsoluteRefractoryCycles)
+		o.RelativeRefractoryCycles = clonePtr(o.RelativeRefractoryCycles)
+	}
+	c.SimParams.Homeostasis.TypeOverrides = slices.Clone(ac.SimParams.Homeostasis.TypeOverrides)
+	for i := range c.SimParams.Homeostasis.TypeOverrides {
+		o := &c.SimParams.Homeostasis.TypeOverrides[i]
+		o.Enabled = clonePtr(o.Enabled)
+		o.TargetFiringRateHz = clonePtr(o.TargetFiringRateHz)
+		o.TimeConstantCycles = clonePtr(o.TimeConstantCycles)
+	}
+	c.Cli.DigitRepeats = slices.Clone(ac.Cli.DigitRepeats)
+	c.Cli.Overrides = slices.Clone(ac.Cli.Overrides)
+	return &c
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
 // LoadCLIConfig populates a CLIConfig struct by parsing flags from the given
 // arguments string slice using the provided FlagSet.
 //
@@ -268,6 +592,8 @@ func LoadCLIConfig(fSet *flag.FlagSet, args []string) (CLIConfig, error) {
 	fSet.Int64Var(&cfg.Seed, "seed", 0,
 		"Seed for random number generator (0 uses current time, other values are used directly).")
 	fSet.StringVar(&cfg.WeightsFile, "weightsFile", "crownet_weights.json", "File to save/load synaptic weights.")
+	fSet.StringVar(&cfg.ModelFile, "modelFile", "",
+		"Model bundle (layout, weights and parameters) for 'observe' to load and 'expose' to resume from and save to.")
 	fSet.Float64Var((*float64)(&cfg.BaseLearningRate), "lrBase", 0.01, "Base learning rate for Hebbian plasticity.")
 
 	// Mode 'sim' Specific Flags
@@ -281,12 +607,34 @@ func LoadCLIConfig(fSet *flag.FlagSet, args []string) (CLIConfig, error) {
 		"Frequency (Hz) for general stimulus in 'sim' mode (0.0 to disable).")
 	fSet.IntVar(&cfg.MonitorOutputID, "monitorOutputID", -1,
 		"ID of an output neuron to monitor for frequency reporting in 'sim' mode (-1 for first available, -2 to disable).")
+	fSet.StringVar(&cfg.StimulusFile, "stimulusFile", "",
+		"Stimulus schedule (TOML or JSON) of timed input events for 'sim' mode (empty to disable).")
 	fSet.BoolVar(&cfg.DebugChem, "debugChem", false, "Enable debug prints for chemical production.")
+	fSet.BoolVar(&cfg.LogSpikes, "logSpikes", false, "Record every neuron firing in the Spikes table of the DB.")
+	fSet.IntVar(&cfg.SynapseLogInterval, "synapseLogInterval", 0,
+		"Cycle interval for storing synaptic weights in the DB (0 to disable).")
+	fSet.StringVar(&cfg.SynapseLogMode, "synapseLogMode", SynapseLogFull,
+		fmt.Sprintf("Synapse snapshot mode: '%s' or '%s'.", SynapseLogFull, SynapseLogDelta))
+	fSet.Float64Var(&cfg.SynapseLogThreshold, "synapseLogThreshold", 0.0,
+		"Minimum weight change stored by delta synapse snapshots.")
+	fSet.IntVar(&cfg.LogQueueSize, "logQueue", 0,
+		"Snapshots queued for the background DB writer (0 for the default).")
+	fSet.StringVar(&cfg.LogBackpressure, "logBackpressure", LogBackpressureBlock,
+		fmt.Sprintf("What to do when the DB writer queue is full: '%s' (wait) or '%s' (discard the write; "+
+			"snapshots and spike raster rows can be lost).", LogBackpressureBlock, LogBackpressureDrop))
 
 	// Mode 'expose' Specific Flags
 	fSet.IntVar(&cfg.Epochs, "epochs", 50, "Number of exposure epochs (for 'expose' mode).")
 	fSet.IntVar(&cfg.CyclesPerPattern, "cyclesPerPattern", 20,
 		"Number of cycles to run per pattern presentation during 'expose' mode.")
+	fSet.StringVar(&cfg.CurriculumOrder, "curriculumOrder", CurriculumSequential,
+		fmt.Sprintf("Digit order of each epoch in 'expose' mode: '%s' or '%s'.", CurriculumSequential, CurriculumShuffled))
+	fSet.StringVar(&cfg.CurriculumSchedule, "curriculumSchedule", CurriculumInterleaved,
+		fmt.Sprintf("Arrangement of repeated presentations in 'expose' mode: '%s' or '%s'.", CurriculumInterleaved, CurriculumBlocked))
+	fSet.IntVar(&cfg.PatternRepeats, "patternRepeats", 1, "Presentations of each digit per epoch in 'expose' mode.")
+	fSet.IntVar(&cfg.MaxCyclesPerPattern, "maxCyclesPerPattern", 0,
+		"If above cyclesPerPattern, presentations last a random number of cycles up to this value.")
+	fSet.IntVar(&cfg.RestCycles, "restCycles", 0, "Cycles run without input after each presentation in 'expose' mode.")
 
 	// Mode 'observe' Specific Flags
 	fSet.IntVar(&cfg.Digit, "digit", 0, "Digit (0-9) to present (for 'observe' mode).")
@@ -301,8 +649,9 @@ func LoadCLIConfig(fSet *flag.FlagSet, args []string) (CLIConfig, error) {
 	fSet.StringVar(&cfg.LogUtilDbPath, "logutil.dbPath", "", "Path to SQLite DB for logutil mode.")
 	fSet.StringVar(&cfg.LogUtilTable, "logutil.table", "",
 		"Table to process in logutil mode (e.g., 'NetworkSnapshots', 'NeuronStates').")
-	fSet.StringVar(&cfg.LogUtilFormat, "logutil.format", "csv", "Output format for logutil export (e.g., 'csv').")
+	fSet.StringVar(&cfg.LogUtilFormat, "logutil.format", "csv", "Output format for logutil export ('csv', 'ndjson' or 'arrow').")
 	fSet.StringVar(&cfg.LogUtilOutput, "logutil.output", "", "Output file for logutil export (stdout if empty).")
+	fSet.StringVar(&cfg.LogUtilRun, "logutil.run", "", "Only export rows of this run ID (all runs if empty).")
 
 	// Filter out Ginkgo-specific flags before parsing if they exist
 	// to prevent "flag provided but not defined" errors when running tests
@@ -334,6 +683,12 @@ func LoadCLIConfig(fSet *flag.FlagSet, args []string) (CLIConfig, error) {
 	if cfg.DbPath != "" {
 		cfg.DbPath = filepath.Clean(cfg.DbPath)
 	}
+	if cfg.ModelFile != "" {
+		cfg.ModelFile = filepath.Clean(cfg.ModelFile)
+	}
+	if cfg.StimulusFile != "" {
+		cfg.StimulusFile = filepath.Clean(cfg.StimulusFile)
+	}
 
 	return cfg, nil
 }
@@ -427,6 +782,18 @@ func (ac *AppConfig) Validate() error {
 		if ac.Cli.SaveInterval < 0 {
 			return fmt.Errorf("saveInterval for sim mode must be non-negative, got %d", ac.Cli.SaveInterval)
 		}
+		if ac.Cli.StimulusFile != "" && ac.Cli.StimInputFreqHz > 0 {
+			return fmt.Errorf("stimulusFile and stimInputFreqHz cannot be combined, add a frequency event to the schedule instead")
+		}
+		if err := ac.validateSynapseLogging(); err != nil {
+			return err
+		}
+		if err := ac.validateLogWriter(); err != nil {
+			return err
+		}
+		if err := ac.validateMetricsStream(); err != nil {
+			return err
+		}
 	case ModeExpose:
 		if ac.Cli.WeightsFile == "" {
 			return fmt.Errorf("weightsFile must be specified for mode '%s'", ac.Cli.Mode)
@@ -437,9 +804,21 @@ func (ac *AppConfig) Validate() error {
 		if ac.Cli.CyclesPerPattern <= 0 {
 			return fmt.Errorf("cyclesPerPattern must be positive for mode '%s', got %d", ac.Cli.Mode, ac.Cli.CyclesPerPattern)
 		}
+		if err := ac.validateCurriculum(); err != nil {
+			return err
+		}
+		if err := ac.validateSynapseLogging(); err != nil {
+			return err
+		}
+		if err := ac.validateLogWriter(); err != nil {
+			return err
+		}
+		if err := ac.validateMetricsStream(); err != nil {
+			return err
+		}
 	case ModeObserve:
-		if ac.Cli.WeightsFile == "" {
-			return fmt.Errorf("weightsFile must be specified for mode '%s'", ac.Cli.Mode)
+		if ac.Cli.WeightsFile == "" && ac.Cli.ModelFile == "" {
+			return fmt.Errorf("weightsFile or modelFile must be specified for mode '%s'", ac.Cli.Mode)
 		}
 		if ac.Cli.Digit < 0 || ac.Cli.Digit > 9 {
 			return fmt.Errorf("digit must be between 0-9 for mode '%s', got %d", ac.Cli.Mode, ac.Cli.Digit)
@@ -457,11 +836,30 @@ func (ac *AppConfig) Validate() error {
 		if strings.TrimSpace(ac.Cli.LogUtilTable) == "" {
 			return fmt.Errorf("logutil.table must be specified for mode '%s'", ac.Cli.Mode)
 		}
-		if ac.Cli.LogUtilTable != "NetworkSnapshots" && ac.Cli.LogUtilTable != "NeuronStates" {
-			return fmt.Errorf("invalid logutil.table '%s', must be 'NetworkSnapshots' or 'NeuronStates'", ac.Cli.LogUtilTable)
+		tableValid := false
+		for _, t := range SupportedLogTables {
+			if ac.Cli.LogUtilTable == t {
+				tableValid = true
+				break
+			}
+		}
+		if !tableValid {
+			return fmt.Errorf("invalid logutil.table '%s', supported tables are: %s",
+				ac.Cli.LogUtilTable, strings.Join(SupportedLogTables, ", "))
+		}
+		formatValid := false
+		for _, f := range SupportedLogFormats {
+			if ac.Cli.LogUtilFormat == f {
+				formatValid = true
+				break
+			}
+		}
+		if !formatValid {
+			return fmt.Errorf("invalid logutil.format '%s', supported formats are: %s",
+				ac.Cli.LogUtilFormat, strings.Join(SupportedLogFormats, ", "))
 		}
-		if ac.Cli.LogUtilFormat != "csv" { // Initially only "csv" is supported
-			return fmt.Errorf("invalid logutil.format '%s', currently only 'csv' is supported", ac.Cli.LogUtilFormat)
+		if ac.Cli.LogUtilFormat == LogFormatArrow && strings.TrimSpace(ac.Cli.LogUtilOutput) == "" {
+			return fmt.Errorf("logutil.output must be specified for format '%s' (binary output is not written to stdout)", LogFormatArrow)
 		}
 		// LogUtilOutput can be empty (stdout). Path validation for it will be done by the logutil itself if provided.
 		// No SimulationParameters validation needed for LogUtil mode.
@@ -518,6 +916,17 @@ func (ac *AppConfig) Validate() error {
 	if ac.SimParams.General.SpaceMaxDimension <= 0 {
 		return fmt.Errorf("SpaceMaxDimension must be positive, got %f", ac.SimParams.General.SpaceMaxDimension)
 	}
+	boundaryValid := ac.SimParams.General.BoundaryMode == "" // Empty means the default clamp mode.
+	for _, m := range SupportedBoundaryModes {
+		if ac.SimParams.General.BoundaryMode == m {
+			boundaryValid = true
+			break
+		}
+	}
+	if !boundaryValid {
+		return fmt.Errorf("invalid BoundaryMode '%s', supported modes are: %s",
+			ac.SimParams.General.BoundaryMode, strings.Join(SupportedBoundaryModes, ", "))
+	}
 	if ac.SimParams.NeuronBehavior.BaseFiringThreshold <= 0 { // Assuming threshold should be positive
 		return fmt.Errorf("BaseFiringThreshold must be positive, got %f",
 			ac.SimParams.NeuronBehavior.BaseFiringThreshold)
@@ -569,6 +978,18 @@ func (ac *AppConfig) Validate() error {
 		return fmt.Errorf("MinLearningRateFactor must be non-negative, got %f",
 			ac.SimParams.Learning.MinLearningRateFactor)
 	}
+	if err := ac.validateNeuronHeterogeneity(); err != nil {
+		return err
+	}
+	if err := ac.validateHomeostasis(); err != nil {
+		return err
+	}
+	if err := ac.validateTopology(); err != nil {
+		return err
+	}
+	if err := ac.validateConnectivity(); err != nil {
+		return err
+	}
 	if ac.SimParams.Synaptogenesis.SynaptogenesisInfluenceRadius <= 0 {
 		return fmt.Errorf("SynaptogenesisInfluenceRadius must be positive, got %f",
 			ac.SimParams.Synaptogenesis.SynaptogenesisInfluenceRadius)
@@ -604,3 +1025,291 @@ func (ac *AppConfig) Validate() error {
 
 	return nil
 }
+
+// validateLogWriter checks the settings of the asynchronous SQLite writer of sim and expose modes.
+func (ac *AppConfig) validateLogWriter() error {
+	if ac.Cli.LogQueueSize < 0 {
+		return fmt.Errorf("logQueue must be non-negative, got %d", ac.Cli.LogQueueSize)
+	}
+	switch ac.Cli.LogBackpressure {
+	case "", LogBackpressureBlock, LogBackpressureDrop:
+		return nil
+	}
+	return fmt.Errorf("invalid logBackpressure '%s', supported values are: %s",
+		ac.Cli.LogBackpressure, strings.Join(SupportedLogBackpressures, ", "))
+}
+
+// validateCurriculum checks the exposure curriculum settings of expose mode.
+func (ac *AppConfig) validateCurriculum() error {
+	cli := &ac.Cli
+	switch cli.CurriculumOrder {
+	case "", CurriculumSequential, CurriculumShuffled:
+	default:
+		return fmt.Errorf("invalid curriculumOrder '%s', supported values are: %s",
+			cli.CurriculumOrder, strings.Join(SupportedCurriculumOrders, ", "))
+	}
+	switch cli.CurriculumSchedule {
+	case "", CurriculumInterleaved, CurriculumBlocked:
+	default:
+		return fmt.Errorf("invalid curriculumSchedule '%s', supported values are: %s",
+			cli.CurriculumSchedule, strings.Join(SupportedCurriculumSchedules, ", "))
+	}
+	if cli.PatternRepeats < 0 {
+		return fmt.Errorf("patternRepeats must be non-negative, got %d", cli.PatternRepeats)
+	}
+	if len(cli.DigitRepeats) > 0 {
+		if len(cli.DigitRepeats) != 10 {
+			return fmt.Errorf("digitRepeats needs one value per digit (10), got %d", len(cli.DigitRepeats))
+		}
+		total := 0
+		for digit, n := range cli.DigitRepeats {
+			if n < 0 {
+				return fmt.Errorf("digitRepeats for digit %d must be non-negative, got %d", digit, n)
+			}
+			total += n
+		}
+		if total == 0 {
+			return fmt.Errorf("digitRepeats skips every digit")
+		}
+	}
+	if cli.MaxCyclesPerPattern < 0 {
+		return fmt.Errorf("maxCyclesPerPattern must be non-negative, got %d", cli.MaxCyclesPerPattern)
+	}
+	if cli.RestCycles < 0 {
+		return fmt.Errorf("restCycles must be non-negative, got %d", cli.RestCycles)
+	}
+	return nil
+}
+
+// validateMetricsStream checks the live metrics stream settings of sim and expose modes.
+func (ac *AppConfig) validateMetricsStream() error {
+	if ac.Cli.StreamEvery < 0 {
+		return fmt.Errorf("streamEvery must be non-negative, got %d", ac.Cli.StreamEvery)
+	}
+	return nil
+}
+
+// validateSynapseLogging checks the synaptic weight history settings of sim and expose modes.
+func (ac *AppConfig) validateSynapseLogging() error {
+	if ac.Cli.SynapseLogInterval < 0 {
+		return fmt.Errorf("synapseLogInterval must be non-negative, got %d", ac.Cli.SynapseLogInterval)
+	}
+	switch ac.Cli.SynapseLogMode {
+	case "", SynapseLogFull, SynapseLogDelta:
+	default:
+		return fmt.Errorf("invalid synapseLogMode '%s', supported modes are: %s",
+			ac.Cli.SynapseLogMode, strings.Join(SupportedSynapseLogModes, ", "))
+	}
+	if ac.Cli.SynapseLogThreshold < 0 {
+		return fmt.Errorf("synapseLogThreshold must be non-negative, got %f", ac.Cli.SynapseLogThreshold)
+	}
+	return nil
+}
+
+// validateTopology checks the selected topology generator and the parameters it uses.
+// Parameters of generators that are not selected are ignored.
+func (ac *AppConfig) validateTopology() error {
+	topo := &ac.SimParams.Topology
+	switch topo.Generator {
+	case "", TopologyAllToAll:
+	case TopologyErdosRenyi:
+		if topo.ConnectionProbability < 0 || topo.ConnectionProbability > 1.0 {
+			return fmt.Errorf("Topology.ConnectionProbability must be between 0.0 and 1.0, got %f",
+				topo.ConnectionProbability)
+		}
+	case TopologyDistance:
+		if topo.DistanceMaxProbability < 0 || topo.DistanceMaxProbability > 1.0 {
+			return fmt.Errorf("Topology.DistanceMaxProbability must be between 0.0 and 1.0, got %f",
+				topo.DistanceMaxProbability)
+		}
+		if topo.DistanceLengthScale <= 0 {
+			return fmt.Errorf("Topology.DistanceLengthScale must be positive, got %f", topo.DistanceLengthScale)
+		}
+	case TopologyWattsStrogatz:
+		if topo.SmallWorldNeighbors <= 0 || topo.SmallWorldNeighbors%2 != 0 {
+			return fmt.Errorf("Topology.SmallWorldNeighbors must be a positive even number, got %d",
+				topo.SmallWorldNeighbors)
+		}
+		if topo.SmallWorldNeighbors >= ac.Cli.TotalNeurons {
+			return fmt.Errorf("Topology.SmallWorldNeighbors (%d) must be less than total neurons (%d)",
+				topo.SmallWorldNeighbors, ac.Cli.TotalNeurons)
+		}
+		if topo.SmallWorldRewireProbability < 0 || topo.SmallWorldRewireProbability > 1.0 {
+			return fmt.Errorf("Topology.SmallWorldRewireProbability must be between 0.0 and 1.0, got %f",
+				topo.SmallWorldRewireProbability)
+		}
+	case TopologyFixedInDegree:
+		if topo.InDegree <= 0 || topo.InDegree >= ac.Cli.TotalNeurons {
+			return fmt.Errorf("Topology.InDegree must be between 1 and total neurons - 1 (%d), got %d",
+				ac.Cli.TotalNeurons-1, topo.InDegree)
+		}
+	default:
+		return fmt.Errorf("invalid Topology.Generator '%s', supported generators are: %s",
+			topo.Generator, strings.Join(SupportedTopologies, ", "))
+	}
+	return nil
+}
+
+// validateConnectivity checks every connectivity rule: known neuron types, no
+// duplicate type pairs, a probability in [0, 1] and a well-formed weight distribution.
+// Rules are only accepted with the all_to_all topology: other generators already
+// sample the connections, and a rule's probability would multiply with theirs.
+func (ac *AppConfig) validateConnectivity() error {
+	if generator := ac.SimParams.Topology.Generator; len(ac.SimParams.Connectivity) > 0 &&
+		generator != "" && generator != TopologyAllToAll {
+		return fmt.Errorf("Connectivity rules require Topology.Generator '%s', got '%s'",
+			TopologyAllToAll, generator)
+	}
+	seen := make(map[string]bool, len(ac.SimParams.Connectivity))
+	for i, rule := range ac.SimParams.Connectivity {
+		pre, okPre := canonicalNeuronTypeName(rule.Pre)
+		post, okPost := canonicalNeuronTypeName(rule.Post)
+		if !okPre || !okPost {
+			return fmt.Errorf("Connectivity[%d]: invalid neuron type pair '%s' -> '%s', supported types are: %s",
+				i, rule.Pre, rule.Post, strings.Join(NeuronTypeNames, ", "))
+		}
+		key := pre + "->" + post
+		if seen[key] {
+			return fmt.Errorf("Connectivity[%d]: duplicate rule for %s", i, key)
+		}
+		seen[key] = true
+
+		if rule.Probability < 0 || rule.Probability > 1.0 {
+			return fmt.Errorf("Connectivity[%d] (%s): probability must be between 0.0 and 1.0, got %f",
+				i, key, rule.Probability)
+		}
+		w := rule.Weight
+		switch w.Distribution {
+		case "", WeightUniform:
+			// A rule without a [weight] table decodes to the uniform range [0, 0], which
+			// is only accepted if the rule creates no synapses.
+			if w.Min > w.Max || (rule.Probability > 0 && w.Min == w.Max) {
+				return fmt.Errorf("Connectivity[%d] (%s): uniform weight max (%f) must be greater than min (%f)",
+					i, key, w.Max, w.Min)
+			}
+		case WeightNormal, WeightLogNormal:
+			if w.StdDev < 0 {
+				return fmt.Errorf("Connectivity[%d] (%s): %s weight std_dev must be non-negative, got %f",
+					i, key, w.Distribution, w.StdDev)
+			}
+		default:
+			return fmt.Errorf("Connectivity[%d] (%s): invalid weight distribution '%s', supported distributions are: %s",
+				i, key, w.Distribution, strings.Join(SupportedWeightDistributions, ", "))
+		}
+	}
+	return nil
+}
+
+// canonicalNeuronTypeName returns the entry of NeuronTypeNames matching name case-insensitively.
+func canonicalNeuronTypeName(name string) (string, bool) {
+	for _, n := range NeuronTypeNames {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// validateNeuronHeterogeneity checks the per-type overrides and the jitter settings
+// of NeuronBehaviorParams.
+func (ac *AppConfig) validateNeuronHeterogeneity() error {
+	nb := &ac.SimParams.NeuronBehavior
+	seen := make(map[string]bool, len(nb.TypeOverrides))
+	for i, o := range nb.TypeOverrides {
+		typeName, ok := canonicalNeuronTypeName(o.Type)
+		if !ok {
+			return fmt.Errorf("NeuronBehavior.TypeOverrides[%d]: invalid neuron type '%s', supported types are: %s",
+				i, o.Type, strings.Join(NeuronTypeNames, ", "))
+		}
+		if seen[typeName] {
+			return fmt.Errorf("NeuronBehavior.TypeOverrides[%d]: duplicate override for %s", i, typeName)
+		}
+		seen[typeName] = true
+		if o.BaseFiringThreshold != nil && *o.BaseFiringThreshold <= 0 {
+			return fmt.Errorf("NeuronBehavior.TypeOverrides[%d] (%s): BaseFiringThreshold must be positive, got %f",
+				i, typeName, *o.BaseFiringThreshold)
+		}
+		if o.AccumulatedPulseDecayRate != nil && *o.AccumulatedPulseDecayRate < 0 {
+			return fmt.Errorf("NeuronBehavior.TypeOverrides[%d] (%s): AccumulatedPulseDecayRate must be non-negative, got %f",
+				i, typeName, *o.AccumulatedPulseDecayRate)
+		}
+		if (o.AbsoluteRefractoryCycles != nil && *o.AbsoluteRefractoryCycles < 0) ||
+			(o.RelativeRefractoryCycles != nil && *o.RelativeRefractoryCycles < 0) {
+			return fmt.Errorf("NeuronBehavior.TypeOverrides[%d] (%s): refractory cycles must be non-negative", i, typeName)
+		}
+	}
+
+	jitters := []struct {
+		name string
+		j    *ParameterJitter
+	}{
+		{"ThresholdJitter", &nb.ThresholdJitter},
+		{"DecayJitter", &nb.DecayJitter},
+		{"RefractoryJitter", &nb.RefractoryJitter},
+	}
+	for _, jt := range jitters {
+		if !jt.j.Enabled() {
+			continue
+		}
+		if jt.j.Distribution != WeightNormal && jt.j.Distribution != WeightLogNormal {
+			return fmt.Errorf("NeuronBehavior.%s: invalid distribution '%s', supported distributions are: %s, %s",
+				jt.name, jt.j.Distribution, WeightNormal, WeightLogNormal)
+		}
+		if jt.j.RelativeStdDev < 0 {
+			return fmt.Errorf("NeuronBehavior.%s: RelativeStdDev must be non-negative, got %f", jt.name, jt.j.RelativeStdDev)
+		}
+		if jt.j.MinFactor <= 0 || jt.j.MinFactor > 1.0 || jt.j.MaxFactor < 1.0 {
+			return fmt.Errorf("NeuronBehavior.%s: clipping bounds must satisfy 0 < MinFactor <= 1 <= MaxFactor, got [%f, %f]",
+				jt.name, jt.j.MinFactor, jt.j.MaxFactor)
+		}
+	}
+	return nil
+}
+
+// validateHomeostasis checks the homeostasis settings. Global target rate and time
+// constant are only required when the mechanism is enabled globally or for some type.
+func (ac *AppConfig) validateHomeostasis() error {
+	h := &ac.SimParams.Homeostasis
+	anyEnabled := h.Enabled
+	seen := make(map[string]bool, len(h.TypeOverrides))
+	for i, o := range h.TypeOverrides {
+		typeName, ok := canonicalNeuronTypeName(o.Type)
+		if !ok {
+			return fmt.Errorf("Homeostasis.TypeOverrides[%d]: invalid neuron type '%s', supported types are: %s",
+				i, o.Type, strings.Join(NeuronTypeNames, ", "))
+		}
+		if seen[typeName] {
+			return fmt.Errorf("Homeostasis.TypeOverrides[%d]: duplicate override for %s", i, typeName)
+		}
+		seen[typeName] = true
+		if o.Enabled != nil && *o.Enabled {
+			anyEnabled = true
+		}
+		if o.TargetFiringRateHz != nil && *o.TargetFiringRateHz <= 0 {
+			return fmt.Errorf("Homeostasis.TypeOverrides[%d] (%s): TargetFiringRateHz must be positive, got %f",
+				i, typeName, *o.TargetFiringRateHz)
+		}
+		if o.TimeConstantCycles != nil && *o.TimeConstantCycles < 1 {
+			return fmt.Errorf("Homeostasis.TypeOverrides[%d] (%s): TimeConstantCycles must be at least 1, got %f",
+				i, typeName, *o.TimeConstantCycles)
+		}
+	}
+	if !anyEnabled {
+		return nil
+	}
+	if h.TargetFiringRateHz <= 0 {
+		return fmt.Errorf("Homeostasis.TargetFiringRateHz must be positive, got %f", h.TargetFiringRateHz)
+	}
+	if h.TimeConstantCycles < 1 {
+		return fmt.Errorf("Homeostasis.TimeConstantCycles must be at least 1, got %f", h.TimeConstantCycles)
+	}
+	if h.MinThreshold <= 0 || h.MaxThreshold < h.MinThreshold {
+		return fmt.Errorf("Homeostasis thresholds must satisfy 0 < MinThreshold <= MaxThreshold, got [%f, %f]",
+			h.MinThreshold, h.MaxThreshold)
+	}
+	if ac.SimParams.Structure.OutputFrequencyWindowCycles <= 0 {
+		return fmt.Errorf("OutputFrequencyWindowCycles must be positive when homeostasis is enabled, got %f",
+			ac.SimParams.Structure.OutputFrequencyWindowCycles)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"crownet/common"
+)
+
+func TestAppConfigClone(t *testing.T) {
+	threshold := common.Threshold(2)
+	enabled := true
+	ac := DefaultAppConfig(ModeExpose)
+	ac.SimParams.Connectivity = []ConnectivityRule{{Pre: "Excitatory", Post: "Inhibitory", Probability: 0.5}}
+	ac.SimParams.NeuronBehavior.TypeOverrides = []NeuronTypeOverride{{Type: "Inhibitory", BaseFiringThreshold: &threshold}}
+	ac.SimParams.Homeostasis.TypeOverrides = []HomeostasisTypeOverride{{Type: "Input", Enabled: &enabled}}
+	ac.Cli.DigitRepeats = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	ac.Cli.Overrides = []string{"cli.epochs=3"}
+
+	c := ac.Clone()
+	if !reflect.DeepEqual(c, ac) {
+		t.Fatalf("Clone() = %+v, want a copy equal to %+v", c, ac)
+	}
+
+	c.SimParams.Connectivity[0].Probability = 1
+	*c.SimParams.NeuronBehavior.TypeOverrides[0].BaseFiringThreshold = 3
+	*c.SimParams.Homeostasis.TypeOverrides[0].Enabled = false
+	c.Cli.DigitRepeats[0] = 0
+	c.Cli.Overrides[0] = "cli.epochs=4"
+	if ac.SimParams.Connectivity[0].Probability != 0.5 || threshold != 2 || !enabled ||
+		ac.Cli.DigitRepeats[0] != 1 || ac.Cli.Overrides[0] != "cli.epochs=3" {
+		t.Errorf("changing the clone changed the original: %+v", ac)
+	}
+}
+
+func TestValidate_ConnectivityRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ConnectivityRule
+		wantErr bool
+	}{
+		{"uniform range", ConnectivityRule{Pre: "Excitatory", Post: "Inhibitory", Probability: 0.5,
+			Weight: WeightDistribution{Min: 0.1, Max: 0.3}}, false},
+		{"normal", ConnectivityRule{Pre: "Excitatory", Post: "Inhibitory", Probability: 0.5,
+			Weight: WeightDistribution{Distribution: WeightNormal, Mean: 0.3, StdDev: 0.05}}, false},
+		{"no weight table without connections", ConnectivityRule{Pre: "Input", Post: "Output"}, false},
+		{"no weight table", ConnectivityRule{Pre: "Excitatory", Post: "Inhibitory", Probability: 0.5}, true},
+		{"empty uniform range", ConnectivityRule{Pre: "Excitatory", Post: "Inhibitory", Probability: 0.5,
+			Weight: WeightDistribution{Distribution: WeightUniform, Min: 0.2, Max: 0.2}}, true},
+		{"inverted uniform range", ConnectivityRule{Pre: "Input", Post: "Output",
+			Weight: WeightDistribution{Min: 0.3, Max: 0.1}}, true},
+		{"probability above 1", ConnectivityRule{Pre: "Excitatory", Post: "Inhibitory", Probability: 1.5,
+			Weight: WeightDistribution{Min: 0.1, Max: 0.3}}, true},
+		{"unknown type", ConnectivityRule{Pre: "Glial", Post: "Inhibitory"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := DefaultAppConfig(ModeSim)
+			ac.SimParams.Connectivity = []ConnectivityRule{tt.rule}
+			if err := ac.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package config
+
+import (
+	_ "embed"
+)
+
+//go:embed default_config.toml
+var defaultConfigTOML []byte
+
+// DefaultConfigTOML returns a configuration file that sets every value of
+// DefaultAppConfig(ModeSim), except the mode, with a comment on each key.
+func DefaultConfigTOML() []byte {
+	return append([]byte(nil), defaultConfigTOML...)
+}
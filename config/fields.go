@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field is one value of an AppConfig as it appears in a TOML file: the key Name
+// inside the table Section (e.g. "sim_params.neurochemical"). Value is a scalar,
+// a list or an inline table.
+type Field struct {
+	Section string
+	Name    string
+	Value   reflect.Value
+}
+
+// Key returns the full dotted key of the field, e.g. "sim_params.neurochemical.cortisol_decay_rate".
+func (f Field) Key() string {
+	return f.Section + "." + f.Name
+}
+
+// Fields lists every value of ac in the order of the struct declarations, grouped
+// by table. A struct becomes a table of its own when its parent holds nothing but
+// other tables and lists (as AppConfig and SimulationParameters do); any other
+// struct is an inline table. Within each table, values of the table itself come
+// before its sub-tables.
+func (ac *AppConfig) Fields() []Field {
+	var fields []Field
+	collectFields(reflect.ValueOf(ac).Elem(), "", &fields)
+	return fields
+}
+
+func collectFields(v reflect.Value, section string, fields *[]Field) {
+	t := v.Type()
+	type table struct {
+		name  string
+		value reflect.Value
+	}
+	var tables []table
+	container := isContainer(t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("toml") == "-" {
+			continue
+		}
+		name := fieldKey(f)
+		if container && f.Type.Kind() == reflect.Struct {
+			tables = append(tables, table{joinKey(section, name), v.Field(i)})
+			continue
+		}
+		*fields = append(*fields, Field{Section: section, Name: name, Value: v.Field(i)})
+	}
+	for _, tbl := range tables {
+		collectFields(tbl.value, tbl.name, fields)
+	}
+}
+
+// isContainer reports whether struct type t has no scalar fields, so that its
+// struct fields are written as tables rather than inline tables.
+func isContainer(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		switch t.Field(i).Type.Kind() {
+		case reflect.Struct, reflect.Slice:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func joinKey(section, name string) string {
+	if section == "" {
+		return name
+	}
+	return section + "." + name
+}
+
+// FormatTOMLValue formats v as a TOML value: a string, number or boolean, an array,
+// or an inline table (nil pointer fields are left out).
+func FormatTOMLValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatTOMLFloat(v.Float())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return FormatTOMLValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = FormatTOMLValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		var items []string
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("toml") == "-" {
+				continue
+			}
+			if f.Type.Kind() == reflect.Pointer && v.Field(i).IsNil() {
+				continue
+			}
+			items = append(items, fieldKey(f)+" = "+FormatTOMLValue(v.Field(i)))
+		}
+		if len(items) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// formatTOMLFloat formats f with the shortest exact representation, keeping a
+// decimal point so that TOML reads it back as a float.
+func formatTOMLFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// UnknownKey is a key of a configuration file that matches no configuration field.
+type UnknownKey struct {
+	Key string
+	// NestedKey is set when Key belongs to the legacy flat layout, in which every
+	// simulation parameter sat directly in [sim_params]: it is where the value goes
+	// in the current layout, e.g. "sim_params.general.space_max_dimension".
+	NestedKey string
+}
+
+// LoadFile decodes the TOML file at filePath over ac, so that keys missing from the
+// file keep their current values, and returns the keys of the file that matched no
+// field. toml.DecodeFile alone ignores such keys silently.
+func LoadFile(filePath string, ac *AppConfig) (toml.MetaData, []UnknownKey, error) {
+	md, err := toml.DecodeFile(filePath, ac)
+	if err != nil {
+		return md, nil, fmt.Errorf("failed to decode configuration file %s: %w", filePath, err)
+	}
+	return md, UnknownKeys(md), nil
+}
+
+// DecodeJSON decodes a JSON document with the same keys and layout as a TOML
+// configuration file (e.g. {"sim_params": {"general": {"space_max_dimension": 12}}})
+// over ac, so that keys missing from it keep their current values, and returns the
+// keys that matched no field.
+func DecodeJSON(data []byte, ac *AppConfig) ([]UnknownKey, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON configuration: %w", err)
+	}
+	// Go through TOML so that the same keys, and the same rules for numbers, apply.
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(jsonToTOML(doc)); err != nil {
+		return nil, fmt.Errorf("failed to convert JSON configuration: %w", err)
+	}
+	md, err := toml.Decode(buf.String(), ac)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON configuration: %w", err)
+	}
+	return UnknownKeys(md), nil
+}
+
+// jsonToTOML converts the numbers of a JSON value decoded with UseNumber to int64
+// when they are integers and to float64 otherwise.
+func jsonToTOML(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, item := range x {
+			x[k] = jsonToTOML(item)
+		}
+	case []any:
+		for i, item := range x {
+			x[i] = jsonToTOML(item)
+		}
+	}
+	return v
+}
+
+// UnknownKeys returns the undecoded keys of md, leaving out the keys inside an
+// unknown table (the table itself is reported).
+func UnknownKeys(md toml.MetaData) []UnknownKey {
+	undecoded := md.Undecoded()
+	unknown := make(map[string]bool, len(undecoded))
+	for _, k := range undecoded {
+		unknown[k.String()] = true
+	}
+	var keys []UnknownKey
+	for _, k := range undecoded {
+		if len(k) > 1 && unknown[k[:len(k)-1].String()] {
+			continue
+		}
+		uk := UnknownKey{Key: k.String()}
+		if len(k) == 2 && k[0] == "sim_params" {
+			uk.NestedKey, _ = legacyNestedKey(k[1])
+		}
+		keys = append(keys, uk)
+	}
+	return keys
+}
+
+// legacyNestedKey returns the full key, in the nested layout, of the simulation
+// parameter named name in the legacy flat [sim_params] table.
+func legacyNestedKey(name string) (string, bool) {
+	section, field, ok := legacySection(name)
+	if !ok {
+		return "", false
+	}
+	return "sim_params." + section + "." + field, true
+}
+
+// legacySection finds the table of SimulationParameters holding the field name and
+// returns the keys of the table and of the field.
+func legacySection(name string) (section, field string, ok bool) {
+	t := reflect.TypeOf(SimulationParameters{})
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if f, found := typeField(sf.Type, name); found {
+			return fieldKey(sf), fieldKey(f), true
+		}
+	}
+	return "", "", false
+}
+
+// MigrateLegacy rewrites doc, a configuration file decoded into a generic map, to
+// the current layout: simulation parameters of the legacy flat [sim_params] table
+// move to their sub-tables (e.g. sim_params.space_max_dimension becomes
+// sim_params.general.space_max_dimension), and keys written as Go field names or
+// with other capitalisation (e.g. TotalNeurons) are renamed to their TOML keys. It
+// returns a description of each change. Keys that match no field, or a flat key
+// whose nested key is also set, are reported as an error and doc is left partially
+// migrated.
+func MigrateLegacy(doc map[string]any) ([]string, error) {
+	m := &migration{}
+	m.table(doc, reflect.TypeOf(AppConfig{}), "")
+	if len(m.problems) > 0 {
+		return m.changes, fmt.Errorf("cannot migrate configuration: %s", strings.Join(m.problems, "; "))
+	}
+	return m.changes, nil
+}
+
+type migration struct {
+	changes  []string
+	problems []string
+}
+
+// table migrates the keys of tbl, which holds the fields of struct type t.
+func (m *migration) table(tbl map[string]any, t reflect.Type, prefix string) {
+	keys := make([]string, 0, len(tbl))
+	for k := range tbl {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := tbl[k]
+		f, ok := typeField(t, k)
+		if !ok {
+			if _, isTable := value.(map[string]any); t == reflect.TypeOf(SimulationParameters{}) && !isTable {
+				if section, field, found := legacySection(k); found {
+					m.move(tbl, k, section, field, prefix)
+					continue
+				}
+			}
+			m.problems = append(m.problems, fmt.Sprintf("unknown key %s", joinKey(prefix, k)))
+			continue
+		}
+		if name := fieldKey(f); name != k {
+			if _, taken := tbl[name]; taken {
+				m.problems = append(m.problems, fmt.Sprintf("%s and %s are both set",
+					joinKey(prefix, k), joinKey(prefix, name)))
+				continue
+			}
+			delete(tbl, k)
+			tbl[name] = value
+			m.changes = append(m.changes, fmt.Sprintf("%s -> %s", joinKey(prefix, k), joinKey(prefix, name)))
+		}
+	}
+
+	// Recurse once every key of this level has its final name and place.
+	for k, value := range tbl {
+		f, ok := typeField(t, k)
+		if !ok {
+			continue
+		}
+		m.value(value, f.Type, joinKey(prefix, k))
+	}
+}
+
+// move moves the flat key k of the legacy [sim_params] table tbl into its sub-table.
+func (m *migration) move(tbl map[string]any, k, section, field, prefix string) {
+	sub, ok := tbl[section].(map[string]any)
+	if !ok {
+		if _, exists := tbl[section]; exists {
+			m.problems = append(m.problems, fmt.Sprintf("%s is not a table", joinKey(prefix, section)))
+			return
+		}
+		sub = make(map[string]any)
+		tbl[section] = sub
+	}
+	if _, taken := sub[field]; taken {
+		m.problems = append(m.problems, fmt.Sprintf("%s and %s are both set",
+			joinKey(prefix, k), joinKey(prefix, section+"."+field)))
+		return
+	}
+	sub[field] = tbl[k]
+	delete(tbl, k)
+	m.changes = append(m.changes, fmt.Sprintf("%s -> %s", joinKey(prefix, k), joinKey(prefix, section+"."+field)))
+}
+
+// value migrates the tables inside value, which decodes into a field of type t.
+func (m *migration) value(value any, t reflect.Type, key string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if t.Kind() == reflect.Struct {
+			m.table(v, t, key)
+		}
+	case []map[string]any: // Array of tables.
+		if t.Kind() == reflect.Slice {
+			for i, item := range v {
+				m.table(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))
+			}
+		}
+	case []any: // Inline array, possibly of inline tables.
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				m.value(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))
+			}
+		}
+	}
+}
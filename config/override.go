@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetField sets the scalar field of ac addressed by path, a dot-separated list of
+// keys such as "sim_params.learning.hebb_positive_reinforce_factor" or
+// "cli.cycles_per_pattern". Each key matches a field by its toml tag, its json tag
+// or its Go name, ignoring case and underscores, so "cli.CyclesPerPattern" works too.
+// The "sim_params." prefix may be left out: "learning.hebb_positive_reinforce_factor"
+// addresses the same field.
+//
+// value may be a string, which is parsed according to the field's type, or a bool,
+// an integer or a float64. Floats are accepted for integer fields only if they are
+// whole numbers. Lists and nested tables (e.g. sim_params.connectivity) cannot be set.
+func (ac *AppConfig) SetField(path string, value any) error {
+	field, _, err := ac.lookupField(path)
+	if err != nil {
+		return err
+	}
+	if err := setScalar(field, value); err != nil {
+		return fmt.Errorf("cannot set %s: %w", path, err)
+	}
+	return nil
+}
+
+// ApplyOverrides applies assignments of the form "key=value" in order, with keys
+// as accepted by SetField and values parsed according to the type of the field.
+// It stops at the first unknown key or invalid value.
+func (ac *AppConfig) ApplyOverrides(assignments []string) error {
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid override '%s', expected section.key=value", assignment)
+		}
+		if err := ac.SetField(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FieldKind returns the reflect.Kind of the scalar field addressed by path (see SetField).
+func (ac *AppConfig) FieldKind(path string) (reflect.Kind, error) {
+	field, _, err := ac.lookupField(path)
+	if err != nil {
+		return reflect.Invalid, err
+	}
+	return field.Kind(), nil
+}
+
+// CanonicalKey returns the full TOML key of the scalar field addressed by path (see
+// SetField), e.g. "sim_params.neurochemical.cortisol_decay_rate" for
+// "neurochemical.CortisolDecayRate".
+func (ac *AppConfig) CanonicalKey(path string) (string, error) {
+	_, key, err := ac.lookupField(path)
+	return key, err
+}
+
+// lookupField resolves path to a settable scalar field of ac and its full TOML key.
+func (ac *AppConfig) lookupField(path string) (reflect.Value, string, error) {
+	if path == "" {
+		return reflect.Value{}, "", fmt.Errorf("empty configuration key")
+	}
+	v := reflect.ValueOf(ac).Elem()
+	keys := strings.Split(path, ".")
+	var canonical []string
+	if _, ok := typeField(v.Type(), keys[0]); !ok {
+		v = v.FieldByName("SimParams") // Sections of SimulationParameters may be addressed directly.
+		canonical = append(canonical, "sim_params")
+	}
+	for i, key := range keys {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, "", fmt.Errorf("configuration key %s: %s is not a table",
+				path, strings.Join(keys[:i], "."))
+		}
+		f, ok := typeField(v.Type(), key)
+		if !ok {
+			return reflect.Value{}, "", fmt.Errorf("configuration key %s: unknown key '%s'", path, key)
+		}
+		v = v.FieldByIndex(f.Index)
+		canonical = append(canonical, fieldKey(f))
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v, strings.Join(canonical, "."), nil
+	}
+	return reflect.Value{}, "", fmt.Errorf("configuration key %s is a %s, only single values can be set", path, v.Kind())
+}
+
+// typeField returns the field of struct type t whose toml tag, json tag or name
+// matches key. Fields tagged toml:"-" never match.
+func typeField(t reflect.Type, key string) (reflect.StructField, bool) {
+	want := normalizeKey(key)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("toml") == "-" {
+			continue
+		}
+		names := []string{f.Name, tagName(f.Tag.Get("toml")), tagName(f.Tag.Get("json"))}
+		for _, name := range names {
+			if name != "" && normalizeKey(name) == want {
+				return f, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldKey returns the key of f in TOML files: its toml tag, or its name if untagged.
+func fieldKey(f reflect.StructField) string {
+	if name := tagName(f.Tag.Get("toml")); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+// setScalar stores value in field, converting it to the field's type.
+func setScalar(field reflect.Value, value any) error {
+	if s, ok := value.(string); ok && field.Kind() != reflect.String {
+		parsed, err := parseScalar(field.Kind(), s)
+		if err != nil {
+			return err
+		}
+		value = parsed
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %v", value)
+		}
+		field.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected true or false, got %v", value)
+		}
+		field.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("expected a number, got %v", value)
+		}
+		if field.OverflowFloat(f) {
+			return fmt.Errorf("%v is out of range", value)
+		}
+		field.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := toInt(value)
+		if !ok {
+			return fmt.Errorf("expected an integer, got %v", value)
+		}
+		if field.OverflowInt(i) {
+			return fmt.Errorf("%v is out of range", value)
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := toInt(value)
+		if !ok || i < 0 {
+			return fmt.Errorf("expected a non-negative integer, got %v", value)
+		}
+		if field.OverflowUint(uint64(i)) {
+			return fmt.Errorf("%v is out of range", value)
+		}
+		field.SetUint(uint64(i))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Kind())
+	}
+	return nil
+}
+
+func parseScalar(kind reflect.Kind, s string) (any, error) {
+	s = strings.TrimSpace(s)
+	switch kind {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got '%s'", s)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got '%s'", s)
+		}
+		return i, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || u > math.MaxInt64 {
+			return nil, fmt.Errorf("expected a non-negative integer, got '%s'", s)
+		}
+		return int64(u), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number, got '%s'", s)
+	}
+	return f, nil
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// toInt accepts integers and whole-number floats (as decoded from TOML or JSON).
+func toInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
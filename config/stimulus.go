@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Event types of a StimulusSchedule.
+const (
+	// StimulusFrequency drives the event's inputs at a constant frequency.
+	StimulusFrequency = "frequency"
+	// StimulusRamp drives the event's inputs at a frequency that changes linearly
+	// from FromHz on the first cycle to ToHz on the last.
+	StimulusRamp = "ramp"
+	// StimulusBurst drives the event's inputs at Hz for On cycles, silences them for
+	// Off cycles, and repeats.
+	StimulusBurst = "burst"
+	// StimulusPattern presents a digit or an explicit pattern to the input neurons.
+	StimulusPattern = "pattern"
+	// StimulusRest silences every other event while it is active.
+	StimulusRest = "rest"
+)
+
+// SupportedStimulusEvents lists all valid values for StimulusEvent.Type.
+var SupportedStimulusEvents = []string{StimulusFrequency, StimulusRamp, StimulusBurst, StimulusPattern, StimulusRest}
+
+// StimulusSchedule is a scripted stimulus protocol for sim mode: a list of timed
+// events applied to the input neurons. It is read from a TOML file with one [[event]]
+// table per event, or from a JSON file with the same keys ({"event": [{...}]}).
+type StimulusSchedule struct {
+	Events []StimulusEvent `toml:"event"`
+}
+
+// StimulusEvent is one event of a StimulusSchedule. Cycles are counted from the start
+// of the run; the event is active from Start up to, but not including, Stop (0: until
+// the end of the run). When several frequency events drive the same input neuron on
+// a cycle, the one listed last wins.
+type StimulusEvent struct {
+	Type  string `toml:"type"`  // One of SupportedStimulusEvents.
+	Start int    `toml:"start"` // First cycle of the event.
+	Stop  int    `toml:"stop"`  // Cycle the event ends at (exclusive); 0 means the end of the run.
+	// Indices into the network's input neurons (the order patterns use) driven by
+	// frequency, ramp and burst events; empty means all input neurons.
+	Inputs []int   `toml:"inputs"`
+	Hz     float64 `toml:"hz"`      // Frequency and burst events.
+	FromHz float64 `toml:"from_hz"` // Ramp events: frequency on the first cycle.
+	ToHz   float64 `toml:"to_hz"`   // Ramp events: frequency on the last cycle.
+	On     int     `toml:"on"`      // Burst events: cycles driven in each period.
+	Off    int     `toml:"off"`     // Burst events: cycles silent in each period.
+	// Pattern events present Digit (0-9) or Pattern (PatternSize values, > 0.5 fires
+	// the input) at Start, and again every Every cycles while active if Every > 0.
+	// Reset clears the potentials and pulses in flight before each presentation, as
+	// expose mode does between patterns.
+	Digit   *int      `toml:"digit"`
+	Pattern []float64 `toml:"pattern"`
+	Every   int       `toml:"every"`
+	Reset   bool      `toml:"reset"`
+}
+
+// LoadStimulusSchedule reads a stimulus schedule from a JSON file (by its .json
+// extension) or a TOML file, and validates it.
+func LoadStimulusSchedule(filePath string) (*StimulusSchedule, error) {
+	var schedule StimulusSchedule
+	var md toml.MetaData
+	var err error
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		md, err = decodeJSONFile(filePath, &schedule)
+	} else {
+		md, err = toml.DecodeFile(filePath, &schedule)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode stimulus schedule %s: %w", filePath, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("stimulus schedule %s has unknown keys: %s", filePath, strings.Join(keys, ", "))
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid stimulus schedule %s: %w", filePath, err)
+	}
+	return &schedule, nil
+}
+
+// decodeJSONFile decodes a JSON file into v through TOML, so that the same keys and
+// the same rules for numbers apply as to a TOML file (see DecodeJSON).
+func decodeJSONFile(filePath string, v any) (toml.MetaData, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return toml.MetaData{}, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return toml.MetaData{}, err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(jsonToTOML(doc)); err != nil {
+		return toml.MetaData{}, err
+	}
+	return toml.Decode(buf.String(), v)
+}
+
+// Validate checks every event of the schedule. Input indices and pattern sizes
+// depend on the network and are checked when the schedule is run.
+func (s *StimulusSchedule) Validate() error {
+	if len(s.Events) == 0 {
+		return fmt.Errorf("stimulus schedule has no [[event]] entries")
+	}
+	for i, e := range s.Events {
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("event %d (%s): %w", i+1, e.Type, err)
+		}
+	}
+	return nil
+}
+
+func (e *StimulusEvent) validate() error {
+	valid := false
+	for _, t := range SupportedStimulusEvents {
+		if e.Type == t {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid type '%s', supported types are: %s", e.Type, strings.Join(SupportedStimulusEvents, ", "))
+	}
+	if e.Start < 0 {
+		return fmt.Errorf("start must be non-negative, got %d", e.Start)
+	}
+	if e.Stop != 0 && e.Stop <= e.Start {
+		return fmt.Errorf("stop (%d) must be greater than start (%d), or 0 for the end of the run", e.Stop, e.Start)
+	}
+	for _, idx := range e.Inputs {
+		if idx < 0 {
+			return fmt.Errorf("input indices must be non-negative, got %d", idx)
+		}
+	}
+
+	switch e.Type {
+	case StimulusFrequency:
+		if e.Hz <= 0 {
+			return fmt.Errorf("hz must be positive, got %g", e.Hz)
+		}
+	case StimulusRamp:
+		if e.Stop == 0 {
+			return fmt.Errorf("a ramp needs a stop cycle")
+		}
+		if e.FromHz < 0 || e.ToHz < 0 || (e.FromHz == 0 && e.ToHz == 0) {
+			return fmt.Errorf("from_hz and to_hz must be non-negative and not both 0, got %g and %g", e.FromHz, e.ToHz)
+		}
+	case StimulusBurst:
+		if e.Hz <= 0 {
+			return fmt.Errorf("hz must be positive, got %g", e.Hz)
+		}
+		if e.On <= 0 || e.Off <= 0 {
+			return fmt.Errorf("on and off must be positive, got %d and %d", e.On, e.Off)
+		}
+	case StimulusPattern:
+		if (e.Digit == nil) == (len(e.Pattern) == 0) {
+			return fmt.Errorf("give either digit or pattern")
+		}
+		if e.Digit != nil && (*e.Digit < 0 || *e.Digit > 9) {
+			return fmt.Errorf("digit must be between 0-9, got %d", *e.Digit)
+		}
+		if e.Every < 0 {
+			return fmt.Errorf("every must be non-negative, got %d", e.Every)
+		}
+	}
+	return nil
+}
+
+// ActiveAt reports whether the event is active on cycle.
+func (e *StimulusEvent) ActiveAt(cycle int) bool {
+	return cycle >= e.Start && (e.Stop == 0 || cycle < e.Stop)
+}
+
+// HzAt returns the frequency a frequency, ramp or burst event drives its inputs at on
+// cycle, which must be active; 0 means the inputs are silent.
+func (e *StimulusEvent) HzAt(cycle int) float64 {
+	switch e.Type {
+	case StimulusFrequency:
+		return e.Hz
+	case StimulusRamp:
+		last := e.Stop - 1
+		if last <= e.Start {
+			return e.ToHz
+		}
+		return e.FromHz + (e.ToHz-e.FromHz)*float64(cycle-e.Start)/float64(last-e.Start)
+	case StimulusBurst:
+		if (cycle-e.Start)%(e.On+e.Off) < e.On {
+			return e.Hz
+		}
+	}
+	return 0
+}
+
+// PresentsAt reports whether a pattern event presents its pattern on cycle.
+func (e *StimulusEvent) PresentsAt(cycle int) bool {
+	if e.Type != StimulusPattern || !e.ActiveAt(cycle) {
+		return false
+	}
+	if e.Every == 0 {
+		return cycle == e.Start
+	}
+	return (cycle-e.Start)%e.Every == 0
+}
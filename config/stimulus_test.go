@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeStimulusFile writes content to name in a temporary directory and returns its path.
+func writeStimulusFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadStimulusSchedule(t *testing.T) {
+	tomlPath := writeStimulusFile(t, "protocol.toml", `
+[[event]]
+type = "frequency"
+inputs = [0]
+hz = 100
+stop = 20
+
+[[event]]
+type = "rest"
+start = 20
+stop = 40
+
+[[event]]
+type = "pattern"
+digit = 1
+start = 40
+every = 5
+reset = true
+`)
+	schedule, err := LoadStimulusSchedule(tomlPath)
+	if err != nil {
+		t.Fatalf("LoadStimulusSchedule(TOML) error = %v", err)
+	}
+	if len(schedule.Events) != 3 {
+		t.Fatalf("LoadStimulusSchedule(TOML) read %d events, want 3", len(schedule.Events))
+	}
+	pattern := schedule.Events[2]
+	if pattern.Digit == nil || *pattern.Digit != 1 || pattern.Every != 5 || !pattern.Reset {
+		t.Errorf("pattern event = %+v, want digit 1 every 5 cycles with reset", pattern)
+	}
+
+	jsonPath := writeStimulusFile(t, "protocol.json",
+		`{"event": [{"type": "burst", "inputs": [0, 1], "hz": 50, "on": 3, "off": 7}]}`)
+	schedule, err = LoadStimulusSchedule(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadStimulusSchedule(JSON) error = %v", err)
+	}
+	if burst := schedule.Events[0]; burst.Hz != 50 || burst.On != 3 || burst.Off != 7 || len(burst.Inputs) != 2 {
+		t.Errorf("burst event = %+v, want 50 Hz, on 3, off 7 on inputs 0 and 1", burst)
+	}
+}
+
+func TestLoadStimulusSchedule_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown key", "[[event]]\ntype = \"frequency\"\nhz = 10\nfrequency = 10\n"},
+		{"unknown type", "[[event]]\ntype = \"noise\"\n"},
+		{"no events", "# empty\n"},
+		{"ramp without stop", "[[event]]\ntype = \"ramp\"\nfrom_hz = 5\nto_hz = 50\n"},
+		{"stop before start", "[[event]]\ntype = \"frequency\"\nhz = 10\nstart = 10\nstop = 5\n"},
+		{"negative input", "[[event]]\ntype = \"frequency\"\nhz = 10\ninputs = [-1]\n"},
+		{"burst without off", "[[event]]\ntype = \"burst\"\nhz = 10\non = 3\n"},
+		{"pattern without digit", "[[event]]\ntype = \"pattern\"\n"},
+		{"digit out of range", "[[event]]\ntype = \"pattern\"\ndigit = 10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeStimulusFile(t, "schedule.toml", tt.content)
+			if _, err := LoadStimulusSchedule(path); err == nil {
+				t.Error("LoadStimulusSchedule() expected an error")
+			}
+		})
+	}
+}
+
+func TestStimulusEvent_Timing(t *testing.T) {
+	ramp := StimulusEvent{Type: StimulusRamp, Start: 10, Stop: 21, FromHz: 0, ToHz: 100}
+	burst := StimulusEvent{Type: StimulusBurst, Start: 5, Hz: 40, On: 2, Off: 3}
+	tests := []struct {
+		name   string
+		event  StimulusEvent
+		cycle  int
+		active bool
+		hz     float64
+	}{
+		{"ramp before start", ramp, 9, false, 0},
+		{"ramp first cycle", ramp, 10, true, 0},
+		{"ramp midpoint", ramp, 15, true, 50},
+		{"ramp last cycle", ramp, 20, true, 100},
+		{"ramp stop is exclusive", ramp, 21, false, 0},
+		{"burst on", burst, 6, true, 40},
+		{"burst off", burst, 7, true, 0},
+		{"burst next period", burst, 10, true, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if active := tt.event.ActiveAt(tt.cycle); active != tt.active {
+				t.Errorf("ActiveAt(%d) = %v, want %v", tt.cycle, active, tt.active)
+			}
+			if tt.active {
+				if hz := tt.event.HzAt(tt.cycle); hz != tt.hz {
+					t.Errorf("HzAt(%d) = %g, want %g", tt.cycle, hz, tt.hz)
+				}
+			}
+		})
+	}
+}
+
+func TestStimulusEvent_PresentsAt(t *testing.T) {
+	digit := 3
+	once := StimulusEvent{Type: StimulusPattern, Digit: &digit, Start: 4}
+	repeated := StimulusEvent{Type: StimulusPattern, Digit: &digit, Start: 4, Stop: 20, Every: 5}
+	for cycle := 0; cycle < 25; cycle++ {
+		if got, want := once.PresentsAt(cycle), cycle == 4; got != want {
+			t.Errorf("single presentation: PresentsAt(%d) = %v, want %v", cycle, got, want)
+		}
+		if got, want := repeated.PresentsAt(cycle), cycle == 4 || cycle == 9 || cycle == 14 || cycle == 19; got != want {
+			t.Errorf("repeated presentation: PresentsAt(%d) = %v, want %v", cycle, got, want)
+		}
+	}
+	if (&StimulusEvent{Type: StimulusRest}).PresentsAt(0) {
+		t.Error("a rest event presents a pattern")
+	}
+}
+
+func TestValidate_StimulusFileExcludesStimInputFreq(t *testing.T) {
+	ac := DefaultAppConfig(ModeSim)
+	ac.Cli.StimulusFile = writeStimulusFile(t, "protocol.toml", "[[event]]\ntype = \"rest\"\n")
+	if err := ac.Validate(); err != nil {
+		t.Fatalf("Validate() with a stimulus file error = %v", err)
+	}
+	ac.Cli.StimInputFreqHz = 10
+	if err := ac.Validate(); err == nil {
+		t.Error("Validate() with stimulusFile and stimInputFreqHz: expected an error")
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Sampling strategies of a SweepSpec.
+const (
+	// SweepGrid runs every combination of the parameters' values.
+	SweepGrid = "grid"
+	// SweepRandom runs SweepSpec.Samples configurations drawn at random.
+	SweepRandom = "random"
+)
+
+// SupportedSweepSamplings lists all valid values for SweepSpec.Sampling.
+var SupportedSweepSamplings = []string{SweepGrid, SweepRandom}
+
+// SweepSpec describes a hyperparameter sweep: which configuration fields to vary,
+// how to sample them, and how each configuration is run. It is read from a TOML
+// file with one [[param]] table per varied field.
+type SweepSpec struct {
+	Mode     string       `toml:"mode"`     // ModeExpose or ModeSim; empty means expose.
+	Sampling string       `toml:"sampling"` // One of SupportedSweepSamplings; empty means grid.
+	Samples  int          `toml:"samples"`  // Random sampling: number of configurations drawn.
+	Repeats  int          `toml:"repeats"`  // Runs per configuration, with seeds Seed, Seed+1, ...; 0 means 1.
+	Seed     int64        `toml:"seed"`     // Seed of the first repeat and of random sampling; 0 keeps the base configuration's seed.
+	Parallel int          `toml:"parallel"` // Runs executed at once; 0 means one per CPU.
+	Output   string       `toml:"output"`   // Results file: SQLite for .db/.sqlite, CSV otherwise.
+	Params   []SweepParam `toml:"param"`
+}
+
+// SweepParam is one configuration field varied by a sweep. Its values are either
+// listed in Values or spread over [Min, Max]: Steps evenly spaced points for grid
+// sampling, or uniform draws for random sampling. Log spaces the points (or draws)
+// evenly in log scale. Integer fields are rounded to the nearest integer.
+type SweepParam struct {
+	Name   string   `toml:"name"` // Configuration key, e.g. "sim_params.learning.hebb_positive_reinforce_factor" (see AppConfig.SetField).
+	Values []any    `toml:"values"`
+	Min    *float64 `toml:"min"`
+	Max    *float64 `toml:"max"`
+	Steps  int      `toml:"steps"`
+	Log    bool     `toml:"log"`
+}
+
+// LoadSweepSpec reads a sweep specification from a TOML file and validates it.
+func LoadSweepSpec(filePath string) (*SweepSpec, error) {
+	var spec SweepSpec
+	md, err := toml.DecodeFile(filePath, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sweep spec %s: %w", filePath, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("sweep spec %s has unknown keys: %s", filePath, strings.Join(keys, ", "))
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sweep spec %s: %w", filePath, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks the spec and that every parameter names a settable configuration
+// field whose listed values have the right type.
+func (s *SweepSpec) Validate() error {
+	switch s.Mode {
+	case "", ModeExpose, ModeSim:
+	default:
+		return fmt.Errorf("invalid sweep mode '%s', supported modes are: %s, %s", s.Mode, ModeExpose, ModeSim)
+	}
+	sampling := s.Sampling
+	if sampling == "" {
+		sampling = SweepGrid
+	}
+	validSampling := false
+	for _, supported := range SupportedSweepSamplings {
+		if sampling == supported {
+			validSampling = true
+		}
+	}
+	if !validSampling {
+		return fmt.Errorf("invalid sampling '%s', supported values are: %s",
+			s.Sampling, strings.Join(SupportedSweepSamplings, ", "))
+	}
+	if sampling == SweepRandom && s.Samples <= 0 {
+		return fmt.Errorf("random sampling requires a positive 'samples', got %d", s.Samples)
+	}
+	if s.Repeats < 0 {
+		return fmt.Errorf("repeats must be non-negative, got %d", s.Repeats)
+	}
+	if s.Parallel < 0 {
+		return fmt.Errorf("parallel must be non-negative, got %d", s.Parallel)
+	}
+	if len(s.Params) == 0 {
+		return fmt.Errorf("sweep spec has no [[param]] entries")
+	}
+
+	probe := &AppConfig{SimParams: DefaultSimulationParameters()}
+	seen := make(map[string]bool)
+	for i, p := range s.Params {
+		if p.Name == "" {
+			return fmt.Errorf("param %d has no name", i+1)
+		}
+		if seen[normalizeKey(p.Name)] {
+			return fmt.Errorf("param %s is listed more than once", p.Name)
+		}
+		seen[normalizeKey(p.Name)] = true
+		kind, err := probe.FieldKind(p.Name)
+		if err != nil {
+			return err
+		}
+		isRange := p.Min != nil || p.Max != nil
+		switch {
+		case len(p.Values) > 0 && isRange:
+			return fmt.Errorf("param %s: give either values or min/max, not both", p.Name)
+		case len(p.Values) > 0:
+			for _, v := range p.Values {
+				if err := probe.SetField(p.Name, v); err != nil {
+					return err
+				}
+			}
+		case isRange:
+			if p.Min == nil || p.Max == nil || *p.Min > *p.Max {
+				return fmt.Errorf("param %s: min and max must both be set with min <= max", p.Name)
+			}
+			if kind == reflect.String || kind == reflect.Bool {
+				return fmt.Errorf("param %s: a %s field needs a list of values, not a range", p.Name, kind)
+			}
+			if p.Log && *p.Min <= 0 {
+				return fmt.Errorf("param %s: log scale needs a positive min, got %g", p.Name, *p.Min)
+			}
+			if sampling == SweepGrid && p.Steps < 1 {
+				return fmt.Errorf("param %s: grid sampling of a range needs steps >= 1", p.Name)
+			}
+		default:
+			return fmt.Errorf("param %s has neither values nor min/max", p.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"crownet/network"
+	"crownet/storage"
+)
+
+const (
+	// rateWindow is the minimum period over which cycles per second are measured.
+	rateWindow = time.Second
+	// weightStatsInterval is the minimum time between two computations of the weight
+	// mean and variance, which visit every synapse.
+	weightStatsInterval = time.Second
+)
+
+// Exporter serves the state of a running network in the Prometheus text format on
+// GET /metrics. The values are taken by ObserveCycle, so a scrape never touches the
+// network and sees the state at the end of the last observed cycle.
+type Exporter struct {
+	writeStats func() storage.WriteStats // Nil when the run has no SQLite logger.
+	server     *http.Server
+
+	mu              sync.Mutex
+	cycles          int64
+	cyclesPerSecond float64
+	activePulses    int
+	spikes          firingCounts
+	cortisol        float64
+	dopamine        float64
+	synapses        int
+	weightMean      float64
+	weightVariance  float64
+	sqlite          storage.WriteStats
+
+	// Accessed only by ObserveCycle, from the simulation goroutine.
+	rateStart      time.Time
+	rateCycles     int64
+	weightsUpdated time.Time
+}
+
+// NewExporter returns an exporter. writeStats, if not nil, reports the SQLite write
+// latency of the run (see storage.SQLiteLogger.WriteStats).
+func NewExporter(writeStats func() storage.WriteStats) *Exporter {
+	return &Exporter{writeStats: writeStats}
+}
+
+// Start serves the exporter on addr in the background and returns the address it
+// listens on (useful with port 0).
+func (e *Exporter) Start(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", e)
+	e.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = e.server.Serve(listener) }()
+	return listener.Addr(), nil
+}
+
+// Close stops the server started by Start.
+func (e *Exporter) Close() error {
+	if e.server == nil {
+		return nil
+	}
+	return e.server.Close()
+}
+
+// ObserveCycle takes the state of net after a cycle. The weight statistics are
+// refreshed at most once per weightStatsInterval.
+func (e *Exporter) ObserveCycle(net *network.CrowNet) {
+	now := time.Now()
+	if e.rateStart.IsZero() {
+		e.rateStart = now
+	}
+	refreshWeights := e.weightsUpdated.IsZero() || now.Sub(e.weightsUpdated) >= weightStatsInterval
+	var synapses int
+	var mean, variance float64
+	if refreshWeights && net.SynapticWeights != nil {
+		synapses, mean, variance = net.SynapticWeights.Stats()
+		e.weightsUpdated = now
+	}
+	var sqlite storage.WriteStats
+	if e.writeStats != nil {
+		sqlite = e.writeStats()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cycles++
+	if elapsed := now.Sub(e.rateStart); elapsed >= rateWindow {
+		e.cyclesPerSecond = float64(e.cycles-e.rateCycles) / elapsed.Seconds()
+		e.rateStart, e.rateCycles = now, e.cycles
+	}
+	e.activePulses = len(net.ActivePulses.GetAll())
+	e.spikes.add(net)
+	e.cortisol = float64(net.ChemicalEnv.CortisolLevel)
+	e.dopamine = float64(net.ChemicalEnv.DopamineLevel)
+	if refreshWeights {
+		e.synapses, e.weightMean, e.weightVariance = synapses, mean, variance
+	}
+	e.sqlite = sqlite
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	e.mu.Lock()
+	writeMetric(&buf, "crownet_cycles_total", "counter", "Simulation cycles completed by this run.", e.cycles)
+	writeMetric(&buf, "crownet_cycles_per_second", "gauge", "Simulation speed, measured over the last second or more.", e.cyclesPerSecond)
+	writeMetric(&buf, "crownet_active_pulses", "gauge", "Pulses propagating at the end of the last cycle.", e.activePulses)
+	fmt.Fprintf(&buf, "# HELP crownet_spikes_total Neuron firings by neuron type.\n# TYPE crownet_spikes_total counter\n")
+	for _, t := range neuronTypes {
+		fmt.Fprintf(&buf, "crownet_spikes_total{type=%q} %d\n", strings.ToLower(t.String()), e.spikes[t])
+	}
+	writeMetric(&buf, "crownet_cortisol_level", "gauge", "Cortisol level.", e.cortisol)
+	writeMetric(&buf, "crownet_dopamine_level", "gauge", "Dopamine level.", e.dopamine)
+	writeMetric(&buf, "crownet_synapses", "gauge", "Synapses in the network.", e.synapses)
+	writeMetric(&buf, "crownet_weight_mean", "gauge", "Mean synaptic weight.", e.weightMean)
+	writeMetric(&buf, "crownet_weight_variance", "gauge", "Population variance of the synaptic weights.", e.weightVariance)
+	if e.writeStats != nil {
+		fmt.Fprintf(&buf, "# HELP crownet_sqlite_write_seconds Duration of the SQLite log writes.\n# TYPE crownet_sqlite_write_seconds summary\n")
+		fmt.Fprintf(&buf, "crownet_sqlite_write_seconds_sum %g\ncrownet_sqlite_write_seconds_count %d\n",
+			e.sqlite.TotalTime.Seconds(), e.sqlite.Writes)
+		writeMetric(&buf, "crownet_sqlite_last_write_seconds", "gauge", "Duration of the most recent SQLite log write.", e.sqlite.LastTime.Seconds())
+	}
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeMetric writes a metric without labels, with its HELP and TYPE lines.
+func writeMetric(buf *bytes.Buffer, name, metricType, help string, value any) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	switch v := value.(type) {
+	case float64:
+		fmt.Fprintf(buf, "%s %g\n", name, v)
+	default:
+		fmt.Fprintf(buf, "%s %d\n", name, v)
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bufio"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"crownet/storage"
+)
+
+// scrapeMetrics serves one GET /metrics from e and returns the samples by name
+// (including labels), skipping the HELP and TYPE lines.
+func scrapeMetrics(t *testing.T, e *Exporter) map[string]string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Content-Type = %q, want text/plain", ct)
+	}
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, value, ok := strings.Cut(line, " "); ok {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func TestExporter_Metrics(t *testing.T) {
+	writes := storage.WriteStats{Writes: 5, TotalTime: 2 * time.Second, LastTime: 250 * time.Millisecond}
+	exporter := NewExporter(func() storage.WriteStats { return writes })
+	net := newMetricsTestNet(t)
+	for i := 0; i < 6; i++ {
+		net.RunCycle()
+		exporter.ObserveCycle(net)
+	}
+
+	values := scrapeMetrics(t, exporter)
+	want := map[string]string{
+		"crownet_cycles_total":               "6",
+		"crownet_sqlite_write_seconds_count": "5",
+		"crownet_sqlite_write_seconds_sum":   "2",
+		"crownet_sqlite_last_write_seconds":  "0.25",
+	}
+	for name, value := range want {
+		if values[name] != value {
+			t.Errorf("%s = %q, want %q", name, values[name], value)
+		}
+	}
+	for _, name := range []string{"crownet_cycles_per_second", "crownet_active_pulses", `crownet_spikes_total{type="excitatory"}`,
+		`crownet_spikes_total{type="output"}`, "crownet_cortisol_level", "crownet_dopamine_level",
+		"crownet_weight_mean", "crownet_weight_variance"} {
+		if _, ok := values[name]; !ok {
+			t.Errorf("metric %s missing from scrape: %v", name, values)
+		}
+	}
+	if values["crownet_synapses"] == "0" {
+		t.Error("crownet_synapses = 0, want the weight statistics to cover the network's synapses")
+	}
+}
+
+func TestExporter_WithoutSQLiteLogger(t *testing.T) {
+	exporter := NewExporter(nil)
+	net := newMetricsTestNet(t)
+	net.RunCycle()
+	exporter.ObserveCycle(net)
+
+	values := scrapeMetrics(t, exporter)
+	if values["crownet_cycles_total"] != "1" {
+		t.Errorf("crownet_cycles_total = %q, want 1", values["crownet_cycles_total"])
+	}
+	for name := range values {
+		if strings.HasPrefix(name, "crownet_sqlite_") {
+			t.Errorf("metric %s exported without an SQLite logger", name)
+		}
+	}
+}
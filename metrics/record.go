@@ -0,0 +1,69 @@
+// Package metrics measures a running network cycle by cycle and publishes the
+// measurements to live consumers: the Server-Sent Events stream and the
+// Prometheus endpoint of sim and expose runs.
+package metrics
+
+import (
+	"crownet/common"
+	"crownet/network"
+	"crownet/neuron"
+)
+
+// neuronTypes lists every neuron type, in the order used for per-type counts.
+var neuronTypes = []neuron.Type{neuron.Excitatory, neuron.Inhibitory, neuron.Dopaminergic, neuron.Input, neuron.Output}
+
+// CycleRecord describes the network after a cycle. Firings are summed over the
+// Cycles cycles since the previous record; the other values are taken at the end.
+type CycleRecord struct {
+	Cycle                    common.CycleCount           `json:"cycle"`  // Index of the last cycle covered (CycleCount-1).
+	Cycles                   int                         `json:"cycles"` // Cycles covered by the record.
+	ActivePulses             int                         `json:"active_pulses"`
+	FiringsByType            map[string]int              `json:"firings_by_type"` // Keyed by neuron type, e.g. "Excitatory".
+	Cortisol                 common.Level                `json:"cortisol"`
+	Dopamine                 common.Level                `json:"dopamine"`
+	LearningRateModulation   common.Factor               `json:"learning_rate_modulation"`
+	SynaptogenesisModulation common.Factor               `json:"synaptogenesis_modulation"`
+	OutputFrequencies        map[common.NeuronID]float64 `json:"output_frequencies_hz"` // Keyed by output neuron ID.
+}
+
+// firingCounts accumulates firings per neuron type.
+type firingCounts [int(neuron.Output) + 1]int
+
+// add counts the neurons of net that fired in the last cycle.
+func (c *firingCounts) add(net *network.CrowNet) {
+	for _, id := range net.FiredLastCycle() {
+		if n, ok := net.GetNeuron(id); ok && int(n.Type) < len(c) {
+			c[n.Type]++
+		}
+	}
+}
+
+func (c *firingCounts) byType() map[string]int {
+	counts := make(map[string]int, len(neuronTypes))
+	for _, t := range neuronTypes {
+		counts[t.String()] = c[t]
+	}
+	return counts
+}
+
+// newCycleRecord takes the current state of net, with the firings counted over cycles cycles.
+func newCycleRecord(net *network.CrowNet, cycles int, firings *firingCounts) CycleRecord {
+	env := net.ChemicalEnv
+	record := CycleRecord{
+		Cycle:                    net.CycleCount - 1,
+		Cycles:                   cycles,
+		ActivePulses:             len(net.ActivePulses.GetAll()),
+		FiringsByType:            firings.byType(),
+		Cortisol:                 env.CortisolLevel,
+		Dopamine:                 env.DopamineLevel,
+		LearningRateModulation:   env.LearningRateModulationFactor,
+		SynaptogenesisModulation: env.SynaptogenesisModulationFactor,
+		OutputFrequencies:        make(map[common.NeuronID]float64, len(net.OutputNeuronIDs)),
+	}
+	for _, id := range net.OutputNeuronIDs {
+		if freq, err := net.GetOutputFrequency(id); err == nil {
+			record.OutputFrequencies[id] = freq
+		}
+	}
+	return record
+}
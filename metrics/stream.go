@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"crownet/network"
+)
+
+// clientBuffer is the number of records queued for each stream client. A client
+// that falls further behind misses records instead of slowing the simulation.
+const clientBuffer = 256
+
+// Stream publishes a CycleRecord every few cycles to the clients connected to its
+// Server-Sent Events endpoint, GET /events. Each event carries one record as JSON
+// in its data field and the record's cycle as its id.
+type Stream struct {
+	every   int
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	closed  bool
+	server  *http.Server
+
+	// Accessed only by ObserveCycle, from the simulation goroutine.
+	cycles  int
+	firings firingCounts
+}
+
+// NewStream returns a stream that publishes a record every `every` cycles (every
+// cycle if every <= 1).
+func NewStream(every int) *Stream {
+	if every < 1 {
+		every = 1
+	}
+	return &Stream{every: every, clients: make(map[chan []byte]struct{})}
+}
+
+// Start serves the stream on addr in the background and returns the address it
+// listens on (useful with port 0).
+func (s *Stream) Start(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /events", s)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = s.server.Serve(listener) }()
+	return listener.Addr(), nil
+}
+
+// Close ends the connected clients' streams and stops the server started by Start.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for c := range s.clients {
+			close(c)
+			delete(s.clients, c)
+		}
+	}
+	s.mu.Unlock()
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+// ObserveCycle counts the firings of the cycle net has just completed and, every
+// `every` cycles, publishes a record to the connected clients.
+func (s *Stream) ObserveCycle(net *network.CrowNet) {
+	s.cycles++
+	s.firings.add(net)
+	if s.cycles < s.every {
+		return
+	}
+	if s.hasClients() {
+		s.publish(newCycleRecord(net, s.cycles, &s.firings))
+	}
+	s.cycles = 0
+	s.firings = firingCounts{}
+}
+
+func (s *Stream) hasClients() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients) > 0
+}
+
+func (s *Stream) publish(record CycleRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	event := []byte(fmt.Sprintf("id: %d\ndata: %s\n\n", record.Cycle, data))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c <- event:
+		default: // Client too slow: drop the record for it.
+		}
+	}
+}
+
+// ServeHTTP streams records to the client until it disconnects or the stream is closed.
+func (s *Stream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	c := make(chan []byte, clientBuffer)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		http.Error(w, "stream closed", http.StatusServiceUnavailable)
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-c:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"crownet/config"
+	"crownet/network"
+)
+
+// newMetricsTestNet builds a small network with a fixed seed.
+func newMetricsTestNet(t *testing.T) *network.CrowNet {
+	t.Helper()
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	appCfg.Cli.TotalNeurons = 60
+	appCfg.Cli.Seed = 7
+	net, err := network.NewCrowNet(appCfg)
+	if err != nil {
+		t.Fatalf("NewCrowNet() error = %v", err)
+	}
+	return net
+}
+
+func TestStream_PublishesEveryNCycles(t *testing.T) {
+	stream := NewStream(2)
+	addr, err := stream.Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer stream.Close()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/events", addr))
+	if err != nil {
+		t.Fatalf("Failed to connect to the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	net := newMetricsTestNet(t)
+	for i := 0; i < 4; i++ {
+		net.RunCycle()
+		stream.ObserveCycle(net)
+	}
+
+	var records []CycleRecord
+	scanner := bufio.NewScanner(resp.Body)
+	for len(records) < 2 && scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var record CycleRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			t.Fatalf("Invalid record %q: %v", data, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (every 2 of 4 cycles): %v", len(records), scanner.Err())
+	}
+	last := records[1]
+	if last.Cycle != 3 || last.Cycles != 2 {
+		t.Errorf("last record covers cycle %d over %d cycles, want cycles 2-3", last.Cycle, last.Cycles)
+	}
+	if _, ok := last.FiringsByType["Excitatory"]; !ok {
+		t.Errorf("FiringsByType = %v, want an entry for every neuron type", last.FiringsByType)
+	}
+	if len(last.OutputFrequencies) != len(net.OutputNeuronIDs) {
+		t.Errorf("OutputFrequencies = %v, want %d entries", last.OutputFrequencies, len(net.OutputNeuronIDs))
+	}
+
+	// Closing the stream ends the client's response (the scan would block otherwise).
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for scanner.Scan() {
+	}
+}
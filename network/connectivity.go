@@ -0,0 +1,74 @@
+package network
+
+import (
+	"fmt"
+	"math/rand"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+	"crownet/synaptic"
+)
+
+// typePair identifies a (presynaptic type, postsynaptic type) combination.
+type typePair struct {
+	pre  neuron.Type
+	post neuron.Type
+}
+
+// connectivityMatrix maps neuron type pairs to the configured connectivity rule.
+type connectivityMatrix map[typePair]*config.ConnectivityRule
+
+// newConnectivityMatrix indexes the configured rules by neuron type pair.
+func newConnectivityMatrix(rules []config.ConnectivityRule) (connectivityMatrix, error) {
+	m := make(connectivityMatrix, len(rules))
+	for i := range rules {
+		pre, err := neuron.ParseType(rules[i].Pre)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity rule %d: %w", i, err)
+		}
+		post, err := neuron.ParseType(rules[i].Post)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity rule %d: %w", i, err)
+		}
+		key := typePair{pre: pre, post: post}
+		if _, exists := m[key]; exists {
+			return nil, fmt.Errorf("connectivity rule %d: duplicate rule for %s -> %s", i, pre, post)
+		}
+		m[key] = &rules[i]
+	}
+	return m, nil
+}
+
+// apply filters the generated connections through the matrix. A connection whose
+// type pair has a rule is kept with the rule's probability and takes its weight
+// distribution; connections without a rule are kept unchanged. config.Validate only
+// accepts rules with the all_to_all topology, so the rule's probability is the
+// per-pair connection probability. Connections are visited in slice order, so the
+// RNG is consumed deterministically.
+func (m connectivityMatrix) apply(
+	neurons []*neuron.Neuron,
+	connections []synaptic.Connection,
+	rng *rand.Rand,
+) []synaptic.Connection {
+	if len(m) == 0 {
+		return connections
+	}
+	types := make(map[common.NeuronID]neuron.Type, len(neurons))
+	for _, n := range neurons {
+		types[n.ID] = n.Type
+	}
+
+	kept := connections[:0]
+	for _, c := range connections {
+		rule, ok := m[typePair{pre: types[c.From], post: types[c.To]}]
+		if ok {
+			if rng.Float64() >= rule.Probability {
+				continue
+			}
+			c.Distribution = &rule.Weight
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+)
+
+// assignNeuronParameters gives every neuron its intrinsic parameters: the global
+// NeuronBehaviorParams values, replaced by the override for the neuron's type if
+// one is configured, then multiplied by a seeded random factor for each enabled
+// jitter. Neurons are visited in ID order and no random numbers are drawn when
+// jitter is disabled, so the rest of the initialization sees the same RNG stream.
+func (cn *CrowNet) assignNeuronParameters() error {
+	nb := &cn.SimParams.SimParams.NeuronBehavior
+	overrides := make(map[neuron.Type]*config.NeuronTypeOverride, len(nb.TypeOverrides))
+	for i := range nb.TypeOverrides {
+		t, err := neuron.ParseType(nb.TypeOverrides[i].Type)
+		if err != nil {
+			return fmt.Errorf("neuron type override %d: %w", i, err)
+		}
+		overrides[t] = &nb.TypeOverrides[i]
+	}
+
+	for _, n := range cn.Neurons {
+		p := neuron.Parameters{
+			BaseFiringThreshold:      nb.BaseFiringThreshold,
+			DecayRate:                nb.AccumulatedPulseDecayRate,
+			AbsoluteRefractoryCycles: nb.AbsoluteRefractoryCycles,
+			RelativeRefractoryCycles: nb.RelativeRefractoryCycles,
+		}
+		if o, ok := overrides[n.Type]; ok {
+			if o.BaseFiringThreshold != nil {
+				p.BaseFiringThreshold = *o.BaseFiringThreshold
+			}
+			if o.AccumulatedPulseDecayRate != nil {
+				p.DecayRate = *o.AccumulatedPulseDecayRate
+			}
+			if o.AbsoluteRefractoryCycles != nil {
+				p.AbsoluteRefractoryCycles = *o.AbsoluteRefractoryCycles
+			}
+			if o.RelativeRefractoryCycles != nil {
+				p.RelativeRefractoryCycles = *o.RelativeRefractoryCycles
+			}
+		}
+
+		if nb.ThresholdJitter.Enabled() {
+			p.BaseFiringThreshold *= common.Threshold(jitterFactor(&nb.ThresholdJitter, cn.rng))
+		}
+		if nb.DecayJitter.Enabled() {
+			p.DecayRate = common.Rate(math.Min(1.0, float64(p.DecayRate)*jitterFactor(&nb.DecayJitter, cn.rng)))
+		}
+		if nb.RefractoryJitter.Enabled() {
+			p.AbsoluteRefractoryCycles = jitterCycles(p.AbsoluteRefractoryCycles, &nb.RefractoryJitter, cn.rng)
+			p.RelativeRefractoryCycles = jitterCycles(p.RelativeRefractoryCycles, &nb.RefractoryJitter, cn.rng)
+		}
+		n.SetParameters(p)
+	}
+	return nil
+}
+
+// jitterFactor draws a multiplicative factor with mean 1 and standard deviation
+// j.RelativeStdDev from the configured distribution, clipped to [MinFactor, MaxFactor].
+// For the log-normal case the underlying normal is parameterised so that the factor
+// itself (not its logarithm) has mean 1 and the requested relative standard deviation.
+func jitterFactor(j *config.ParameterJitter, rng *rand.Rand) float64 {
+	var factor float64
+	switch j.Distribution {
+	case config.WeightLogNormal:
+		sigma := math.Sqrt(math.Log(1 + j.RelativeStdDev*j.RelativeStdDev))
+		factor = math.Exp(-sigma*sigma/2 + sigma*rng.NormFloat64())
+	default: // config.WeightNormal
+		factor = 1 + j.RelativeStdDev*rng.NormFloat64()
+	}
+	return math.Max(j.MinFactor, math.Min(j.MaxFactor, factor))
+}
+
+// jitterCycles scales a cycle count by a jitter factor and rounds to the nearest whole cycle.
+func jitterCycles(cycles common.CycleCount, j *config.ParameterJitter, rng *rand.Rand) common.CycleCount {
+	return common.CycleCount(math.Round(float64(cycles) * jitterFactor(j, rng)))
+}
+
+// NeuronParameters returns the intrinsic parameters of every neuron, keyed by ID.
+func (cn *CrowNet) NeuronParameters() map[common.NeuronID]neuron.Parameters {
+	params := make(map[common.NeuronID]neuron.Parameters, len(cn.Neurons))
+	for _, n := range cn.Neurons {
+		params[n.ID] = n.Parameters()
+	}
+	return params
+}
+
+// LoadNeuronParameters restores previously saved intrinsic parameters. Every
+// neuron ID in params must exist in the network; neurons missing from params keep
+// their current values.
+func (cn *CrowNet) LoadNeuronParameters(params map[common.NeuronID]neuron.Parameters) error {
+	for id := range params {
+		if _, ok := cn.neuronMap[id]; !ok {
+			return fmt.Errorf("neuron parameters reference unknown neuron ID %d", id)
+		}
+	}
+	for _, n := range cn.Neurons {
+		if p, ok := params[n.ID]; ok {
+			n.SetParameters(p)
+		}
+	}
+	return nil
+}
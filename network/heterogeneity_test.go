@@ -0,0 +1,153 @@
+package network
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+)
+
+// newTestAppConfig returns the sim mode defaults for a 60-neuron network (35 input,
+// 10 output and 15 internal neurons) built with the given seed. Tests in this
+// package adjust it before calling NewCrowNet.
+func newTestAppConfig(seed int64) *config.AppConfig {
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	appCfg.Cli.TotalNeurons = 60
+	appCfg.Cli.Seed = seed
+	return appCfg
+}
+
+func TestAssignNeuronParameters_TypeOverride(t *testing.T) {
+	appCfg := newTestAppConfig(1)
+	nb := &appCfg.SimParams.NeuronBehavior
+	threshold := common.Threshold(2.5)
+	refractory := common.CycleCount(7)
+	nb.TypeOverrides = []config.NeuronTypeOverride{
+		{Type: "inhibitory", BaseFiringThreshold: &threshold, AbsoluteRefractoryCycles: &refractory},
+	}
+	net, err := NewCrowNet(appCfg)
+	if err != nil {
+		t.Fatalf("NewCrowNet() error = %v", err)
+	}
+
+	inhibitory := 0
+	for _, n := range net.Neurons {
+		p := n.Parameters()
+		if n.Type != neuron.Inhibitory {
+			if p.BaseFiringThreshold != nb.BaseFiringThreshold || p.AbsoluteRefractoryCycles != nb.AbsoluteRefractoryCycles {
+				t.Errorf("%s neuron %d: parameters %+v, want the global values", n.Type, n.ID, p)
+			}
+			continue
+		}
+		inhibitory++
+		if p.BaseFiringThreshold != threshold || p.AbsoluteRefractoryCycles != refractory {
+			t.Errorf("inhibitory neuron %d: parameters %+v, want threshold %f and absolute refractory %d",
+				n.ID, p, threshold, refractory)
+		}
+		// Values without an override keep the global setting.
+		if p.DecayRate != nb.AccumulatedPulseDecayRate || p.RelativeRefractoryCycles != nb.RelativeRefractoryCycles {
+			t.Errorf("inhibitory neuron %d: parameters %+v, want the global decay and relative refractory values", n.ID, p)
+		}
+		if n.CurrentFiringThreshold != threshold {
+			t.Errorf("inhibitory neuron %d: CurrentFiringThreshold = %f, want the overridden base %f",
+				n.ID, n.CurrentFiringThreshold, threshold)
+		}
+	}
+	if inhibitory == 0 {
+		t.Fatal("network has no inhibitory neurons")
+	}
+}
+
+func TestJitterFactor_StaysInBounds(t *testing.T) {
+	for _, distribution := range []string{config.WeightNormal, config.WeightLogNormal} {
+		t.Run(distribution, func(t *testing.T) {
+			// A wide distribution, so that both bounds are reached.
+			j := &config.ParameterJitter{Distribution: distribution, RelativeStdDev: 1.0, MinFactor: 0.8, MaxFactor: 1.3}
+			rng := rand.New(rand.NewSource(1))
+			sawMin, sawMax := false, false
+			for i := 0; i < 10000; i++ {
+				f := jitterFactor(j, rng)
+				if f < j.MinFactor || f > j.MaxFactor {
+					t.Fatalf("jitterFactor() = %f, outside [%f, %f]", f, j.MinFactor, j.MaxFactor)
+				}
+				sawMin = sawMin || f == j.MinFactor
+				sawMax = sawMax || f == j.MaxFactor
+			}
+			if !sawMin || !sawMax {
+				t.Errorf("expected draws clipped to both bounds (min: %v, max: %v)", sawMin, sawMax)
+			}
+		})
+	}
+}
+
+func TestJitterFactor_MeanIsOne(t *testing.T) {
+	for _, distribution := range []string{config.WeightNormal, config.WeightLogNormal} {
+		j := &config.ParameterJitter{Distribution: distribution, RelativeStdDev: 0.1, MinFactor: 0, MaxFactor: 10}
+		rng := rand.New(rand.NewSource(1))
+		sum := 0.0
+		const draws = 20000
+		for i := 0; i < draws; i++ {
+			sum += jitterFactor(j, rng)
+		}
+		if mean := sum / draws; math.Abs(mean-1) > 0.01 {
+			t.Errorf("%s: mean jitter factor = %f, want 1", distribution, mean)
+		}
+	}
+}
+
+func TestJitterCycles_StaysInBounds(t *testing.T) {
+	j := &config.ParameterJitter{Distribution: config.WeightNormal, RelativeStdDev: 1.0, MinFactor: 0.5, MaxFactor: 2.0}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if c := jitterCycles(5, j, rng); c < 3 || c > 10 { // round(5 * 0.5) = 3, 5 * 2 = 10
+			t.Fatalf("jitterCycles(5) = %d, outside [3, 10]", c)
+		}
+	}
+}
+
+func TestAssignNeuronParameters_JitterReproducible(t *testing.T) {
+	build := func(seed int64) map[common.NeuronID]neuron.Parameters {
+		appCfg := newTestAppConfig(seed)
+		nb := &appCfg.SimParams.NeuronBehavior
+		nb.ThresholdJitter.Distribution = config.WeightNormal
+		nb.DecayJitter.Distribution = config.WeightLogNormal
+		nb.RefractoryJitter.Distribution = config.WeightNormal
+		net, err := NewCrowNet(appCfg)
+		if err != nil {
+			t.Fatalf("NewCrowNet() error = %v", err)
+		}
+		return net.NeuronParameters()
+	}
+
+	first := build(7)
+	if !reflect.DeepEqual(first, build(7)) {
+		t.Error("the same seed produced different neuron parameters")
+	}
+	if reflect.DeepEqual(first, build(8)) {
+		t.Error("different seeds produced the same neuron parameters")
+	}
+
+	distinct := make(map[common.Threshold]bool)
+	for _, p := range first {
+		distinct[p.BaseFiringThreshold] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("threshold jitter produced a single threshold for all %d neurons", len(first))
+	}
+}
+
+func TestLoadNeuronParameters_RejectsUnknownNeuron(t *testing.T) {
+	net, err := NewCrowNet(newTestAppConfig(1))
+	if err != nil {
+		t.Fatalf("NewCrowNet() error = %v", err)
+	}
+	params := net.NeuronParameters()
+	params[common.NeuronID(len(net.Neurons)+100)] = neuron.Parameters{BaseFiringThreshold: 1}
+	if err := net.LoadNeuronParameters(params); err == nil {
+		t.Error("LoadNeuronParameters() with an unknown neuron ID: expected an error")
+	}
+}
@@ -0,0 +1,85 @@
+package network
+
+import (
+	"fmt"
+	"math"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+)
+
+// homeostasisRule holds the resolved homeostasis settings for one neuron type.
+type homeostasisRule struct {
+	enabled            bool
+	targetFiringRateHz float64
+	timeConstantCycles float64
+}
+
+// newHomeostasisRules resolves the global homeostasis settings and per-type
+// overrides into one rule per neuron type.
+func newHomeostasisRules(params *config.HomeostasisParams) (map[neuron.Type]homeostasisRule, error) {
+	base := homeostasisRule{
+		enabled:            params.Enabled,
+		targetFiringRateHz: params.TargetFiringRateHz,
+		timeConstantCycles: params.TimeConstantCycles,
+	}
+	rules := make(map[neuron.Type]homeostasisRule)
+	for _, t := range []neuron.Type{neuron.Excitatory, neuron.Inhibitory, neuron.Dopaminergic, neuron.Input, neuron.Output} {
+		rules[t] = base
+	}
+	for i, o := range params.TypeOverrides {
+		t, err := neuron.ParseType(o.Type)
+		if err != nil {
+			return nil, fmt.Errorf("homeostasis type override %d: %w", i, err)
+		}
+		rule := rules[t]
+		if o.Enabled != nil {
+			rule.enabled = *o.Enabled
+		}
+		if o.TargetFiringRateHz != nil {
+			rule.targetFiringRateHz = *o.TargetFiringRateHz
+		}
+		if o.TimeConstantCycles != nil {
+			rule.timeConstantCycles = *o.TimeConstantCycles
+		}
+		rules[t] = rule
+	}
+	return rules, nil
+}
+
+// applyHomeostasis nudges each neuron's base firing threshold towards the value
+// that yields its target firing rate. The rate is estimated from FiringHistory
+// over the last OutputFrequencyWindowCycles cycles (the span that history keeps).
+// The threshold is scaled by 1 + (rate - target) / (target * tau): it rises while
+// the neuron fires too often and decays with time constant tau while it is silent.
+// CurrentFiringThreshold is left to the neurochemical step, which derives it from
+// the adapted base threshold.
+func (cn *CrowNet) applyHomeostasis() {
+	h := &cn.SimParams.SimParams.Homeostasis
+	window := cn.SimParams.SimParams.Structure.OutputFrequencyWindowCycles
+	cyclesPerSecond := cn.SimParams.SimParams.General.CyclesPerSecond
+	if window <= 0 || cyclesPerSecond <= 0 {
+		return
+	}
+	windowSeconds := window / cyclesPerSecond
+	cutoff := cn.CycleCount - common.CycleCount(window)
+
+	for _, n := range cn.Neurons {
+		rule, ok := cn.homeostasisRules[n.Type]
+		if !ok || !rule.enabled {
+			continue
+		}
+		spikes := 0
+		for _, fired := range n.FiringHistory {
+			if fired > cutoff {
+				spikes++
+			}
+		}
+		rate := float64(spikes) / windowSeconds
+		factor := 1 + (rate-rule.targetFiringRateHz)/(rule.targetFiringRateHz*rule.timeConstantCycles)
+		adapted := float64(n.BaseFiringThreshold) * factor
+		adapted = math.Max(float64(h.MinThreshold), math.Min(float64(h.MaxThreshold), adapted))
+		n.BaseFiringThreshold = common.Threshold(adapted)
+	}
+}
@@ -0,0 +1,200 @@
+package network
+
+import (
+	"math"
+	"testing"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+)
+
+// newHomeostasisTestNet builds the network of newTestAppConfig with a firing rate
+// window of one second (100 cycles at 100 cycles per second) and homeostasis
+// enabled at a 5 Hz target with a time constant of 10 cycles. configure may adjust
+// the homeostasis settings before the network is built.
+func newHomeostasisTestNet(t *testing.T, configure func(h *config.HomeostasisParams)) *CrowNet {
+	t.Helper()
+	appCfg := newTestAppConfig(1)
+	appCfg.SimParams.General.CyclesPerSecond = 100
+	appCfg.SimParams.Structure.OutputFrequencyWindowCycles = 100
+	h := &appCfg.SimParams.Homeostasis
+	h.Enabled = true
+	h.TargetFiringRateHz = 5
+	h.TimeConstantCycles = 10
+	h.MinThreshold = 0.1
+	h.MaxThreshold = 10
+	if configure != nil {
+		configure(h)
+	}
+	net, err := NewCrowNet(appCfg)
+	if err != nil {
+		t.Fatalf("NewCrowNet() error = %v", err)
+	}
+	net.CycleCount = 100
+	return net
+}
+
+// setFiringRate fills n's firing history with hz spikes spread over the last
+// second (the 100-cycle window of newHomeostasisTestNet).
+func setFiringRate(n *neuron.Neuron, now common.CycleCount, hz int) {
+	n.FiringHistory = n.FiringHistory[:0]
+	for i := 0; i < hz; i++ {
+		n.FiringHistory = append(n.FiringHistory, now-common.CycleCount(i))
+	}
+}
+
+// firstNeuronOfType returns the first neuron of net with type typ.
+func firstNeuronOfType(t *testing.T, net *CrowNet, typ neuron.Type) *neuron.Neuron {
+	t.Helper()
+	for _, n := range net.Neurons {
+		if n.Type == typ {
+			return n
+		}
+	}
+	t.Fatalf("network has no %s neuron", typ)
+	return nil
+}
+
+func TestNewHomeostasisRules(t *testing.T) {
+	disabled := false
+	target := 2.0
+	params := config.HomeostasisParams{
+		Enabled:            true,
+		TargetFiringRateHz: 5,
+		TimeConstantCycles: 100,
+		TypeOverrides: []config.HomeostasisTypeOverride{
+			{Type: "input", Enabled: &disabled},
+			{Type: "Inhibitory", TargetFiringRateHz: &target},
+		},
+	}
+	rules, err := newHomeostasisRules(&params)
+	if err != nil {
+		t.Fatalf("newHomeostasisRules() error = %v", err)
+	}
+
+	if r := rules[neuron.Excitatory]; !r.enabled || r.targetFiringRateHz != 5 || r.timeConstantCycles != 100 {
+		t.Errorf("Excitatory rule = %+v, want the global settings", r)
+	}
+	if r := rules[neuron.Input]; r.enabled {
+		t.Errorf("Input rule = %+v, want adaptation disabled by its override", r)
+	}
+	if r := rules[neuron.Inhibitory]; !r.enabled || r.targetFiringRateHz != 2 || r.timeConstantCycles != 100 {
+		t.Errorf("Inhibitory rule = %+v, want target 2 Hz and the global time constant", r)
+	}
+
+	params.TypeOverrides = []config.HomeostasisTypeOverride{{Type: "glial"}}
+	if _, err := newHomeostasisRules(&params); err == nil {
+		t.Error("newHomeostasisRules() with an unknown type override: expected an error")
+	}
+}
+
+func TestApplyHomeostasis(t *testing.T) {
+	t.Run("raises the threshold of a neuron firing above target", func(t *testing.T) {
+		net := newHomeostasisTestNet(t, nil)
+		n := firstNeuronOfType(t, net, neuron.Excitatory)
+		n.BaseFiringThreshold = 1.0
+		setFiringRate(n, net.CycleCount, 20)
+
+		net.applyHomeostasis()
+		// factor = 1 + (20 - 5) / (5 * 10)
+		if want := 1.3; math.Abs(float64(n.BaseFiringThreshold)-want) > 1e-9 {
+			t.Errorf("BaseFiringThreshold = %f, want %f", n.BaseFiringThreshold, want)
+		}
+	})
+
+	t.Run("lowers the threshold of a silent neuron", func(t *testing.T) {
+		net := newHomeostasisTestNet(t, nil)
+		n := firstNeuronOfType(t, net, neuron.Excitatory)
+		n.BaseFiringThreshold = 1.0
+		setFiringRate(n, net.CycleCount, 0)
+
+		net.applyHomeostasis()
+		// factor = 1 + (0 - 5) / (5 * 10)
+		if want := 0.9; math.Abs(float64(n.BaseFiringThreshold)-want) > 1e-9 {
+			t.Errorf("BaseFiringThreshold = %f, want %f", n.BaseFiringThreshold, want)
+		}
+	})
+
+	t.Run("keeps thresholds within the configured bounds", func(t *testing.T) {
+		net := newHomeostasisTestNet(t, func(h *config.HomeostasisParams) {
+			h.MinThreshold = 0.95
+			h.MaxThreshold = 1.1
+		})
+		for i, n := range net.Neurons {
+			n.BaseFiringThreshold = 1.0
+			if i%2 == 0 {
+				setFiringRate(n, net.CycleCount, 50)
+			} else {
+				setFiringRate(n, net.CycleCount, 0)
+			}
+		}
+		for cycle := 0; cycle < 20; cycle++ {
+			net.applyHomeostasis()
+		}
+		for i, n := range net.Neurons {
+			want := common.Threshold(1.1)
+			if i%2 != 0 {
+				want = 0.95
+			}
+			if math.Abs(float64(n.BaseFiringThreshold-want)) > 1e-9 {
+				t.Errorf("neuron %d: BaseFiringThreshold = %f, want it held at %f", n.ID, n.BaseFiringThreshold, want)
+			}
+		}
+	})
+
+	t.Run("a type override disables adaptation", func(t *testing.T) {
+		disabled := false
+		net := newHomeostasisTestNet(t, func(h *config.HomeostasisParams) {
+			h.TypeOverrides = []config.HomeostasisTypeOverride{{Type: "Excitatory", Enabled: &disabled}}
+		})
+		excitatory := firstNeuronOfType(t, net, neuron.Excitatory)
+		inhibitory := firstNeuronOfType(t, net, neuron.Inhibitory)
+		for _, n := range []*neuron.Neuron{excitatory, inhibitory} {
+			n.BaseFiringThreshold = 1.0
+			setFiringRate(n, net.CycleCount, 20)
+		}
+
+		net.applyHomeostasis()
+		if excitatory.BaseFiringThreshold != 1.0 {
+			t.Errorf("Excitatory BaseFiringThreshold = %f, want 1.0 (adaptation disabled)", excitatory.BaseFiringThreshold)
+		}
+		if inhibitory.BaseFiringThreshold <= 1.0 {
+			t.Errorf("Inhibitory BaseFiringThreshold = %f, want it raised", inhibitory.BaseFiringThreshold)
+		}
+	})
+}
+
+// TestRunCycle_HomeostasisFrozenWithoutLearning checks that disabling learning, as
+// observe mode does, also freezes homeostatic threshold adaptation.
+func TestRunCycle_HomeostasisFrozenWithoutLearning(t *testing.T) {
+	net := newHomeostasisTestNet(t, nil)
+	thresholds := make(map[common.NeuronID]common.Threshold)
+	for _, n := range net.Neurons {
+		setFiringRate(n, net.CycleCount, 0) // Silent neurons would have their thresholds lowered.
+		thresholds[n.ID] = n.BaseFiringThreshold
+	}
+
+	net.SetDynamicState(false, false, false)
+	for cycle := 0; cycle < 5; cycle++ {
+		net.RunCycle()
+	}
+	for _, n := range net.Neurons {
+		if n.BaseFiringThreshold != thresholds[n.ID] {
+			t.Errorf("neuron %d: BaseFiringThreshold changed from %f to %f with learning disabled",
+				n.ID, thresholds[n.ID], n.BaseFiringThreshold)
+		}
+	}
+
+	net.SetDynamicState(true, false, false)
+	net.RunCycle()
+	changed := false
+	for _, n := range net.Neurons {
+		if n.BaseFiringThreshold != thresholds[n.ID] {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Error("no BaseFiringThreshold changed with learning enabled; the frozen check above proves nothing")
+	}
+}
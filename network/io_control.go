@@ -24,6 +24,11 @@ func (cn *CrowNet) SetDynamicState(learning, synaptogenesis, chemicalModulation
 	cn.isChemicalModulationEnabled = chemicalModulation
 }
 
+// DynamicState reports which dynamic processes are enabled (see SetDynamicState).
+func (cn *CrowNet) DynamicState() (learning, synaptogenesis, chemicalModulation bool) {
+	return cn.isLearningEnabled, cn.isSynaptogenesisEnabled, cn.isChemicalModulationEnabled
+}
+
 // ResetNetworkStateForNewPattern prepares the network for a new input pattern
 // presentation by resetting transient neuronal states and clearing active signals.
 // Specifically, it:
@@ -272,7 +277,10 @@ func (cn *CrowNet) processFrequencyInputs() {
 		// Should not happen with proper initialization.
 		return
 	}
-	for neuronID, timeLeft := range cn.timeToNextInputFire {
+	// Iterate in ID order: each firing draws from cn.rng, so map order would
+	// change which neuron receives which random value.
+	for _, neuronID := range sortedNeuronIDs(cn.timeToNextInputFire) {
+		timeLeft := cn.timeToNextInputFire[neuronID]
 		newTimeLeft := timeLeft - 1
 		cn.timeToNextInputFire[neuronID] = newTimeLeft
 
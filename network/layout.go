@@ -0,0 +1,87 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+	"crownet/synaptic"
+)
+
+// NeuronSpec describes one neuron of a saved network: everything NewCrowNetFromLayout
+// needs to rebuild it without drawing from the seed.
+type NeuronSpec struct {
+	ID         common.NeuronID
+	Type       neuron.Type
+	Position   common.Point
+	Parameters neuron.Parameters
+}
+
+// Layout returns the spec of every neuron in the network, in ID order, with its
+// current position and intrinsic parameters.
+func (cn *CrowNet) Layout() []NeuronSpec {
+	layout := make([]NeuronSpec, 0, len(cn.Neurons))
+	for _, n := range cn.Neurons {
+		layout = append(layout, NeuronSpec{ID: n.ID, Type: n.Type, Position: n.Position, Parameters: n.Parameters()})
+	}
+	sort.Slice(layout, func(i, j int) bool { return layout[i].ID < layout[j].ID })
+	return layout
+}
+
+// NewCrowNetFromLayout creates a CrowNet whose neurons and synapses are exactly the
+// given ones, instead of generating them from the seed as NewCrowNet does. The
+// configured topology, connectivity rules, neuron counts and parameter jitter are
+// not used; appCfg still provides the simulation parameters and the seed for the
+// network's own random draws (e.g. synaptogenesis). Every synapse in weights must
+// connect neurons present in layout.
+func NewCrowNetFromLayout(appCfg *config.AppConfig, layout []NeuronSpec,
+	weights map[common.NeuronID]synaptic.WeightMap) (*CrowNet, error) {
+	if appCfg == nil {
+		return nil, fmt.Errorf("NewCrowNetFromLayout: appConfig cannot be nil")
+	}
+	if len(layout) == 0 {
+		return nil, fmt.Errorf("NewCrowNetFromLayout: layout has no neurons")
+	}
+	net, err := newCrowNetShell(appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := append([]NeuronSpec(nil), layout...)
+	sort.Slice(specs, func(i, j int) bool { return specs[i].ID < specs[j].ID })
+	for i, spec := range specs {
+		if i > 0 && spec.ID == specs[i-1].ID {
+			return nil, fmt.Errorf("layout contains neuron ID %d more than once", spec.ID)
+		}
+		n := neuron.New(spec.ID, spec.Type, spec.Position, &net.SimParams.SimParams)
+		n.SetParameters(spec.Parameters)
+		net.Neurons = append(net.Neurons, n)
+		switch spec.Type {
+		case neuron.Input:
+			net.InputNeuronIDs = append(net.InputNeuronIDs, spec.ID)
+		case neuron.Output:
+			net.OutputNeuronIDs = append(net.OutputNeuronIDs, spec.ID)
+		}
+	}
+	net.neuronIDCounter = specs[len(specs)-1].ID + 1
+	net.finalizeInitialization()
+
+	var connections []synaptic.Connection
+	for _, fromID := range sortedNeuronIDs(weights) {
+		if _, ok := net.neuronMap[fromID]; !ok {
+			return nil, fmt.Errorf("weights reference unknown presynaptic neuron ID %d", fromID)
+		}
+		for _, toID := range sortedNeuronIDs(weights[fromID]) {
+			if _, ok := net.neuronMap[toID]; !ok {
+				return nil, fmt.Errorf("weights reference unknown postsynaptic neuron ID %d", toID)
+			}
+			connections = append(connections, synaptic.Connection{From: fromID, To: toID})
+		}
+	}
+	net.SynapticWeights.LoadWeights(weights)
+	net.TopologyStats = computeTopologyStats("layout", net.Neurons, connections)
+	net.SpatialGrid.Build(net.Neurons)
+	return net, nil
+}
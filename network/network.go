@@ -12,7 +12,9 @@ import (
 	"crownet/space"
 
 	// "crownet/space/grid" // If grid becomes its own sub-package. For now, space.SpatialGrid
+	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"math/rand"
 	"sort"
@@ -46,6 +48,8 @@ func (cn *CrowNet) isNeuronRecentlyActive(n *neuron.Neuron, coincidenceWindow co
 type CrowNet struct {
 	SynaptogenesisForceCalculator ForceCalculator
 	SynaptogenesisMovementUpdater MovementUpdater
+	TopologyGenerator             TopologyGenerator
+	TopologyStats                 TopologyStats // Connections realised by TopologyGenerator at creation.
 	inputTargetFrequencies        map[common.NeuronID]float64
 	SpatialGrid                   *space.SpatialGrid
 	rng                           *rand.Rand
@@ -54,6 +58,8 @@ type CrowNet struct {
 	InputNeuronIDSet              map[common.NeuronID]struct{}
 	neuronMap                     map[common.NeuronID]*neuron.Neuron
 	timeToNextInputFire           map[common.NeuronID]common.CycleCount
+	homeostasisRules              map[neuron.Type]homeostasisRule
+	firedLastCycle                []common.NeuronID // Neurons that fired in the last completed cycle, in ID order.
 	ActivePulses                  *pulse.PulseList
 	SynapticWeights               *synaptic.NetworkWeights
 	ChemicalEnv                   *neurochemical.Environment
@@ -64,6 +70,7 @@ type CrowNet struct {
 	CycleCount                    common.CycleCount
 	neuronIDCounter               common.NeuronID
 	logger                        Logger // Interface for logging
+	log                           *slog.Logger
 	isLearningEnabled             bool
 	isSynaptogenesisEnabled       bool
 	isChemicalModulationEnabled   bool
@@ -86,6 +93,40 @@ func NewCrowNet(appCfg *config.AppConfig) (*CrowNet, error) {
 	if appCfg == nil {
 		return nil, fmt.Errorf("NewCrowNet: appConfig cannot be nil")
 	}
+	net, err := newCrowNetShell(appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := net.initializeNeurons(appCfg.Cli.TotalNeurons); err != nil {
+		return nil, fmt.Errorf("failed to initialize neurons: %w", err)
+	}
+	// Neurons are in ID order here, so the generator (and the weight draws that follow)
+	// consume the RNG deterministically.
+	connections, err := net.TopologyGenerator.Generate(net.Neurons, net.rng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate topology: %w", err)
+	}
+	connectivity, err := newConnectivityMatrix(appCfg.SimParams.Connectivity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure connectivity rules: %w", err)
+	}
+	connections = connectivity.apply(net.Neurons, connections, net.rng)
+	net.SynapticWeights.InitializeConnections(connections)
+	net.TopologyStats = computeTopologyStats(net.TopologyGenerator.Name(), net.Neurons, connections)
+	if err := net.assignNeuronParameters(); err != nil {
+		return nil, fmt.Errorf("failed to assign neuron parameters: %w", err)
+	}
+	net.finalizeInitialization()
+	net.SpatialGrid.Build(net.Neurons) // Initial build after neurons are positioned
+
+	return net, nil
+}
+
+// newCrowNetShell creates a CrowNet with its spatial grid, empty synaptic weights,
+// topology generator and homeostasis rules configured, but without neurons.
+// NewCrowNet and NewCrowNetFromLayout populate it.
+func newCrowNetShell(appCfg *config.AppConfig) (*CrowNet, error) {
 	// Store the whole AppConfig, not just SimParams, if CLI parameters are needed by CrowNet methods.
 	// simParams := &appCfg.SimParams // Use a pointer to SimParams
 
@@ -109,6 +150,11 @@ func NewCrowNet(appCfg *config.AppConfig) (*CrowNet, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create spatial grid: %w", err)
 	}
+	boundary, err := space.NewBoundary(appCfg.SimParams.General.BoundaryMode, appCfg.SimParams.General.SpaceMaxDimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure space boundary: %w", err)
+	}
+	spatialGridInstance.SetBoundary(boundary)
 
 	net := &CrowNet{
 		SimParams:         appCfg, // Store entire AppConfig
@@ -138,21 +184,30 @@ func NewCrowNet(appCfg *config.AppConfig) (*CrowNet, error) {
 		return nil, fmt.Errorf("failed to create synaptic weights: %w", err)
 	}
 	net.SynapticWeights = synapticWeightsInstance
-
-	if err := net.initializeNeurons(appCfg.Cli.TotalNeurons); err != nil {
-		return nil, fmt.Errorf("failed to initialize neurons: %w", err)
+	net.TopologyGenerator, err = NewTopologyGenerator(&appCfg.SimParams.Topology, boundary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure topology: %w", err)
 	}
-	allNeuronIDs := make([]common.NeuronID, len(net.Neurons))
-	for i, n := range net.Neurons {
-		allNeuronIDs[i] = n.ID
+	net.homeostasisRules, err = newHomeostasisRules(&appCfg.SimParams.Homeostasis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure homeostasis: %w", err)
 	}
-	net.SynapticWeights.InitializeAllToAllWeights(allNeuronIDs)
-	net.finalizeInitialization()
-	net.SpatialGrid.Build(net.Neurons) // Initial build after neurons are positioned
 
 	return net, nil
 }
 
+// sortedNeuronIDs returns the keys of a neuron map in ascending order.
+// Iterating in this order keeps RNG draws and floating-point accumulation
+// identical between runs that share a seed.
+func sortedNeuronIDs[V any](m map[common.NeuronID]V) []common.NeuronID {
+	ids := make([]common.NeuronID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 // getNextNeuronID returns the next available unique ID for a new neuron and increments the internal counter.
 func (cn *CrowNet) getNextNeuronID() common.NeuronID {
 	id := cn.neuronIDCounter
@@ -269,13 +324,31 @@ func (cn *CrowNet) finalizeInitialization() {
 }
 
 // _updateAllNeuronStates handles the decay of accumulated potential and state advancement for all neurons.
+// Neurons whose state advance registers a firing are recorded for FiredLastCycle.
 func (cn *CrowNet) _updateAllNeuronStates() {
+	cn.firedLastCycle = cn.firedLastCycle[:0]
 	for _, n := range cn.Neurons { // Or iterate cn.neuronMap
 		n.DecayPotential(&cn.SimParams.SimParams)
-		n.AdvanceState(cn.CycleCount, &cn.SimParams.SimParams)
+		if n.AdvanceState(cn.CycleCount, &cn.SimParams.SimParams) {
+			cn.firedLastCycle = append(cn.firedLastCycle, n.ID)
+		}
 	}
 }
 
+// FiredLastCycle returns the IDs, in ascending order, of the neurons that fired in
+// the most recently completed cycle (CycleCount-1). A firing is the cycle recorded
+// in the neuron's LastFiredCycle and FiringHistory. The slice is reused by the next
+// call to RunCycle, so callers that keep it must copy it.
+func (cn *CrowNet) FiredLastCycle() []common.NeuronID {
+	return cn.firedLastCycle
+}
+
+// GetNeuron returns the neuron with the given ID, or false if there is none.
+func (cn *CrowNet) GetNeuron(id common.NeuronID) (*neuron.Neuron, bool) {
+	n, ok := cn.neuronMap[id]
+	return n, ok
+}
+
 // _applyChemicalModulationEffects updates chemical levels and applies their effects to neurons
 // if chemical modulation is enabled. Otherwise, it resets modulation factors and neuron thresholds.
 func (cn *CrowNet) _applyChemicalModulationEffects() {
@@ -284,6 +357,7 @@ func (cn *CrowNet) _applyChemicalModulationEffects() {
 		cn.ChemicalEnv.UpdateLevels(cn.neuronMap, cn.ActivePulses.GetAll(),
 			simParamsPtr.Neurochemical.CortisolGlandPosition, simParamsPtr)
 		cn.ChemicalEnv.ApplyEffectsToNeurons(cn.neuronMap, simParamsPtr)
+		cn.logChemicals()
 	} else {
 		cn.ChemicalEnv.LearningRateModulationFactor = 1.0
 		cn.ChemicalEnv.SynaptogenesisModulationFactor = 1.0
@@ -308,6 +382,12 @@ func (cn *CrowNet) RunCycle() {
 	cn.processFrequencyInputs()
 	cn._updateAllNeuronStates()
 	cn.processActivePulses()
+	if cn.isLearningEnabled {
+		// Homeostasis is a form of plasticity, so it is frozen together with learning
+		// (e.g. in observe mode). It runs before chemical modulation, which builds the
+		// current threshold on top of the adapted base threshold.
+		cn.applyHomeostasis()
+	}
 	cn._applyChemicalModulationEffects()
 
 	if cn.isLearningEnabled {
@@ -316,13 +396,10 @@ func (cn *CrowNet) RunCycle() {
 	if cn.isSynaptogenesisEnabled {
 		cn.applySynaptogenesis()
 		// Rebuild spatial grid if neurons moved.
-		// This should use cn.neuronMap values or cn.Neurons if still maintained.
-		// Assuming cn.Neurons is up-to-date or cn.neuronMap is used.
-		var neuronsForGrid []*neuron.Neuron
-		for _, n := range cn.neuronMap {
-			neuronsForGrid = append(neuronsForGrid, n)
-		}
-		cn.SpatialGrid.Build(neuronsForGrid)
+		// cn.Neurons is used (rather than cn.neuronMap) so that the order of neurons
+		// inside each grid cell, and thus the order in which pulses reach them, is
+		// the same on every run.
+		cn.SpatialGrid.Build(cn.Neurons)
 	}
 	cn.CycleCount++
 }
@@ -370,17 +447,22 @@ func (cn *CrowNet) applyHebbianLearning() {
 
 	coincidenceWindow := common.CycleCount(simParamsPtr.Learning.HebbianCoincidenceWindow)
 
-	for _, preSynapticNeuron := range cn.neuronMap { // Iterate map
+	// cn.Neurons is iterated in ID order; map order would make same-seed runs diverge.
+	for _, preSynapticNeuron := range cn.Neurons {
 		isPreActive := cn.isNeuronRecentlyActive(preSynapticNeuron, coincidenceWindow)
 		if !isPreActive {
 			continue
 		}
 		preActivityValue := 1.0
 
-		for _, postSynapticNeuron := range cn.neuronMap { // Iterate map
+		for _, postSynapticNeuron := range cn.Neurons {
 			if preSynapticNeuron.ID == postSynapticNeuron.ID {
 				continue
 			}
+			// Only existing synapses learn; sparse topologies must not be filled in by Hebbian updates.
+			if !cn.SynapticWeights.HasConnection(preSynapticNeuron.ID, postSynapticNeuron.ID) {
+				continue
+			}
 			isPostActive := cn.isNeuronRecentlyActive(postSynapticNeuron, coincidenceWindow)
 			if isPostActive {
 				postActivityValue := 1.0
@@ -418,6 +500,32 @@ func (cn *CrowNet) SetLogger(logger Logger) {
 	cn.logger = logger
 }
 
+// SetLog assigns the structured logger that receives the network's debug records,
+// such as the neurochemical levels of each cycle. A nil logger disables them.
+func (cn *CrowNet) SetLog(logger *slog.Logger) {
+	cn.log = logger
+}
+
+// logChemicals records the neurochemical levels just updated, at debug level or, if
+// DebugChem is set, at info level.
+func (cn *CrowNet) logChemicals() {
+	level := slog.LevelDebug
+	if cn.SimParams != nil && cn.SimParams.Cli.DebugChem {
+		level = slog.LevelInfo
+	}
+	ctx := context.Background()
+	if cn.log == nil || !cn.log.Enabled(ctx, level) {
+		return
+	}
+	env := cn.ChemicalEnv
+	cn.log.Log(ctx, level, "Neurochemicals updated",
+		"cycle", int(cn.CycleCount),
+		"cortisol", float64(env.CortisolLevel),
+		"dopamine", float64(env.DopamineLevel),
+		"lr_mod", float64(env.LearningRateModulationFactor),
+		"syn_mod", float64(env.SynaptogenesisModulationFactor))
+}
+
 // LogSnapshot logs the current state of the network.
 // This method is intended to be called periodically by the simulation runner.
 // (This is a basic LogSnapshot, actual implementation might be in a storage specific logger)
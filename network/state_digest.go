@@ -0,0 +1,118 @@
+package network
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+// StateDigest summarises the simulation state at a point in time as one hash
+// per component. Two runs with the same configuration and seed must produce
+// identical digests every cycle; a mismatch pinpoints the component that diverged.
+type StateDigest struct {
+	Neurons   uint64 // Positions, velocities, potentials, states and intrinsic parameters of all neurons.
+	Weights   uint64 // All synaptic weights.
+	Pulses    uint64 // All active pulses.
+	Chemicals uint64 // Neurochemical levels and modulation factors.
+}
+
+// DigestComponents lists the component names reported by StateDigest.Diff, in comparison order.
+var DigestComponents = []string{"neurons", "weights", "pulses", "chemicals"}
+
+// Diff returns the name of the first component that differs between d and other,
+// or an empty string if both digests are identical.
+func (d StateDigest) Diff(other StateDigest) string {
+	switch {
+	case d.Neurons != other.Neurons:
+		return DigestComponents[0]
+	case d.Weights != other.Weights:
+		return DigestComponents[1]
+	case d.Pulses != other.Pulses:
+		return DigestComponents[2]
+	case d.Chemicals != other.Chemicals:
+		return DigestComponents[3]
+	}
+	return ""
+}
+
+// Digest hashes the current state of the network. Every collection is visited
+// in a fixed order (neurons and weights by ID, pulses in list order) so that the
+// digest depends only on the state itself and not on map iteration order.
+func (cn *CrowNet) Digest() StateDigest {
+	var d StateDigest
+
+	h := fnv.New64a()
+	for _, n := range cn.Neurons {
+		writeInt(h, int64(n.ID))
+		writeInt(h, int64(n.Type))
+		writeInt(h, int64(n.CurrentState))
+		writeFloat(h, float64(n.AccumulatedPotential))
+		writeFloat(h, float64(n.CurrentFiringThreshold))
+		writeInt(h, int64(n.LastFiredCycle))
+		writeFloat(h, float64(n.BaseFiringThreshold))
+		writeFloat(h, float64(n.DecayRate))
+		writeInt(h, int64(n.AbsoluteRefractoryCycles))
+		writeInt(h, int64(n.RelativeRefractoryCycles))
+		for i := range n.Position {
+			writeFloat(h, float64(n.Position[i]))
+		}
+		for i := range n.Velocity {
+			writeFloat(h, float64(n.Velocity[i]))
+		}
+	}
+	d.Neurons = h.Sum64()
+
+	h = fnv.New64a()
+	if cn.SynapticWeights != nil {
+		allWeights := cn.SynapticWeights.GetAllWeights()
+		for _, fromID := range sortedNeuronIDs(allWeights) {
+			toMap := allWeights[fromID]
+			for _, toID := range sortedNeuronIDs(toMap) {
+				writeInt(h, int64(fromID))
+				writeInt(h, int64(toID))
+				writeFloat(h, float64(toMap[toID]))
+			}
+		}
+	}
+	d.Weights = h.Sum64()
+
+	h = fnv.New64a()
+	if cn.ActivePulses != nil {
+		for _, p := range cn.ActivePulses.GetAll() {
+			writeInt(h, int64(p.EmittingNeuronID))
+			writeInt(h, int64(p.CreationCycle))
+			writeFloat(h, p.CurrentDistance)
+			writeFloat(h, float64(p.BaseSignalValue))
+			for i := range p.Origin {
+				writeFloat(h, float64(p.Origin[i]))
+			}
+		}
+	}
+	d.Pulses = h.Sum64()
+
+	h = fnv.New64a()
+	if cn.ChemicalEnv != nil {
+		writeFloat(h, float64(cn.ChemicalEnv.CortisolLevel))
+		writeFloat(h, float64(cn.ChemicalEnv.DopamineLevel))
+		writeFloat(h, float64(cn.ChemicalEnv.LearningRateModulationFactor))
+		writeFloat(h, float64(cn.ChemicalEnv.SynaptogenesisModulationFactor))
+	}
+	d.Chemicals = h.Sum64()
+
+	return d
+}
+
+// writeInt feeds v into h as 8 little-endian bytes.
+func writeInt(h hash.Hash64, v int64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(v))
+	h.Write(buf[:]) //nolint:errcheck // hash.Hash.Write never returns an error.
+}
+
+// writeFloat feeds the exact bit pattern of v into h.
+func writeFloat(h hash.Hash64, v float64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	h.Write(buf[:]) //nolint:errcheck // hash.Hash.Write never returns an error.
+}
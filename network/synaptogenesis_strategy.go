@@ -56,7 +56,9 @@ func (dfc *DefaultForceCalculator) CalculateForces(
 		return forces // Return empty forces if params are missing
 	}
 
-	for id, n := range neurons {
+	boundary := space.BoundaryFromParams(&simParams.General)
+	for _, id := range sortedNeuronIDs(neurons) {
+		n := neurons[id]
 		netForce := make(common.Point, common.PointDimension) // Initialize net force for neuron n
 
 		// 1. Repulsive forces from all nearby neurons (within SynaptogenesisInfluenceRadius)
@@ -69,7 +71,9 @@ func (dfc *DefaultForceCalculator) CalculateForces(
 			if n.ID == otherNeuron.ID {
 				continue // Skip self
 			}
-			distance := space.EuclideanDistance(n.Position, otherNeuron.Position)
+			// Under periodic boundaries the nearest image of otherNeuron may lie across the seam.
+			awayFromOther := boundary.Displacement(otherNeuron.Position, n.Position)
+			distance := space.Magnitude(common.Point(awayFromOther))
 			// Avoid division by zero if distance is very small (though grid cell size should help)
 			if distance < 1e-6 { // epsilon distance
 				distance = 1e-6
@@ -80,7 +84,7 @@ func (dfc *DefaultForceCalculator) CalculateForces(
 			// The direction is away from otherNeuron.
 			repulsionStrength := float64(simParams.Synaptogenesis.RepulsionForceFactor) / (distance * distance)
 			for i := 0; i < common.PointDimension; i++ {
-				directionComponent := (n.Position[i] - otherNeuron.Position[i]) / common.Coordinate(distance)
+				directionComponent := awayFromOther[i] / common.Coordinate(distance)
 				netForce[i] += directionComponent * common.Coordinate(repulsionStrength)
 			}
 		}
@@ -127,7 +131,9 @@ func (dmu *DefaultMovementUpdater) ApplyMovements(
 		return // Essential parameters missing
 	}
 
-	for id, n := range neurons {
+	boundary := space.BoundaryFromParams(&simParams.General)
+	for _, id := range sortedNeuronIDs(neurons) {
+		n := neurons[id]
 		force, ok := forces[id]
 		if !ok || force == nil {
 			continue // No force calculated for this neuron
@@ -169,17 +175,12 @@ func (dmu *DefaultMovementUpdater) ApplyMovements(
 			newPosition[i] = n.Position[i] + displacement[i]
 		}
 
-		// Clamp position to simulation boundaries (e.g., a sphere or cube)
-		// Assuming SimParams.General.SpaceMaxDimension defines a cubic boundary centered at origin
-		boundary := simParams.General.SpaceMaxDimension
-		for i := 0; i < common.PointDimension; i++ {
-			if newPosition[i] > common.Coordinate(boundary) {
-				newPosition[i] = common.Coordinate(boundary)
-			} else if newPosition[i] < common.Coordinate(-boundary) {
-				newPosition[i] = common.Coordinate(-boundary)
-			}
-		}
+		// Keep the neuron inside the simulation space according to the configured
+		// boundary mode (clamp, reflect or periodic). Velocity records this cycle's
+		// displacement for logging; it does not carry over into the next cycle.
+		newPosition, newVelocity := boundary.Apply(newPosition, common.Vector(displacement))
 		n.Position = newPosition
+		n.Velocity = common.Point(newVelocity)
 	}
 }
 
@@ -0,0 +1,251 @@
+// Package network (specifically this file for topology generators) provides
+// strategies for choosing which synapses exist when a network is created.
+package network
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+	"crownet/space"
+	"crownet/synaptic"
+)
+
+// TopologyGenerator defines an interface for generating the initial set of synapses.
+// Implementations must draw all randomness from rng and visit neurons in slice
+// order so that a given seed always produces the same connections.
+type TopologyGenerator interface {
+	Name() string
+	Generate(neurons []*neuron.Neuron, rng *rand.Rand) ([]synaptic.Connection, error)
+}
+
+// TopologyStats summarises the connections realised by a TopologyGenerator.
+type TopologyStats struct {
+	Generator    string  // Name of the generator that produced the connections.
+	Connections  int     // Number of directed synapses created.
+	MinInDegree  int     // Smallest number of presynaptic partners of any neuron.
+	MaxInDegree  int     // Largest number of presynaptic partners of any neuron.
+	MeanInDegree float64 // Average number of presynaptic partners per neuron.
+}
+
+// NewTopologyGenerator returns the generator selected in params. The boundary is
+// used by the distance-dependent generator so that distances respect periodic space.
+func NewTopologyGenerator(params *config.TopologyParams, boundary space.Boundary) (TopologyGenerator, error) {
+	switch params.Generator {
+	case "", config.TopologyAllToAll:
+		return &AllToAllTopology{}, nil
+	case config.TopologyErdosRenyi:
+		return &ErdosRenyiTopology{Probability: params.ConnectionProbability}, nil
+	case config.TopologyDistance:
+		return &DistanceTopology{
+			MaxProbability: params.DistanceMaxProbability,
+			LengthScale:    params.DistanceLengthScale,
+			Boundary:       boundary,
+		}, nil
+	case config.TopologyWattsStrogatz:
+		return &WattsStrogatzTopology{
+			Neighbors:         params.SmallWorldNeighbors,
+			RewireProbability: params.SmallWorldRewireProbability,
+		}, nil
+	case config.TopologyFixedInDegree:
+		return &FixedInDegreeTopology{InDegree: params.InDegree}, nil
+	default:
+		return nil, fmt.Errorf("unknown topology generator '%s'", params.Generator)
+	}
+}
+
+// computeTopologyStats counts connections and in-degrees for the given neurons.
+func computeTopologyStats(name string, neurons []*neuron.Neuron, connections []synaptic.Connection) TopologyStats {
+	stats := TopologyStats{Generator: name, Connections: len(connections)}
+	if len(neurons) == 0 {
+		return stats
+	}
+	inDegree := make(map[common.NeuronID]int, len(neurons))
+	for _, c := range connections {
+		inDegree[c.To]++
+	}
+	stats.MinInDegree = math.MaxInt
+	for _, n := range neurons {
+		d := inDegree[n.ID]
+		if d < stats.MinInDegree {
+			stats.MinInDegree = d
+		}
+		if d > stats.MaxInDegree {
+			stats.MaxInDegree = d
+		}
+	}
+	stats.MeanInDegree = float64(len(connections)) / float64(len(neurons))
+	return stats
+}
+
+// AllToAllTopology connects every neuron to every other neuron.
+type AllToAllTopology struct{}
+
+// Name returns the configuration name of the generator.
+func (t *AllToAllTopology) Name() string { return config.TopologyAllToAll }
+
+// Generate returns every ordered pair of distinct neurons. No randomness is used.
+func (t *AllToAllTopology) Generate(neurons []*neuron.Neuron, _ *rand.Rand) ([]synaptic.Connection, error) {
+	connections := make([]synaptic.Connection, 0, len(neurons)*(len(neurons)-1))
+	for _, pre := range neurons {
+		for _, post := range neurons {
+			if pre.ID != post.ID {
+				connections = append(connections, synaptic.Connection{From: pre.ID, To: post.ID})
+			}
+		}
+	}
+	return connections, nil
+}
+
+// ErdosRenyiTopology connects each ordered pair of distinct neurons independently
+// with a fixed probability.
+type ErdosRenyiTopology struct {
+	Probability float64
+}
+
+// Name returns the configuration name of the generator.
+func (t *ErdosRenyiTopology) Name() string { return config.TopologyErdosRenyi }
+
+// Generate draws one Bernoulli trial per ordered pair.
+func (t *ErdosRenyiTopology) Generate(neurons []*neuron.Neuron, rng *rand.Rand) ([]synaptic.Connection, error) {
+	var connections []synaptic.Connection
+	for _, pre := range neurons {
+		for _, post := range neurons {
+			if pre.ID != post.ID && rng.Float64() < t.Probability {
+				connections = append(connections, synaptic.Connection{From: pre.ID, To: post.ID})
+			}
+		}
+	}
+	return connections, nil
+}
+
+// DistanceTopology connects each ordered pair with probability
+// MaxProbability * exp(-d / LengthScale), where d is the distance between the
+// two neurons' positions in the 16D space.
+type DistanceTopology struct {
+	MaxProbability float64
+	LengthScale    float64
+	Boundary       space.Boundary
+}
+
+// Name returns the configuration name of the generator.
+func (t *DistanceTopology) Name() string { return config.TopologyDistance }
+
+// Generate draws one Bernoulli trial per ordered pair using the distance-dependent probability.
+func (t *DistanceTopology) Generate(neurons []*neuron.Neuron, rng *rand.Rand) ([]synaptic.Connection, error) {
+	if t.LengthScale <= 0 {
+		return nil, fmt.Errorf("distance topology: length scale must be positive, got %f", t.LengthScale)
+	}
+	var connections []synaptic.Connection
+	for _, pre := range neurons {
+		for _, post := range neurons {
+			if pre.ID == post.ID {
+				continue
+			}
+			d := t.Boundary.Distance(pre.Position, post.Position)
+			if rng.Float64() < t.MaxProbability*math.Exp(-d/t.LengthScale) {
+				connections = append(connections, synaptic.Connection{From: pre.ID, To: post.ID})
+			}
+		}
+	}
+	return connections, nil
+}
+
+// WattsStrogatzTopology builds a small-world network. Neurons are placed on a ring
+// in ID order and each is linked to its Neighbors/2 nearest successors; every such
+// lattice edge is then rewired with RewireProbability to a uniformly chosen neuron.
+// Each resulting edge is a reciprocal pair of synapses, so every neuron starts with
+// in-degree Neighbors before rewiring.
+type WattsStrogatzTopology struct {
+	Neighbors         int
+	RewireProbability float64
+}
+
+// Name returns the configuration name of the generator.
+func (t *WattsStrogatzTopology) Name() string { return config.TopologyWattsStrogatz }
+
+// Generate builds the ring lattice and rewires it.
+func (t *WattsStrogatzTopology) Generate(neurons []*neuron.Neuron, rng *rand.Rand) ([]synaptic.Connection, error) {
+	n := len(neurons)
+	if t.Neighbors <= 0 || t.Neighbors%2 != 0 || t.Neighbors >= n {
+		return nil, fmt.Errorf("watts-strogatz topology: neighbors must be a positive even number below %d, got %d",
+			n, t.Neighbors)
+	}
+
+	// linked[i][j] marks an undirected edge between ring positions i and j.
+	linked := make([]map[int]bool, n)
+	for i := range linked {
+		linked[i] = make(map[int]bool, t.Neighbors)
+	}
+	type edge struct{ a, b int }
+	edges := make([]edge, 0, n*t.Neighbors/2)
+	for i := 0; i < n; i++ {
+		for j := 1; j <= t.Neighbors/2; j++ {
+			k := (i + j) % n
+			linked[i][k], linked[k][i] = true, true
+			edges = append(edges, edge{i, k})
+		}
+	}
+
+	for idx, e := range edges {
+		if rng.Float64() >= t.RewireProbability {
+			continue
+		}
+		if len(linked[e.a]) >= n-1 {
+			continue // Already linked to every other neuron; nowhere to rewire to.
+		}
+		target := rng.Intn(n)
+		for target == e.a || linked[e.a][target] {
+			target = rng.Intn(n)
+		}
+		delete(linked[e.a], e.b)
+		delete(linked[e.b], e.a)
+		linked[e.a][target], linked[target][e.a] = true, true
+		edges[idx] = edge{e.a, target}
+	}
+
+	connections := make([]synaptic.Connection, 0, 2*len(edges))
+	for _, e := range edges {
+		connections = append(connections,
+			synaptic.Connection{From: neurons[e.a].ID, To: neurons[e.b].ID},
+			synaptic.Connection{From: neurons[e.b].ID, To: neurons[e.a].ID},
+		)
+	}
+	return connections, nil
+}
+
+// FixedInDegreeTopology gives every neuron exactly InDegree presynaptic partners,
+// chosen uniformly without replacement from all other neurons.
+type FixedInDegreeTopology struct {
+	InDegree int
+}
+
+// Name returns the configuration name of the generator.
+func (t *FixedInDegreeTopology) Name() string { return config.TopologyFixedInDegree }
+
+// Generate samples the presynaptic partners of each neuron with a partial Fisher–Yates shuffle.
+func (t *FixedInDegreeTopology) Generate(neurons []*neuron.Neuron, rng *rand.Rand) ([]synaptic.Connection, error) {
+	n := len(neurons)
+	if t.InDegree <= 0 || t.InDegree >= n {
+		return nil, fmt.Errorf("fixed in-degree topology: in-degree must be between 1 and %d, got %d", n-1, t.InDegree)
+	}
+	connections := make([]synaptic.Connection, 0, n*t.InDegree)
+	candidates := make([]int, 0, n-1)
+	for postIdx, post := range neurons {
+		candidates = candidates[:0]
+		for i := 0; i < n; i++ {
+			if i != postIdx {
+				candidates = append(candidates, i)
+			}
+		}
+		for i := 0; i < t.InDegree; i++ {
+			j := i + rng.Intn(len(candidates)-i)
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+			connections = append(connections, synaptic.Connection{From: neurons[candidates[i]].ID, To: post.ID})
+		}
+	}
+	return connections, nil
+}
@@ -0,0 +1,223 @@
+package network
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+	"crownet/space"
+	"crownet/synaptic"
+)
+
+// newTopologyTestNeurons creates n excitatory neurons with IDs 0..n-1, the i-th
+// placed at position(i) (the origin if position is nil).
+func newTopologyTestNeurons(n int, position func(i int) common.Point) []*neuron.Neuron {
+	simParams := config.DefaultSimulationParameters()
+	neurons := make([]*neuron.Neuron, n)
+	for i := range neurons {
+		var pos common.Point
+		if position != nil {
+			pos = position(i)
+		}
+		neurons[i] = neuron.New(common.NeuronID(i), neuron.Excitatory, pos, &simParams)
+	}
+	return neurons
+}
+
+// checkSimpleGraph fails the test if connections contain a self-loop or the same
+// directed pair twice.
+func checkSimpleGraph(t *testing.T, connections []synaptic.Connection) {
+	t.Helper()
+	seen := make(map[[2]common.NeuronID]bool, len(connections))
+	for _, c := range connections {
+		if c.From == c.To {
+			t.Errorf("self-loop on neuron %d", c.From)
+		}
+		pair := [2]common.NeuronID{c.From, c.To}
+		if seen[pair] {
+			t.Errorf("duplicate connection %d -> %d", c.From, c.To)
+		}
+		seen[pair] = true
+	}
+}
+
+func TestAllToAllTopology(t *testing.T) {
+	neurons := newTopologyTestNeurons(10, nil)
+	connections, err := (&AllToAllTopology{}).Generate(neurons, nil)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(connections) != 10*9 {
+		t.Errorf("Generate() created %d connections, want %d", len(connections), 10*9)
+	}
+	checkSimpleGraph(t, connections)
+}
+
+func TestErdosRenyiTopology(t *testing.T) {
+	const n, p = 100, 0.1
+	neurons := newTopologyTestNeurons(n, nil)
+	connections, err := (&ErdosRenyiTopology{Probability: p}).Generate(neurons, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// The count is binomial with mean p*N*(N-1) = 990 and standard deviation ~30.
+	want := p * n * (n - 1)
+	if got := float64(len(connections)); math.Abs(got-want) > 0.15*want {
+		t.Errorf("Generate() created %.0f connections, want %.0f ± 15%%", got, want)
+	}
+	checkSimpleGraph(t, connections)
+}
+
+func TestFixedInDegreeTopology(t *testing.T) {
+	const n, inDegree = 30, 7
+	neurons := newTopologyTestNeurons(n, nil)
+	connections, err := (&FixedInDegreeTopology{InDegree: inDegree}).Generate(neurons, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	checkSimpleGraph(t, connections)
+	stats := computeTopologyStats(config.TopologyFixedInDegree, neurons, connections)
+	if stats.Connections != n*inDegree || stats.MinInDegree != inDegree || stats.MaxInDegree != inDegree {
+		t.Errorf("stats = %+v, want %d connections and every in-degree %d", stats, n*inDegree, inDegree)
+	}
+
+	if _, err := (&FixedInDegreeTopology{InDegree: n}).Generate(neurons, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("Generate() with in-degree %d for %d neurons: expected an error", n, n)
+	}
+}
+
+func TestWattsStrogatzTopology(t *testing.T) {
+	const n, neighbors = 50, 4
+	neurons := newTopologyTestNeurons(n, nil)
+	for _, rewire := range []float64{0, 0.3, 1} {
+		ws := &WattsStrogatzTopology{Neighbors: neighbors, RewireProbability: rewire}
+		connections, err := ws.Generate(neurons, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatalf("Generate() with rewire probability %g: error = %v", rewire, err)
+		}
+		// Rewiring moves edges but never adds or removes them.
+		if len(connections) != n*neighbors {
+			t.Errorf("rewire probability %g: %d connections, want %d", rewire, len(connections), n*neighbors)
+		}
+		checkSimpleGraph(t, connections)
+		if rewire == 0 {
+			stats := computeTopologyStats(ws.Name(), neurons, connections)
+			if stats.MinInDegree != neighbors || stats.MaxInDegree != neighbors {
+				t.Errorf("ring lattice stats = %+v, want every in-degree %d", stats, neighbors)
+			}
+		}
+	}
+
+	if _, err := (&WattsStrogatzTopology{Neighbors: 3}).Generate(neurons, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("Generate() with an odd number of neighbors: expected an error")
+	}
+}
+
+func TestDistanceTopology_ProbabilityDecreasesWithDistance(t *testing.T) {
+	// Neurons on a line, 0.5 apart, so pair distances range from 0.5 to 29.5.
+	neurons := newTopologyTestNeurons(60, func(i int) common.Point {
+		var p common.Point
+		p[0] = common.Coordinate(float64(i) * 0.5)
+		return p
+	})
+	boundary, err := space.NewBoundary(config.BoundaryClamp, 100)
+	if err != nil {
+		t.Fatalf("NewBoundary() error = %v", err)
+	}
+	gen := &DistanceTopology{MaxProbability: 0.8, LengthScale: 2, Boundary: boundary}
+	connections, err := gen.Generate(neurons, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	checkSimpleGraph(t, connections)
+
+	// Fraction of ordered pairs connected within each distance band.
+	bands := []float64{0, 2, 4, 8, math.Inf(1)}
+	pairs := make([]int, len(bands)-1)
+	connected := make([]int, len(bands)-1)
+	band := func(d float64) int {
+		for i := 1; i < len(bands); i++ {
+			if d < bands[i] {
+				return i - 1
+			}
+		}
+		return len(bands) - 2
+	}
+	for _, pre := range neurons {
+		for _, post := range neurons {
+			if pre.ID != post.ID {
+				pairs[band(boundary.Distance(pre.Position, post.Position))]++
+			}
+		}
+	}
+	for _, c := range connections {
+		connected[band(boundary.Distance(neurons[c.From].Position, neurons[c.To].Position))]++
+	}
+	previous := math.Inf(1)
+	for i := range pairs {
+		fraction := float64(connected[i]) / float64(pairs[i])
+		if fraction >= previous {
+			t.Errorf("distance band [%g, %g): connected fraction %f, want it below the closer band's %f",
+				bands[i], bands[i+1], fraction, previous)
+		}
+		previous = fraction
+	}
+}
+
+func TestTopologyGenerators_SameSeedSameConnections(t *testing.T) {
+	neurons := newTopologyTestNeurons(40, func(i int) common.Point {
+		var p common.Point
+		p[i%common.PointDimension] = common.Coordinate(i % 7)
+		return p
+	})
+	boundary, err := space.NewBoundary(config.BoundaryClamp, 10)
+	if err != nil {
+		t.Fatalf("NewBoundary() error = %v", err)
+	}
+	params := config.DefaultSimulationParameters().Topology
+	params.ConnectionProbability = 0.2
+	params.SmallWorldNeighbors = 6
+	params.SmallWorldRewireProbability = 0.3
+	params.InDegree = 5
+
+	for _, name := range []string{config.TopologyErdosRenyi, config.TopologyDistance,
+		config.TopologyWattsStrogatz, config.TopologyFixedInDegree} {
+		t.Run(name, func(t *testing.T) {
+			params.Generator = name
+			gen, err := NewTopologyGenerator(&params, boundary)
+			if err != nil {
+				t.Fatalf("NewTopologyGenerator() error = %v", err)
+			}
+			first, err := gen.Generate(neurons, rand.New(rand.NewSource(42)))
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			second, _ := gen.Generate(neurons, rand.New(rand.NewSource(42)))
+			if !reflect.DeepEqual(first, second) {
+				t.Error("Generate() with the same seed produced different connection lists")
+			}
+			other, _ := gen.Generate(neurons, rand.New(rand.NewSource(43)))
+			if reflect.DeepEqual(first, other) {
+				t.Error("Generate() with a different seed produced the same connection list")
+			}
+		})
+	}
+}
+
+func TestComputeTopologyStats(t *testing.T) {
+	neurons := newTopologyTestNeurons(4, nil)
+	connections := []synaptic.Connection{{From: 0, To: 1}, {From: 2, To: 1}, {From: 3, To: 1}, {From: 1, To: 2}}
+	stats := computeTopologyStats("test", neurons, connections)
+	want := TopologyStats{Generator: "test", Connections: 4, MinInDegree: 0, MaxInDegree: 3, MeanInDegree: 1}
+	if stats != want {
+		t.Errorf("computeTopologyStats() = %+v, want %+v", stats, want)
+	}
+
+	if stats := computeTopologyStats("empty", nil, nil); stats != (TopologyStats{Generator: "empty"}) {
+		t.Errorf("computeTopologyStats() with no neurons = %+v, want only the generator name", stats)
+	}
+}
@@ -101,7 +101,7 @@ func calculateCortisolStimulation(activePulses []*pulse.Pulse,
 		// For now, let's count pulses whose origin is near the gland.
 		// This is still not quite right.
 		// A better simplified model: if gland is within max pulse radius and pulse is active.
-		distToGland := space.EuclideanDistance(p.Origin, cortisolGlandPosition)
+		distToGland := space.BoundaryFromParams(&simParams.General).Distance(p.Origin, cortisolGlandPosition)
 		if distToGland < p.MaxRadius { // If gland is within potential reach of this pulse
 			// This is still a placeholder. A proper model would check if the pulse's
 			// current expanding shell intersects the gland's sensitive volume.
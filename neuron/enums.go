@@ -1,5 +1,10 @@
 package neuron
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Type defines the functional role of a neuron within the network.
 type Type int
 
@@ -34,6 +39,16 @@ func (nt Type) String() string {
 	}
 }
 
+// ParseType returns the Type whose String() matches name, ignoring case.
+func ParseType(name string) (Type, error) {
+	for _, t := range []Type{Excitatory, Inhibitory, Dopaminergic, Input, Output} {
+		if strings.EqualFold(name, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown neuron type '%s'", name)
+}
+
 // State defines the operational condition of a neuron at a given time.
 type State int
 
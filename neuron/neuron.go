@@ -24,9 +24,41 @@ type Neuron struct {
 	LastMovedCycle         common.CycleCount    // Simulation cycle in which the neuron last moved.
 	Velocity               common.Point         // Current velocity vector of the neuron (for synaptogenesis).
 	FiringHistory          []common.CycleCount  // Records recent firing cycles for frequency calculation.
+	DecayRate              common.Rate          // Fraction of accumulated potential lost per cycle.
+	AbsoluteRefractoryCycles common.CycleCount  // Cycles this neuron cannot fire after firing.
+	RelativeRefractoryCycles common.CycleCount  // Cycles this neuron has an elevated threshold after firing.
 	SimParams              *config.SimulationParameters // Reference to global simulation parameters.
 }
 
+// Parameters holds the intrinsic parameters that may differ from neuron to neuron.
+// It is the unit persisted alongside the synaptic weights.
+type Parameters struct {
+	BaseFiringThreshold      common.Threshold  `json:"base_firing_threshold"`
+	DecayRate                common.Rate       `json:"decay_rate"`
+	AbsoluteRefractoryCycles common.CycleCount `json:"absolute_refractory_cycles"`
+	RelativeRefractoryCycles common.CycleCount `json:"relative_refractory_cycles"`
+}
+
+// Parameters returns the neuron's current intrinsic parameters.
+func (n *Neuron) Parameters() Parameters {
+	return Parameters{
+		BaseFiringThreshold:      n.BaseFiringThreshold,
+		DecayRate:                n.DecayRate,
+		AbsoluteRefractoryCycles: n.AbsoluteRefractoryCycles,
+		RelativeRefractoryCycles: n.RelativeRefractoryCycles,
+	}
+}
+
+// SetParameters replaces the neuron's intrinsic parameters. The current firing
+// threshold is reset to the new base threshold.
+func (n *Neuron) SetParameters(p Parameters) {
+	n.BaseFiringThreshold = p.BaseFiringThreshold
+	n.CurrentFiringThreshold = p.BaseFiringThreshold
+	n.DecayRate = p.DecayRate
+	n.AbsoluteRefractoryCycles = p.AbsoluteRefractoryCycles
+	n.RelativeRefractoryCycles = p.RelativeRefractoryCycles
+}
+
 // New creates and initializes a new Neuron instance.
 //
 // Parameters:
@@ -71,6 +103,9 @@ func New(id common.NeuronID,
 		LastMovedCycle:         -1, // -1 indicates never moved
 		Velocity:               make(common.Point, common.PointDimension), // Initialize velocity to zero vector
 		FiringHistory:          make([]common.CycleCount, 0, historyCapacity),
+		DecayRate:                simParams.NeuronBehavior.AccumulatedPulseDecayRate,
+		AbsoluteRefractoryCycles: simParams.NeuronBehavior.AbsoluteRefractoryCycles,
+		RelativeRefractoryCycles: simParams.NeuronBehavior.RelativeRefractoryCycles,
 		SimParams:              simParams, // Store the reference
 	}
 }
@@ -99,21 +134,10 @@ func (n *Neuron) IntegrateIncomingPotential(potential common.PulseValue, _ commo
 }
 
 // DecayPotential reduces the neuron's accumulated potential over time, simulating leakage.
-// The decay rate is defined in SimulationParameters.
-func (n *Neuron) DecayPotential(simParams *config.SimulationParameters) {
-	if simParams == nil {
-		// This check might be redundant if n.SimParams is always expected to be set.
-		// However, defensive programming suggests keeping it if simParams can be nil.
-		// If n.SimParams is used, ensure it's not nil at Neuron creation or before calling this.
-		// Assuming n.SimParams is reliable:
-		if n.SimParams == nil {
-			return // Or log error
-		}
-		simParams = n.SimParams // Use neuron's own SimParams if argument is nil
-	}
-
-	decayRate := simParams.NeuronBehavior.AccumulatedPulseDecayRate
-	n.AccumulatedPotential *= (1.0 - common.Potential(decayRate))
+// The decay rate is the neuron's own DecayRate, initialised from SimulationParameters.
+// The simParams argument is kept for API compatibility and is no longer read.
+func (n *Neuron) DecayPotential(_ *config.SimulationParameters) {
+	n.AccumulatedPotential *= (1.0 - common.Potential(n.DecayRate))
 
 	// Ensure potential doesn't overshoot towards negative infinity due to strong inhibition + decay.
 	// Clamp to a reasonable minimum if necessary, e.g., -BaseFiringThreshold or 0 if only positive potential matters.
@@ -182,7 +206,7 @@ func (n *Neuron) AdvanceState(currentCycle common.CycleCount, simParams *config.
 		n.CyclesInCurrentState = 0
 		n.CurrentFiringThreshold = n.BaseFiringThreshold * 1000 // Effectively infinite during absolute
 	case AbsoluteRefractory:
-		if n.CyclesInCurrentState >= simParams.NeuronBehavior.AbsoluteRefractoryCycles {
+		if n.CyclesInCurrentState >= n.AbsoluteRefractoryCycles {
 			n.CurrentState = RelativeRefractory
 			n.CyclesInCurrentState = 0
 			// Set threshold for relative refractory period (e.g., higher than base).
@@ -207,7 +231,7 @@ func (n *Neuron) AdvanceState(currentCycle common.CycleCount, simParams *config.
 			n.CurrentState = Firing
 			n.CyclesInCurrentState = 0
 			// Firing actions handled in next cycle's Firing case.
-		} else if n.CyclesInCurrentState >= simParams.NeuronBehavior.RelativeRefractoryCycles {
+		} else if n.CyclesInCurrentState >= n.RelativeRefractoryCycles {
 			// Refractory period ended, return to Resting.
 			n.CurrentState = Resting
 			n.CyclesInCurrentState = 0
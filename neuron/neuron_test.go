@@ -1,6 +1,7 @@
 package neuron
 
 import (
+	"math"
 	"testing"
 
 	"crownet/common"
@@ -343,3 +344,53 @@ func TestFiringHistoryManagement(t *testing.T) {
 		}
 	}
 }
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Type
+		wantErr bool
+	}{
+		{"Excitatory", Excitatory, false},
+		{"inhibitory", Inhibitory, false},
+		{"DOPAMINERGIC", Dopaminergic, false},
+		{"input", Input, false},
+		{"output", Output, false},
+		{"interneuron", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseType(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseType(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseType(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetParameters(t *testing.T) {
+	simParams := getDefaultSimParamsForTest()
+	n := New(1, Inhibitory, common.Point{}, simParams)
+
+	if got := n.Parameters(); got.DecayRate != simParams.NeuronBehavior.AccumulatedPulseDecayRate ||
+		got.AbsoluteRefractoryCycles != simParams.NeuronBehavior.AbsoluteRefractoryCycles {
+		t.Errorf("New() parameters = %+v, want values from NeuronBehaviorParams", got)
+	}
+
+	want := Parameters{BaseFiringThreshold: 1.7, DecayRate: 0.3, AbsoluteRefractoryCycles: 4, RelativeRefractoryCycles: 6}
+	n.SetParameters(want)
+	if got := n.Parameters(); got != want {
+		t.Errorf("Parameters() after SetParameters = %+v, want %+v", got, want)
+	}
+	if n.CurrentFiringThreshold != want.BaseFiringThreshold {
+		t.Errorf("SetParameters() CurrentFiringThreshold = %f, want %f", n.CurrentFiringThreshold, want.BaseFiringThreshold)
+	}
+
+	n.AccumulatedPotential = 1.0
+	n.DecayPotential(simParams)
+	if math.Abs(float64(n.AccumulatedPotential)-0.7) > 1e-9 {
+		t.Errorf("DecayPotential() with per-neuron DecayRate 0.3 got %f, want 0.7", n.AccumulatedPotential)
+	}
+}
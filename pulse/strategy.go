@@ -162,7 +162,8 @@ func (dpic *DefaultPulseImpactCalculator) CalculateImpact(
 		return nil // Neuron cannot be affected by its own pulse this way
 	}
 
-	distanceToTarget := space.EuclideanDistance(p.OriginPosition, targetNeuron.Position)
+	// Respects periodic wrap-around when the space is toroidal.
+	distanceToTarget := space.BoundaryFromParams(&simParams.General).Distance(p.OriginPosition, targetNeuron.Position)
 
 	if distanceToTarget >= shellStartRadius && distanceToTarget < shellEndRadius {
 		weight := weights.GetWeight(p.EmittingNeuronID, targetNeuron.ID)
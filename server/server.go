@@ -0,0 +1,392 @@
+// Package server exposes a CrowNet network through a local HTTP/JSON API, so that
+// notebooks and other tools can create a network, run it, stimulate it and read
+// its state without going through the command line.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/datagen"
+	"crownet/network"
+	"crownet/storage"
+)
+
+// maxBodyBytes limits the size of request bodies; weight matrices are the largest.
+const maxBodyBytes = 256 << 20
+
+// maxRunCycles limits the cycles run by a single request.
+const maxRunCycles = 1_000_000
+
+// Server holds at most one network and serves the API around it. Every request
+// that touches the network holds the server's lock for its whole duration, so
+// concurrent requests are applied one at a time. A run stops early, releasing the
+// lock, when its request is cancelled (the client disconnects or the server shuts down).
+type Server struct {
+	mu     sync.Mutex
+	appCfg *config.AppConfig
+	net    *network.CrowNet
+}
+
+// New returns a server without a network; POST /network creates one.
+func New() *Server {
+	return &Server{}
+}
+
+// Handler returns the HTTP handler of the API:
+//
+//	POST /network             create a network from a JSON configuration (same keys as the TOML file)
+//	GET  /network             network summary
+//	POST /network/run         run {"cycles": n} cycles
+//	POST /network/present     present {"digit": d} or {"pattern": [...]}
+//	POST /network/frequency   stimulate {"neuron_id": id, "hz": f} (0 Hz stops it)
+//	POST /network/reset       clear accumulated potentials and active pulses
+//	GET  /network/outputs     output neuron activations and firing frequencies
+//	GET  /network/weights     synaptic weights, in the JSON weights file format
+//	PUT  /network/weights     replace the synaptic weights
+//	GET  /network/chemicals   neurochemical levels and modulation factors
+//	GET  /network/dynamics    enabled dynamic processes
+//	PUT  /network/dynamics    enable or disable {"learning", "synaptogenesis", "chemical_modulation"}
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /network", s.handleCreate)
+	mux.HandleFunc("GET /network", s.withNetwork(s.handleInfo))
+	mux.HandleFunc("POST /network/run", s.withNetwork(s.handleRun))
+	mux.HandleFunc("POST /network/present", s.withNetwork(s.handlePresent))
+	mux.HandleFunc("POST /network/frequency", s.withNetwork(s.handleFrequency))
+	mux.HandleFunc("POST /network/reset", s.withNetwork(s.handleReset))
+	mux.HandleFunc("GET /network/outputs", s.withNetwork(s.handleOutputs))
+	mux.HandleFunc("GET /network/weights", s.withNetwork(s.handleGetWeights))
+	mux.HandleFunc("PUT /network/weights", s.withNetwork(s.handlePutWeights))
+	mux.HandleFunc("GET /network/chemicals", s.withNetwork(s.handleChemicals))
+	mux.HandleFunc("GET /network/dynamics", s.withNetwork(s.handleGetDynamics))
+	mux.HandleFunc("PUT /network/dynamics", s.withNetwork(s.handlePutDynamics))
+	return mux
+}
+
+// NetworkInfo summarises the network held by the server.
+type NetworkInfo struct {
+	Neurons         int               `json:"neurons"`
+	InputNeuronIDs  []common.NeuronID `json:"input_neuron_ids"`
+	OutputNeuronIDs []common.NeuronID `json:"output_neuron_ids"`
+	Cycle           common.CycleCount `json:"cycle"`
+	Topology        string            `json:"topology"`
+	Connections     int               `json:"connections"`
+	Seed            int64             `json:"seed"`
+	Dynamics        DynamicState      `json:"dynamics"`
+	Chemicals       ChemicalLevels    `json:"chemicals"`
+}
+
+// RunResult reports the outcome of POST /network/run.
+type RunResult struct {
+	Cycle          common.CycleCount `json:"cycle"`
+	Firings        int               `json:"firings"` // Firings over all the cycles run.
+	FiredLastCycle []common.NeuronID `json:"fired_last_cycle"`
+	ActivePulses   int               `json:"active_pulses"`
+}
+
+// OutputState is the state of one output neuron.
+type OutputState struct {
+	NeuronID    common.NeuronID `json:"neuron_id"`
+	Activation  float64         `json:"activation"`
+	FrequencyHz float64         `json:"frequency_hz"`
+}
+
+// ChemicalLevels holds the neurochemical levels and the modulation factors they produce.
+type ChemicalLevels struct {
+	Cortisol                 common.Level  `json:"cortisol"`
+	Dopamine                 common.Level  `json:"dopamine"`
+	LearningRateModulation   common.Factor `json:"learning_rate_modulation"`
+	SynaptogenesisModulation common.Factor `json:"synaptogenesis_modulation"`
+}
+
+// DynamicState lists which dynamic processes are enabled (see network.CrowNet.SetDynamicState).
+type DynamicState struct {
+	Learning           bool `json:"learning"`
+	Synaptogenesis     bool `json:"synaptogenesis"`
+	ChemicalModulation bool `json:"chemical_modulation"`
+}
+
+// withNetwork locks the server and calls h if a network exists, or answers 404.
+func (s *Server) withNetwork(h func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.net == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no network: create one with POST /network"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err))
+		return
+	}
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	if strings.TrimSpace(string(data)) != "" {
+		unknown, err := config.DecodeJSON(data, appCfg)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if len(unknown) > 0 {
+			keys := make([]string, len(unknown))
+			for i, k := range unknown {
+				keys[i] = k.Key
+			}
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown configuration keys: %s", strings.Join(keys, ", ")))
+			return
+		}
+	}
+	// The API drives the network itself: no SQLite logging and no weight files.
+	appCfg.Cli.Mode = config.ModeSim
+	appCfg.Cli.DbPath = ""
+	appCfg.Cli.SaveInterval = 0
+	if err := appCfg.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid configuration: %w", err))
+		return
+	}
+	net, err := network.NewCrowNet(appCfg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to create network: %w", err))
+		return
+	}
+	net.SetDynamicState(true, true, true)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appCfg, s.net = appCfg, net
+	writeJSON(w, http.StatusCreated, s.info())
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.info())
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Cycles int `json:"cycles"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Cycles <= 0 || req.Cycles > maxRunCycles {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("cycles must be between 1 and %d, got %d", maxRunCycles, req.Cycles))
+		return
+	}
+	result := RunResult{}
+	for i := 0; i < req.Cycles; i++ {
+		if err := r.Context().Err(); err != nil {
+			writeError(w, http.StatusServiceUnavailable,
+				fmt.Errorf("run interrupted after %d of %d cycles: %w", i, req.Cycles, err))
+			return
+		}
+		s.net.RunCycle()
+		result.Firings += len(s.net.FiredLastCycle())
+	}
+	result.Cycle = s.net.CycleCount
+	result.FiredLastCycle = append([]common.NeuronID{}, s.net.FiredLastCycle()...)
+	result.ActivePulses = len(s.net.ActivePulses.GetAll())
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handlePresent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Digit   *int      `json:"digit"`
+		Pattern []float64 `json:"pattern"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	pattern := req.Pattern
+	switch {
+	case req.Digit != nil && pattern != nil:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("give either digit or pattern, not both"))
+		return
+	case req.Digit != nil:
+		var err error
+		if pattern, err = datagen.GetDigitPatternFn(*req.Digit, &s.appCfg.SimParams); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	case pattern == nil:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("digit or pattern is required"))
+		return
+	}
+	s.net.ResetNetworkStateForNewPattern()
+	if err := s.net.PresentPattern(pattern); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.info())
+}
+
+func (s *Server) handleFrequency(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		NeuronID common.NeuronID `json:"neuron_id"`
+		Hz       float64         `json:"hz"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if err := s.net.ConfigureFrequencyInput(req.NeuronID, req.Hz); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, _ *http.Request) {
+	s.net.ResetNetworkStateForNewPattern()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleOutputs(w http.ResponseWriter, _ *http.Request) {
+	activations, err := s.net.GetOutputActivation()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	outputs := make([]OutputState, len(activations))
+	for i, activation := range activations {
+		id := s.net.OutputNeuronIDs[i]
+		freq, err := s.net.GetOutputFrequency(id)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		outputs[i] = OutputState{NeuronID: id, Activation: activation, FrequencyHz: freq}
+	}
+	writeJSON(w, http.StatusOK, map[string][]OutputState{"outputs": outputs})
+}
+
+func (s *Server) handleGetWeights(w http.ResponseWriter, _ *http.Request) {
+	// Encode before writing anything, so that a failure can still be answered with 500.
+	var buf bytes.Buffer
+	if err := storage.WriteWeightsJSON(&buf, s.net.SynapticWeights.GetAllWeights()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = buf.WriteTo(w)
+}
+
+func (s *Server) handlePutWeights(w http.ResponseWriter, r *http.Request) {
+	weights, err := storage.ReadWeightsJSON(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid weights: %w", err))
+		return
+	}
+	for from, targets := range weights {
+		if _, ok := s.net.GetNeuron(from); !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid weights: neuron %d does not exist", from))
+			return
+		}
+		for to := range targets {
+			if _, ok := s.net.GetNeuron(to); !ok {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid weights: neuron %d does not exist", to))
+				return
+			}
+		}
+	}
+	s.net.SynapticWeights.LoadWeights(weights)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleChemicals(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.chemicals())
+}
+
+func (s *Server) handleGetDynamics(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.dynamics())
+}
+
+func (s *Server) handlePutDynamics(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Learning           *bool `json:"learning"`
+		Synaptogenesis     *bool `json:"synaptogenesis"`
+		ChemicalModulation *bool `json:"chemical_modulation"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	state := s.dynamics()
+	if req.Learning != nil {
+		state.Learning = *req.Learning
+	}
+	if req.Synaptogenesis != nil {
+		state.Synaptogenesis = *req.Synaptogenesis
+	}
+	if req.ChemicalModulation != nil {
+		state.ChemicalModulation = *req.ChemicalModulation
+	}
+	s.net.SetDynamicState(state.Learning, state.Synaptogenesis, state.ChemicalModulation)
+	writeJSON(w, http.StatusOK, state)
+}
+
+// info, chemicals and dynamics must be called with the lock held.
+func (s *Server) info() NetworkInfo {
+	return NetworkInfo{
+		Neurons:         len(s.net.Neurons),
+		InputNeuronIDs:  s.net.InputNeuronIDs,
+		OutputNeuronIDs: s.net.OutputNeuronIDs,
+		Cycle:           s.net.CycleCount,
+		Topology:        s.net.TopologyStats.Generator,
+		Connections:     s.net.TopologyStats.Connections,
+		Seed:            s.appCfg.Cli.Seed,
+		Dynamics:        s.dynamics(),
+		Chemicals:       s.chemicals(),
+	}
+}
+
+func (s *Server) chemicals() ChemicalLevels {
+	env := s.net.ChemicalEnv
+	return ChemicalLevels{
+		Cortisol:                 env.CortisolLevel,
+		Dopamine:                 env.DopamineLevel,
+		LearningRateModulation:   env.LearningRateModulationFactor,
+		SynaptogenesisModulation: env.SynaptogenesisModulationFactor,
+	}
+}
+
+func (s *Server) dynamics() DynamicState {
+	learning, synaptogenesis, chemical := s.net.DynamicState()
+	return DynamicState{Learning: learning, Synaptogenesis: synaptogenesis, ChemicalModulation: chemical}
+}
+
+// readJSON decodes the request body into v, rejecting unknown fields, and answers
+// 400 if that fails.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = fmt.Errorf("empty request body")
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,169 @@
+package space
+
+import (
+	"fmt"
+	"math"
+
+	"crownet/common"
+	"crownet/config"
+)
+
+// BoundaryMode selects how neuron positions are kept inside the simulation space.
+type BoundaryMode string
+
+const (
+	// BoundaryClamp clamps each coordinate to [-SpaceMaxDimension, SpaceMaxDimension],
+	// keeping points inside the hypercube (the original behaviour).
+	BoundaryClamp BoundaryMode = config.BoundaryClamp
+	// BoundaryReflect mirrors points that leave the hypersphere back inside it
+	// and inverts the radial component of their velocity (elastic reflection).
+	BoundaryReflect BoundaryMode = config.BoundaryReflect
+	// BoundaryPeriodic wraps coordinates around the hypercube
+	// [-SpaceMaxDimension, SpaceMaxDimension) in every dimension (toroidal space).
+	BoundaryPeriodic BoundaryMode = config.BoundaryPeriodic
+)
+
+// Boundary describes the boundary condition of the simulation space.
+// HalfWidth is the half side length of the hypercube for clamp and periodic
+// modes and the radius of the hypersphere for reflect mode.
+// The zero value is not usable; build one with NewBoundary or BoundaryFromParams.
+type Boundary struct {
+	Mode      BoundaryMode
+	HalfWidth float64
+}
+
+// NewBoundary validates mode and halfWidth and returns the corresponding Boundary.
+// An empty mode defaults to BoundaryClamp.
+func NewBoundary(mode string, halfWidth float64) (Boundary, error) {
+	if halfWidth <= 0 {
+		return Boundary{}, fmt.Errorf("NewBoundary: halfWidth must be positive, got %f", halfWidth)
+	}
+	switch BoundaryMode(mode) {
+	case "":
+		return Boundary{Mode: BoundaryClamp, HalfWidth: halfWidth}, nil
+	case BoundaryClamp, BoundaryReflect, BoundaryPeriodic:
+		return Boundary{Mode: BoundaryMode(mode), HalfWidth: halfWidth}, nil
+	default:
+		return Boundary{}, fmt.Errorf("NewBoundary: unknown boundary mode '%s'", mode)
+	}
+}
+
+// BoundaryFromParams builds the Boundary described by the general simulation parameters.
+// Invalid parameters (which config.Validate rejects) fall back to a clamp boundary.
+func BoundaryFromParams(general *config.GeneralParams) Boundary {
+	b, err := NewBoundary(general.BoundaryMode, general.SpaceMaxDimension)
+	if err != nil {
+		return Boundary{Mode: BoundaryClamp, HalfWidth: general.SpaceMaxDimension}
+	}
+	return b
+}
+
+// IsPeriodic reports whether the boundary wraps coordinates around the hypercube.
+func (b Boundary) IsPeriodic() bool {
+	return b.Mode == BoundaryPeriodic
+}
+
+// Displacement returns the vector pointing from p1 to p2. Under periodic
+// boundaries the minimum-image convention is used, so the vector crosses the
+// wrap-around seam whenever that is the shorter path.
+func (b Boundary) Displacement(p1, p2 common.Point) common.Vector {
+	var d common.Vector
+	period := 2 * b.HalfWidth
+	for i := range p1 {
+		diff := float64(p2[i] - p1[i])
+		if b.IsPeriodic() {
+			diff -= period * math.Round(diff/period)
+		}
+		d[i] = common.Coordinate(diff)
+	}
+	return d
+}
+
+// Distance returns the distance between p1 and p2 under the boundary condition.
+// For clamp and reflect modes this is exactly EuclideanDistance; for periodic
+// mode it is the shortest distance on the torus.
+func (b Boundary) Distance(p1, p2 common.Point) float64 {
+	if !b.IsPeriodic() {
+		return EuclideanDistance(p1, p2)
+	}
+	d := b.Displacement(p1, p2)
+	return Magnitude(common.Point(d))
+}
+
+// Apply brings a position that may have left the space back inside it and
+// returns the corrected position and velocity.
+//   - Clamp: each coordinate is clamped to [-HalfWidth, HalfWidth]; velocity is unchanged.
+//   - Reflect: the overshoot is mirrored back inside the hypersphere and the radial
+//     component of the velocity is inverted.
+//   - Periodic: each coordinate is wrapped into [-HalfWidth, HalfWidth); velocity is unchanged.
+func (b Boundary) Apply(p common.Point, v common.Vector) (common.Point, common.Vector) {
+	switch b.Mode {
+	case BoundaryReflect:
+		return reflectInHyperSphere(p, v, b.HalfWidth)
+	case BoundaryPeriodic:
+		return WrapToHyperCube(p, b.HalfWidth), v
+	default:
+		return ClampToHyperCube(p, b.HalfWidth), v
+	}
+}
+
+// ClampToHyperCube clamps every coordinate of p to [-halfWidth, halfWidth].
+func ClampToHyperCube(p common.Point, halfWidth float64) common.Point {
+	var clamped common.Point
+	for i := range p {
+		clamped[i] = common.Coordinate(math.Max(-halfWidth, math.Min(halfWidth, float64(p[i]))))
+	}
+	return clamped
+}
+
+// WrapToHyperCube maps every coordinate of p into [-halfWidth, halfWidth)
+// as on a torus. A non-positive halfWidth returns p unchanged.
+func WrapToHyperCube(p common.Point, halfWidth float64) common.Point {
+	if halfWidth <= 0 {
+		return p
+	}
+	period := 2 * halfWidth
+	var wrapped common.Point
+	for i := range p {
+		x := math.Mod(float64(p[i])+halfWidth, period)
+		if x < 0 {
+			x += period
+		}
+		wrapped[i] = common.Coordinate(x - halfWidth)
+	}
+	return wrapped
+}
+
+// reflectInHyperSphere mirrors a point lying outside the hypersphere of radius
+// maxRadius back inside it across the surface, and inverts the radial component
+// of the velocity so the neuron moves away from the wall.
+// Points overshooting by more than the diameter are left on the opposite side
+// of the centre at most maxRadius away.
+func reflectInHyperSphere(p common.Point, v common.Vector, maxRadius float64) (common.Point, common.Vector) {
+	dist := Magnitude(p)
+	if dist <= maxRadius || dist == 0 {
+		return p, v
+	}
+
+	reflectedDist := 2*maxRadius - dist
+	if reflectedDist < -maxRadius {
+		reflectedDist = -maxRadius
+	}
+	scale := reflectedDist / dist
+
+	var reflected common.Point
+	radialSpeed := 0.0
+	for i := range p {
+		reflected[i] = common.Coordinate(float64(p[i]) * scale)
+		radialSpeed += float64(v[i]) * float64(p[i]) / dist
+	}
+
+	if radialSpeed <= 0 { // Already moving back inwards.
+		return reflected, v
+	}
+	var bounced common.Vector
+	for i := range v {
+		bounced[i] = v[i] - common.Coordinate(2*radialSpeed*float64(p[i])/dist)
+	}
+	return reflected, bounced
+}
@@ -0,0 +1,169 @@
+package space
+
+import (
+	"math"
+	"testing"
+
+	"crownet/common"
+	"crownet/config"
+)
+
+func TestNewBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		halfWidth float64
+		wantMode  BoundaryMode
+		wantErr   bool
+	}{
+		{"clamp", "clamp", 10, BoundaryClamp, false},
+		{"reflect", "reflect", 10, BoundaryReflect, false},
+		{"periodic", "periodic", 10, BoundaryPeriodic, false},
+		{"empty defaults to clamp", "", 10, BoundaryClamp, false},
+		{"unknown mode", "bounce", 10, "", true},
+		{"zero half width", "clamp", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBoundary(tt.mode, tt.halfWidth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBoundary(%q, %f) error = %v, wantErr %v", tt.mode, tt.halfWidth, err, tt.wantErr)
+			}
+			if !tt.wantErr && b.Mode != tt.wantMode {
+				t.Errorf("NewBoundary(%q, %f).Mode = %q, want %q", tt.mode, tt.halfWidth, b.Mode, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestBoundaryFromParams(t *testing.T) {
+	general := config.DefaultSimulationParameters().General
+	general.BoundaryMode = config.BoundaryPeriodic
+	b := BoundaryFromParams(&general)
+	if b.Mode != BoundaryPeriodic || b.HalfWidth != general.SpaceMaxDimension {
+		t.Errorf("BoundaryFromParams() = %+v, want periodic with half width %f", b, general.SpaceMaxDimension)
+	}
+}
+
+func TestWrapToHyperCube(t *testing.T) {
+	tests := []struct {
+		name string
+		in   common.Coordinate
+		want common.Coordinate
+	}{
+		{"inside", 3, 3},
+		{"past upper edge", 11, -9},
+		{"past lower edge", -12, 8},
+		{"upper edge maps to lower edge", 10, -10},
+		{"lower edge unchanged", -10, -10},
+		{"several periods away", 47, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapToHyperCube(common.Point{tt.in}, 10)
+			if math.Abs(float64(got[0]-tt.want)) > 1e-9 {
+				t.Errorf("WrapToHyperCube(%f, 10)[0] = %f, want %f", tt.in, got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundaryDistancePeriodic(t *testing.T) {
+	periodic := Boundary{Mode: BoundaryPeriodic, HalfWidth: 10}
+	clamp := Boundary{Mode: BoundaryClamp, HalfWidth: 10}
+	p1 := common.Point{-9.5, 0}
+	p2 := common.Point{9.5, 0}
+
+	if d := periodic.Distance(p1, p2); math.Abs(d-1.0) > 1e-9 {
+		t.Errorf("periodic Distance across seam = %f, want 1.0", d)
+	}
+	if d := clamp.Distance(p1, p2); math.Abs(d-19.0) > 1e-9 {
+		t.Errorf("clamp Distance = %f, want 19.0", d)
+	}
+
+	disp := periodic.Displacement(p1, p2)
+	if math.Abs(float64(disp[0])+1.0) > 1e-9 {
+		t.Errorf("periodic Displacement x = %f, want -1.0 (shortest path crosses the seam)", disp[0])
+	}
+}
+
+func TestBoundaryApply(t *testing.T) {
+	outside := common.Point{12, 0}
+	outward := common.Vector{2, 1}
+
+	t.Run("clamp limits each coordinate to the cube", func(t *testing.T) {
+		b := Boundary{Mode: BoundaryClamp, HalfWidth: 10}
+		p, v := b.Apply(outside, outward)
+		if math.Abs(float64(p[0])-10) > 1e-9 {
+			t.Errorf("clamp Apply position x = %f, want 10", p[0])
+		}
+		// A corner point inside the cube but outside the inscribed sphere is kept.
+		corner := common.Point{9, 9, -11}
+		if got, _ := b.Apply(corner, outward); got[0] != 9 || got[1] != 9 || got[2] != -10 {
+			t.Errorf("clamp Apply(%v) = %v, want (9, 9, -10)", corner, got)
+		}
+		if v != outward {
+			t.Errorf("clamp Apply changed velocity to %v, want %v", v, outward)
+		}
+	})
+
+	t.Run("reflect mirrors the overshoot and inverts radial velocity", func(t *testing.T) {
+		b := Boundary{Mode: BoundaryReflect, HalfWidth: 10}
+		p, v := b.Apply(outside, outward)
+		if math.Abs(float64(p[0])-8) > 1e-9 {
+			t.Errorf("reflect Apply position x = %f, want 8", p[0])
+		}
+		if math.Abs(float64(v[0])+2) > 1e-9 || math.Abs(float64(v[1])-1) > 1e-9 {
+			t.Errorf("reflect Apply velocity = %v, want radial component inverted to (-2, 1)", v)
+		}
+	})
+
+	t.Run("reflect leaves inside points alone", func(t *testing.T) {
+		b := Boundary{Mode: BoundaryReflect, HalfWidth: 10}
+		inside := common.Point{3, 4}
+		p, v := b.Apply(inside, outward)
+		if p != inside || v != outward {
+			t.Errorf("reflect Apply(inside) = (%v, %v), want unchanged", p, v)
+		}
+	})
+
+	t.Run("periodic wraps to the opposite face", func(t *testing.T) {
+		b := Boundary{Mode: BoundaryPeriodic, HalfWidth: 10}
+		p, v := b.Apply(outside, outward)
+		if math.Abs(float64(p[0])+8) > 1e-9 {
+			t.Errorf("periodic Apply position x = %f, want -8", p[0])
+		}
+		if v != outward {
+			t.Errorf("periodic Apply changed velocity to %v, want %v", v, outward)
+		}
+	})
+}
+
+func TestSpatialGridPeriodicQuery(t *testing.T) {
+	var minBound common.Point
+	for i := range minBound {
+		minBound[i] = -10
+	}
+	sg, err := NewSpatialGrid(3.0, pointDimension, minBound)
+	if err != nil {
+		t.Fatalf("NewSpatialGrid() error = %v", err)
+	}
+	sg.SetBoundary(Boundary{Mode: BoundaryPeriodic, HalfWidth: 10})
+
+	nearUpperFace := newTestNeuron(1, common.Point{9.8})
+	sg.Build(nil)
+	sg.AddNeuron(nearUpperFace)
+
+	candidates := sg.QuerySphereForCandidates(common.Point{-9.8}, 1.0)
+	found := false
+	for _, n := range candidates {
+		if n.ID == nearUpperFace.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("QuerySphereForCandidates across the periodic seam did not return neuron %d", nearUpperFace.ID)
+	}
+}
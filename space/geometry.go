@@ -27,6 +27,15 @@ func EuclideanDistance(p1, p2 common.Point) float64 {
 	return math.Sqrt(sumOfSquares)
 }
 
+// Magnitude returns the Euclidean length of p interpreted as a vector from the origin.
+func Magnitude(p common.Point) float64 {
+	var sumOfSquares float64
+	for i := range p {
+		sumOfSquares += float64(p[i]) * float64(p[i])
+	}
+	return math.Sqrt(sumOfSquares)
+}
+
 // IsWithinRadius checks if a point pTest is within a specified Euclidean distance (radius)
 // from a central point pCenter in N-dimensional space.
 // It handles negative radius by returning false.
@@ -138,9 +147,9 @@ func GenerateRandomPositionInHyperSphere(maxRadius float64, rng *rand.Rand) comm
 	normDeviates := make([]float64, pointDimension)
 	sumSq := 0.0
 	for i := 0; i < pointDimension; i++ {
-		// Changed from rng.NormFloat64() to package-level rand.NormFloat64()
-		// This uses the global math/rand source, not the passed rng instance for these deviates.
-		val := rand.NormFloat64()
+		// The passed rng is used (not the global math/rand source) so that positions
+		// are reproducible for a given seed.
+		val := rng.NormFloat64()
 		normDeviates[i] = val
 		sumSq += val * val
 	}
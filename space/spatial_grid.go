@@ -17,6 +17,7 @@ type SpatialGrid struct {
 	cellSize         float64
 	gridOriginOffset common.Point // The world coordinate that maps to cell index (0,0,...,0).
 	numDims          int
+	boundary         *Boundary // Optional; when periodic, queries wrap around the hypercube.
 }
 
 // NewSpatialGrid creates and returns a new SpatialGrid instance.
@@ -52,6 +53,12 @@ func NewSpatialGrid(cellSize float64, numDims int, spaceMinBound common.Point) (
 	return sg, nil
 }
 
+// SetBoundary sets the boundary condition used by queries. With a periodic
+// boundary, QuerySphereForCandidates also returns neurons across the wrap-around seam.
+func (sg *SpatialGrid) SetBoundary(b Boundary) {
+	sg.boundary = &b
+}
+
 // GetCellID calculates the cell ID for a given point in world coordinates.
 func (sg *SpatialGrid) GetCellID(point common.Point) CellID {
 	var id CellID
@@ -97,25 +104,78 @@ func (sg *SpatialGrid) QuerySphereForCandidates(center common.Point, radius floa
 		return candidateNeurons
 	}
 
-	minCellIndices := [pointDimension]int{}
-	maxCellIndices := [pointDimension]int{}
-
+	var cellIndicesPerDim [pointDimension][]int
 	for i := 0; i < sg.numDims; i++ {
 		sphereMinDimCoord := float64(center[i]) - radius
 		sphereMaxDimCoord := float64(center[i]) + radius
-		minCellIndices[i] = int(math.Floor((sphereMinDimCoord - float64(sg.gridOriginOffset[i])) / sg.cellSize))
-		maxCellIndices[i] = int(math.Floor((sphereMaxDimCoord - float64(sg.gridOriginOffset[i])) / sg.cellSize))
+		if sg.boundary != nil && sg.boundary.IsPeriodic() {
+			cellIndicesPerDim[i] = sg.periodicCellRange(i, sphereMinDimCoord, sphereMaxDimCoord)
+			continue
+		}
+		minCell := sg.cellIndex(i, sphereMinDimCoord)
+		maxCell := sg.cellIndex(i, sphereMaxDimCoord)
+		for c := minCell; c <= maxCell; c++ {
+			cellIndicesPerDim[i] = append(cellIndicesPerDim[i], c)
+		}
 	}
 
 	var currentCellVisit [pointDimension]int // Corrected from common.PointDimension
-	sg.queryCellsRecursive(minCellIndices, maxCellIndices, &currentCellVisit, 0, &candidateNeurons)
+	sg.queryCellsRecursive(&cellIndicesPerDim, &currentCellVisit, 0, &candidateNeurons)
 
 	return candidateNeurons
 }
 
-// queryCellsRecursive is a helper to iterate N-dimensionally through a range of cells.
+// cellIndex returns the index of the cell containing world coordinate coord along dimension dim.
+func (sg *SpatialGrid) cellIndex(dim int, coord float64) int {
+	return int(math.Floor((coord - float64(sg.gridOriginOffset[dim])) / sg.cellSize))
+}
+
+// periodicCellRange returns the distinct cell indices along dimension dim that
+// intersect the interval [minCoord, maxCoord] once it is wrapped around the
+// periodic hypercube [-HalfWidth, HalfWidth).
+func (sg *SpatialGrid) periodicCellRange(dim int, minCoord, maxCoord float64) []int {
+	halfWidth := sg.boundary.HalfWidth
+	period := 2 * halfWidth
+	firstCell := sg.cellIndex(dim, -halfWidth)
+	lastCell := sg.cellIndex(dim, math.Nextafter(halfWidth, -halfWidth))
+
+	if maxCoord-minCoord >= period { // The interval covers the whole dimension.
+		indices := make([]int, 0, lastCell-firstCell+1)
+		for c := firstCell; c <= lastCell; c++ {
+			indices = append(indices, c)
+		}
+		return indices
+	}
+
+	seen := make(map[int]struct{})
+	indices := make([]int, 0)
+	// The interval can overlap the space at most in its original position and
+	// shifted by one period in either direction.
+	for _, shift := range []float64{-period, 0, period} {
+		lo := math.Max(minCoord+shift, -halfWidth)
+		hi := math.Min(maxCoord+shift, halfWidth)
+		if lo > hi {
+			continue
+		}
+		loCell := sg.cellIndex(dim, lo)
+		hiCell := sg.cellIndex(dim, hi)
+		if hiCell > lastCell {
+			hiCell = lastCell
+		}
+		for c := loCell; c <= hiCell; c++ {
+			if _, dup := seen[c]; !dup {
+				seen[c] = struct{}{}
+				indices = append(indices, c)
+			}
+		}
+	}
+	return indices
+}
+
+// queryCellsRecursive is a helper to iterate N-dimensionally through the
+// cartesian product of the given per-dimension cell indices.
 func (sg *SpatialGrid) queryCellsRecursive(
-	minCellIndices, maxCellIndices [pointDimension]int,
+	cellIndicesPerDim *[pointDimension][]int,
 	currentCellIndices *[pointDimension]int,
 	dim int,
 	candidateNeurons *[]*neuron.Neuron,
@@ -130,8 +190,8 @@ func (sg *SpatialGrid) queryCellsRecursive(
 		return
 	}
 
-	for i := minCellIndices[dim]; i <= maxCellIndices[dim]; i++ {
+	for _, i := range cellIndicesPerDim[dim] {
 		(*currentCellIndices)[dim] = i
-		sg.queryCellsRecursive(minCellIndices, maxCellIndices, currentCellIndices, dim+1, candidateNeurons)
+		sg.queryCellsRecursive(cellIndicesPerDim, currentCellIndices, dim+1, candidateNeurons)
 	}
 }
@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// This file implements the subset of the Apache Arrow IPC file format ("Feather v2")
+// needed by the log exporter: a flat schema of nullable Int64, Float64 and Utf8
+// columns written as a sequence of record batches. The layout follows the Arrow
+// columnar format specification (metadata version V5, little endian, no compression).
+// Keeping it in-tree avoids pulling the full Arrow module into the build.
+
+// arrowType is the logical type of an Arrow column written by arrowFileWriter.
+type arrowType int
+
+const (
+	arrowInt64 arrowType = iota
+	arrowFloat64
+	arrowUtf8
+)
+
+// arrowField describes one column of an Arrow schema.
+type arrowField struct {
+	name string
+	typ  arrowType
+}
+
+// arrowMagic starts and ends every Arrow IPC file.
+const arrowMagic = "ARROW1"
+
+// Flatbuffer enum and union values from the Arrow format definitions (Schema.fbs, Message.fbs).
+const (
+	arrowMetadataV5        = 4
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+	arrowTypeInt           = 2
+	arrowTypeFloatingPoint = 3
+	arrowTypeUtf8          = 5
+	arrowPrecisionDouble   = 2
+)
+
+// arrowBlock locates one record batch in the file, for the footer.
+type arrowBlock struct {
+	offset         int64
+	metaDataLength int32
+	bodyLength     int64
+}
+
+// arrowColumn accumulates the values of one column of the current record batch.
+type arrowColumn struct {
+	valid     []bool
+	nullCount int
+	values    []byte  // Int64/Float64 values, or Utf8 character data.
+	offsets   []int32 // Utf8 only: start of each value in values, plus the end.
+}
+
+// arrowFileWriter writes an Arrow IPC file. Rows are buffered column by column and
+// written as a record batch every batchRows rows, so memory use stays bounded for
+// large tables.
+type arrowFileWriter struct {
+	w         io.Writer
+	pos       int64
+	fields    []arrowField
+	columns   []arrowColumn
+	rows      int
+	batchRows int
+	batches   []arrowBlock
+}
+
+// newArrowFileWriter writes the file header and the schema message for fields.
+func newArrowFileWriter(w io.Writer, fields []arrowField, batchRows int) (*arrowFileWriter, error) {
+	aw := &arrowFileWriter{w: w, fields: fields, batchRows: batchRows}
+	aw.resetColumns()
+	if err := aw.write([]byte(arrowMagic + "\x00\x00")); err != nil {
+		return nil, err
+	}
+	message := fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbScalar(1, arrowHeaderSchema),
+		fbRef(aw.schemaTable()),
+		fbScalar(8, 0),
+	}
+	if _, err := aw.writeMessage(message, nil); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// appendRow adds one row. Each value must be nil (null) or match the column type:
+// int64 for arrowInt64, float64 for arrowFloat64 and string for arrowUtf8.
+func (aw *arrowFileWriter) appendRow(values []any) error {
+	if len(values) != len(aw.fields) {
+		return fmt.Errorf("arrow row has %d values, schema has %d fields", len(values), len(aw.fields))
+	}
+	for i, v := range values {
+		col := &aw.columns[i]
+		col.valid = append(col.valid, v != nil)
+		if v == nil {
+			col.nullCount++
+		}
+		switch aw.fields[i].typ {
+		case arrowInt64:
+			x, _ := v.(int64)
+			col.values = binary.LittleEndian.AppendUint64(col.values, uint64(x))
+		case arrowFloat64:
+			x, _ := v.(float64)
+			col.values = binary.LittleEndian.AppendUint64(col.values, math.Float64bits(x))
+		case arrowUtf8:
+			x, _ := v.(string)
+			col.values = append(col.values, x...)
+			col.offsets = append(col.offsets, int32(len(col.values)))
+		}
+	}
+	aw.rows++
+	if aw.rows >= aw.batchRows {
+		return aw.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as one record batch.
+func (aw *arrowFileWriter) flush() error {
+	if aw.rows == 0 {
+		return nil
+	}
+	var body []byte
+	var nodes, buffers []byte
+	addBuffer := func(data []byte) {
+		buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(body)))
+		buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(data)))
+		body = append(body, data...)
+		body = padTo(body, 8)
+	}
+	for i := range aw.columns {
+		col := &aw.columns[i]
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(aw.rows))
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(col.nullCount))
+		if col.nullCount > 0 {
+			addBuffer(validityBitmap(col.valid))
+		} else {
+			addBuffer(nil) // A column without nulls may omit its validity bitmap.
+		}
+		if aw.fields[i].typ == arrowUtf8 {
+			offsets := make([]byte, 0, 4*len(col.offsets))
+			for _, o := range col.offsets {
+				offsets = binary.LittleEndian.AppendUint32(offsets, uint32(o))
+			}
+			addBuffer(offsets)
+		}
+		addBuffer(col.values)
+	}
+
+	recordBatch := fbTable{
+		fbScalar(8, uint64(aw.rows)),
+		fbRef(&fbStructVector{elemSize: 16, data: nodes}),
+		fbRef(&fbStructVector{elemSize: 16, data: buffers}),
+	}
+	message := fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbScalar(1, arrowHeaderRecordBatch),
+		fbRef(recordBatch),
+		fbScalar(8, uint64(len(body))),
+	}
+	block, err := aw.writeMessage(message, body)
+	if err != nil {
+		return err
+	}
+	aw.batches = append(aw.batches, block)
+	aw.resetColumns()
+	return nil
+}
+
+// close writes the remaining rows, the end-of-stream marker and the file footer.
+// It does not close the underlying writer.
+func (aw *arrowFileWriter) close() error {
+	if err := aw.flush(); err != nil {
+		return err
+	}
+	if err := aw.write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}); err != nil {
+		return err
+	}
+
+	var blocks []byte
+	for _, b := range aw.batches {
+		blocks = binary.LittleEndian.AppendUint64(blocks, uint64(b.offset))
+		blocks = binary.LittleEndian.AppendUint32(blocks, uint32(b.metaDataLength))
+		blocks = binary.LittleEndian.AppendUint32(blocks, 0) // Struct padding.
+		blocks = binary.LittleEndian.AppendUint64(blocks, uint64(b.bodyLength))
+	}
+	footer := encodeFlatbuffer(fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbRef(aw.schemaTable()),
+		fbRef(&fbStructVector{elemSize: 24, data: nil}),
+		fbRef(&fbStructVector{elemSize: 24, data: blocks}),
+	})
+	if err := aw.write(footer); err != nil {
+		return err
+	}
+	if err := aw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return aw.write([]byte(arrowMagic))
+}
+
+// schemaTable builds the Schema flatbuffer table for the writer's fields.
+func (aw *arrowFileWriter) schemaTable() fbTable {
+	fields := make(fbTableVector, len(aw.fields))
+	for i, f := range aw.fields {
+		var typeID uint64
+		var typeTable fbTable
+		switch f.typ {
+		case arrowInt64:
+			typeID, typeTable = arrowTypeInt, fbTable{fbScalar(4, 64), fbScalar(1, 1)}
+		case arrowFloat64:
+			typeID, typeTable = arrowTypeFloatingPoint, fbTable{fbScalar(2, arrowPrecisionDouble)}
+		case arrowUtf8:
+			typeID, typeTable = arrowTypeUtf8, fbTable{}
+		}
+		fields[i] = fbTable{
+			fbRef(fbString(f.name)),
+			fbScalar(1, 1), // nullable
+			fbScalar(1, typeID),
+			fbRef(typeTable),
+			{},                     // dictionary
+			fbRef(fbTableVector{}), // children
+		}
+	}
+	return fbTable{
+		fbScalar(2, 0), // little endian
+		fbRef(fields),
+	}
+}
+
+// writeMessage writes an encapsulated IPC message: continuation marker, metadata
+// length, the Message flatbuffer padded to 8 bytes, then the body.
+func (aw *arrowFileWriter) writeMessage(message fbTable, body []byte) (arrowBlock, error) {
+	meta := padTo(encodeFlatbuffer(message), 8)
+	block := arrowBlock{offset: aw.pos, metaDataLength: int32(8 + len(meta)), bodyLength: int64(len(body))}
+	header := binary.LittleEndian.AppendUint32(nil, 0xFFFFFFFF)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(meta)))
+	for _, part := range [][]byte{header, meta, body} {
+		if err := aw.write(part); err != nil {
+			return arrowBlock{}, err
+		}
+	}
+	return block, nil
+}
+
+func (aw *arrowFileWriter) write(p []byte) error {
+	n, err := aw.w.Write(p)
+	aw.pos += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write Arrow data: %w", err)
+	}
+	return nil
+}
+
+func (aw *arrowFileWriter) resetColumns() {
+	aw.columns = make([]arrowColumn, len(aw.fields))
+	for i, f := range aw.fields {
+		if f.typ == arrowUtf8 {
+			aw.columns[i].offsets = []int32{0}
+		}
+	}
+	aw.rows = 0
+}
+
+// validityBitmap packs validity flags into an LSB-first bitmap.
+func validityBitmap(valid []bool) []byte {
+	bitmap := make([]byte, (len(valid)+7)/8)
+	for i, ok := range valid {
+		if ok {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	return bitmap
+}
+
+// padTo appends zero bytes until len(b) is a multiple of align.
+func padTo(b []byte, align int) []byte {
+	for len(b)%align != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// Minimal FlatBuffers encoder. Objects are laid out front to back: every object is
+// written after the object that references it, which keeps all unsigned offsets
+// positive. Each vtable is placed directly before its table. Alignment is relative
+// to the start of the buffer, which Arrow keeps 8-byte aligned in the file.
+
+// fbObject is anything that can be referenced by an offset.
+type fbObject interface {
+	// place appends the object to buf and returns the buffer and the object's position.
+	place(buf []byte) ([]byte, int)
+}
+
+// fbField is one table slot: a little-endian scalar of size bytes, a reference
+// to another object, or absent (the zero value).
+type fbField struct {
+	size  int
+	value uint64
+	ref   fbObject
+}
+
+func fbScalar(size int, value uint64) fbField { return fbField{size: size, value: value} }
+func fbRef(obj fbObject) fbField              { return fbField{size: 4, ref: obj} }
+
+// fbTable is a table whose field IDs are the slice indices.
+type fbTable []fbField
+
+func (t fbTable) place(buf []byte) ([]byte, int) {
+	// Lay out the inline fields after the 4-byte vtable offset, each aligned to its size.
+	offsets := make([]int, len(t))
+	size, align := 4, 4
+	for i, f := range t {
+		if f.size == 0 {
+			continue
+		}
+		size = (size + f.size - 1) / f.size * f.size
+		offsets[i] = size
+		size += f.size
+		if f.size > align {
+			align = f.size
+		}
+	}
+
+	buf = padTo(buf, 2)
+	vtablePos := len(buf)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(4+2*len(t)))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(size))
+	for _, o := range offsets {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(o))
+	}
+
+	buf = padTo(buf, align)
+	tablePos := len(buf)
+	buf = append(buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(buf[tablePos:], uint32(int32(tablePos-vtablePos)))
+	for i, f := range t {
+		p := tablePos + offsets[i]
+		switch {
+		case f.size == 0 || f.ref != nil:
+		case f.size == 1:
+			buf[p] = byte(f.value)
+		case f.size == 2:
+			binary.LittleEndian.PutUint16(buf[p:], uint16(f.value))
+		case f.size == 4:
+			binary.LittleEndian.PutUint32(buf[p:], uint32(f.value))
+		case f.size == 8:
+			binary.LittleEndian.PutUint64(buf[p:], f.value)
+		}
+	}
+	for i, f := range t {
+		if f.ref == nil {
+			continue
+		}
+		var childPos int
+		buf, childPos = f.ref.place(buf)
+		p := tablePos + offsets[i]
+		binary.LittleEndian.PutUint32(buf[p:], uint32(childPos-p))
+	}
+	return buf, tablePos
+}
+
+// fbString is a null-terminated, length-prefixed string.
+type fbString string
+
+func (s fbString) place(buf []byte) ([]byte, int) {
+	buf = padTo(buf, 4)
+	pos := len(buf)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	buf = append(buf, s...)
+	return append(buf, 0), pos
+}
+
+// fbStructVector is a vector of fixed-size structs given as raw little-endian bytes.
+// Elements are 8-byte aligned, which covers every struct used by Arrow.
+type fbStructVector struct {
+	elemSize int
+	data     []byte
+}
+
+func (v *fbStructVector) place(buf []byte) ([]byte, int) {
+	buf = padTo(buf, 4)
+	for (len(buf)+4)%8 != 0 {
+		buf = append(buf, 0, 0, 0, 0)
+	}
+	pos := len(buf)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v.data)/v.elemSize))
+	return append(buf, v.data...), pos
+}
+
+// fbTableVector is a vector of offsets to tables.
+type fbTableVector []fbTable
+
+func (v fbTableVector) place(buf []byte) ([]byte, int) {
+	buf = padTo(buf, 4)
+	pos := len(buf)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+	slots := len(buf)
+	buf = append(buf, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		var childPos int
+		buf, childPos = t.place(buf)
+		p := slots + 4*i
+		binary.LittleEndian.PutUint32(buf[p:], uint32(childPos-p))
+	}
+	return buf, pos
+}
+
+// encodeFlatbuffer returns a finished buffer whose root is the given table.
+func encodeFlatbuffer(root fbTable) []byte {
+	buf := make([]byte, 4, 256)
+	buf, rootPos := root.place(buf)
+	binary.LittleEndian.PutUint32(buf, uint32(rootPos))
+	return buf
+}
@@ -6,10 +6,12 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 
 	"crownet/common"
+	"crownet/neuron"
 	"crownet/synaptic"
 )
 
@@ -32,11 +34,24 @@ func SaveNetworkWeightsToJSON(networkWeights *synaptic.NetworkWeights, filePath
 		return fmt.Errorf("cannot save nil NetworkWeights")
 	}
 	// BUG-STORAGE-001: Use networkWeights.GetAllWeights() to get the map for serialization
-	weightsToSerialize := networkWeights.GetAllWeights()
+	data, err := marshalWeightsJSON(networkWeights.GetAllWeights())
+	if err != nil {
+		return err
+	}
 
+	err = os.WriteFile(filePath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write JSON weights file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// marshalWeightsJSON converts weights to the indented, string-keyed JSON written by
+// SaveNetworkWeightsToJSON.
+func marshalWeightsJSON(weights map[common.NeuronID]synaptic.WeightMap) ([]byte, error) {
 	// Prepare a structure with string keys for JSON serialization.
 	serializableWeights := make(map[string]map[string]float64)
-	for fromID, toMap := range weightsToSerialize {
+	for fromID, toMap := range weights {
 		strFromID := strconv.FormatInt(int64(fromID), 10)
 		serializableWeights[strFromID] = make(map[string]float64)
 		for toID, weightVal := range toMap {
@@ -47,14 +62,19 @@ func SaveNetworkWeightsToJSON(networkWeights *synaptic.NetworkWeights, filePath
 
 	data, err := json.MarshalIndent(serializableWeights, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to serialize weights to JSON: %w", err)
+		return nil, fmt.Errorf("failed to serialize weights to JSON: %w", err)
 	}
+	return data, nil
+}
 
-	err = os.WriteFile(filePath, data, 0644)
+// WriteWeightsJSON writes weights to w in the format of SaveNetworkWeightsToJSON.
+func WriteWeightsJSON(w io.Writer, weights map[common.NeuronID]synaptic.WeightMap) error {
+	data, err := marshalWeightsJSON(weights)
 	if err != nil {
-		return fmt.Errorf("failed to write JSON weights file %s: %w", filePath, err)
+		return err
 	}
-	return nil
+	_, err = w.Write(data)
+	return err
 }
 
 // LoadNetworkWeightsFromJSON deserializes network synaptic weights from a JSON file
@@ -81,11 +101,28 @@ func LoadNetworkWeightsFromJSON(filePath string) (map[common.NeuronID]synaptic.W
 		return nil, fmt.Errorf("failed to read JSON weights file %s: %w", filePath, err)
 	}
 
-	serializableWeights := make(map[string]map[string]float64)
-	err = json.Unmarshal(data, &serializableWeights)
+	deserializedMap, err := unmarshalWeightsJSON(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal weights from JSON from %s: %w", filePath, err)
 	}
+	return deserializedMap, nil
+}
+
+// ReadWeightsJSON reads weights in the format of SaveNetworkWeightsToJSON from r.
+func ReadWeightsJSON(r io.Reader) (map[common.NeuronID]synaptic.WeightMap, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalWeightsJSON(data)
+}
+
+// unmarshalWeightsJSON parses string-keyed JSON weights back into numeric neuron IDs.
+func unmarshalWeightsJSON(data []byte) (map[common.NeuronID]synaptic.WeightMap, error) {
+	serializableWeights := make(map[string]map[string]float64)
+	if err := json.Unmarshal(data, &serializableWeights); err != nil {
+		return nil, err
+	}
 
 	// BUG-STORAGE-001: Changed to return the map directly instead of attempting to create NetworkWeights struct.
 	deserializedMap := make(map[common.NeuronID]synaptic.WeightMap)
@@ -108,3 +145,72 @@ func LoadNetworkWeightsFromJSON(filePath string) (map[common.NeuronID]synaptic.W
 	}
 	return deserializedMap, nil
 }
+
+// NeuronParamsPath returns the path of the neuron parameters file that accompanies
+// the weights file at weightsPath. It is derived from the full file name (e.g.
+// "weights.bin" -> "weights.bin.neurons.json"), so weight files that differ only
+// in format or compression never share a parameters file.
+func NeuronParamsPath(weightsPath string) string {
+	return weightsPath + ".neurons.json"
+}
+
+// SaveNeuronParametersToJSON writes per-neuron intrinsic parameters to a JSON file
+// at filePath. As with weights, neuron IDs are written as string keys.
+// File permissions are set to 0644.
+//
+// Parameters:
+//   - params: The per-neuron parameters to save, keyed by neuron ID.
+//   - filePath: The path to the file where the JSON data will be written.
+//
+// Returns:
+//   - error: An error if serialization or file writing fails, nil otherwise.
+func SaveNeuronParametersToJSON(params map[common.NeuronID]neuron.Parameters, filePath string) error {
+	serializable := make(map[string]neuron.Parameters, len(params))
+	for id, p := range params {
+		serializable[strconv.FormatInt(int64(id), 10)] = p
+	}
+
+	data, err := json.MarshalIndent(serializable, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize neuron parameters to JSON: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON neuron parameters file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadNeuronParametersFromJSON reads per-neuron intrinsic parameters written by
+// SaveNeuronParametersToJSON.
+//
+// Parameters:
+//   - filePath: The path to the JSON file containing the parameters.
+//
+// Returns:
+//   - map[common.NeuronID]neuron.Parameters: The loaded parameters keyed by neuron ID.
+//   - error: An error if file reading, JSON unmarshalling, or NeuronID parsing fails.
+//     Returns an error wrapping os.ErrNotExist if the file is not found.
+func LoadNeuronParametersFromJSON(filePath string) (map[common.NeuronID]neuron.Parameters, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("JSON neuron parameters file %s not found: %w", filePath, err)
+		}
+		return nil, fmt.Errorf("failed to read JSON neuron parameters file %s: %w", filePath, err)
+	}
+
+	serializable := make(map[string]neuron.Parameters)
+	if err := json.Unmarshal(data, &serializable); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal neuron parameters from JSON from %s: %w", filePath, err)
+	}
+
+	params := make(map[common.NeuronID]neuron.Parameters, len(serializable))
+	for strID, p := range serializable {
+		idVal, errConv := strconv.ParseInt(strID, 10, 64)
+		if errConv != nil {
+			return nil, fmt.Errorf("invalid neuron ID in JSON '%s': %w", strID, errConv)
+		}
+		params[common.NeuronID(idVal)] = p
+	}
+	return params, nil
+}
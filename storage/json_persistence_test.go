@@ -0,0 +1,26 @@
+package storage
+
+import "testing"
+
+func TestNeuronParamsPath(t *testing.T) {
+	tests := []struct {
+		weightsPath string
+		want        string
+	}{
+		{"weights.json", "weights.json.neurons.json"},
+		{"weights.bin", "weights.bin.neurons.json"},
+		{"weights.bin.gz", "weights.bin.gz.neurons.json"},
+		{"dir/weights.json.gz", "dir/weights.json.gz.neurons.json"},
+	}
+	seen := make(map[string]string)
+	for _, tt := range tests {
+		got := NeuronParamsPath(tt.weightsPath)
+		if got != tt.want {
+			t.Errorf("NeuronParamsPath(%q) = %q, want %q", tt.weightsPath, got, tt.want)
+		}
+		if other, dup := seen[got]; dup {
+			t.Errorf("NeuronParamsPath(%q) and NeuronParamsPath(%q) share %q", tt.weightsPath, other, got)
+		}
+		seen[got] = tt.weightsPath
+	}
+}
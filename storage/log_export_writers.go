@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/neuron"
+)
+
+// columnKind is how a log column is scanned from SQLite and represented on export.
+type columnKind int
+
+const (
+	columnInt         columnKind = iota // INTEGER, exported as int64.
+	columnFloat                         // REAL, exported as float64.
+	columnText                          // TEXT or DATETIME, exported as string.
+	columnPoint                         // JSON array stored as TEXT (Position, Velocity), exported as exportPoint.
+	columnNeuronType                    // neuron.Type stored as INTEGER, exported by name.
+	columnNeuronState                   // neuron.State stored as INTEGER, exported by name.
+)
+
+// exportColumn names one exported column and its kind.
+type exportColumn struct {
+	name string
+	kind columnKind
+}
+
+// exportPoint is a vector column as stored in the log: a JSON array of numbers.
+type exportPoint string
+
+// arrowRowBatch is the number of rows per Arrow record batch.
+const arrowRowBatch = 64 * 1024
+
+// scanTarget returns a value to pass to rows.Scan for a column of this kind.
+func (k columnKind) scanTarget() any {
+	switch k {
+	case columnInt, columnNeuronType, columnNeuronState:
+		return new(sql.NullInt64)
+	case columnFloat:
+		return new(sql.NullFloat64)
+	default:
+		return new(sql.NullString)
+	}
+}
+
+// value converts a scanned target into the exported value: nil for NULL, otherwise
+// int64, float64, string or exportPoint depending on the kind.
+func (k columnKind) value(target any) any {
+	switch t := target.(type) {
+	case *sql.NullInt64:
+		if !t.Valid {
+			return nil
+		}
+		switch k {
+		case columnNeuronType:
+			return neuron.Type(t.Int64).String()
+		case columnNeuronState:
+			return neuron.State(t.Int64).String()
+		}
+		return t.Int64
+	case *sql.NullFloat64:
+		if !t.Valid {
+			return nil
+		}
+		return t.Float64
+	case *sql.NullString:
+		if !t.Valid {
+			return nil
+		}
+		if k == columnPoint {
+			return exportPoint(t.String)
+		}
+		return t.String
+	}
+	return nil
+}
+
+// rowWriter writes exported rows in one output format.
+type rowWriter interface {
+	writeHeader(columns []exportColumn) error
+	writeRow(values []any) error
+	close() error // Flushes buffered output; does not close the underlying writer.
+}
+
+// newRowWriter returns the writer for format (one of config.SupportedLogFormats).
+func newRowWriter(format string, out io.Writer) rowWriter {
+	switch format {
+	case config.LogFormatNDJSON:
+		return &ndjsonRowWriter{w: bufio.NewWriter(out)}
+	case config.LogFormatArrow:
+		return &arrowRowWriter{out: out}
+	default:
+		return &csvRowWriter{w: csv.NewWriter(out)}
+	}
+}
+
+// csvRowWriter writes a header record and one record per row. NULL is an empty
+// cell and vectors are kept as their JSON text.
+type csvRowWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (cw *csvRowWriter) writeHeader(columns []exportColumn) error {
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.name
+	}
+	cw.record = make([]string, len(columns))
+	return cw.w.Write(headers)
+}
+
+func (cw *csvRowWriter) writeRow(values []any) error {
+	for i, v := range values {
+		switch x := v.(type) {
+		case int64:
+			cw.record[i] = strconv.FormatInt(x, 10)
+		case float64:
+			cw.record[i] = strconv.FormatFloat(x, 'f', -1, 64)
+		case string:
+			cw.record[i] = x
+		case exportPoint:
+			cw.record[i] = string(x)
+		default:
+			cw.record[i] = "" // Represent NULL as empty string in CSV
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvRowWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ndjsonRowWriter writes one JSON object per row, with keys in column order.
+// NULL is null and vectors are nested arrays.
+type ndjsonRowWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+	line bytes.Buffer
+}
+
+func (nw *ndjsonRowWriter) writeHeader(columns []exportColumn) error {
+	nw.keys = make([][]byte, len(columns))
+	for i, col := range columns {
+		key, err := json.Marshal(col.name)
+		if err != nil {
+			return err
+		}
+		nw.keys[i] = key
+	}
+	return nil
+}
+
+func (nw *ndjsonRowWriter) writeRow(values []any) error {
+	nw.line.Reset()
+	nw.line.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			nw.line.WriteByte(',')
+		}
+		nw.line.Write(nw.keys[i])
+		nw.line.WriteByte(':')
+		switch x := v.(type) {
+		case nil:
+			nw.line.WriteString("null")
+		case exportPoint:
+			if err := json.Compact(&nw.line, []byte(x)); err != nil {
+				return fmt.Errorf("invalid vector in column %s: %w", nw.keys[i], err)
+			}
+		default:
+			encoded, err := json.Marshal(x)
+			if err != nil {
+				return fmt.Errorf("cannot encode column %s: %w", nw.keys[i], err)
+			}
+			nw.line.Write(encoded)
+		}
+	}
+	nw.line.WriteString("}\n")
+	_, err := nw.w.Write(nw.line.Bytes())
+	return err
+}
+
+func (nw *ndjsonRowWriter) close() error {
+	return nw.w.Flush()
+}
+
+// arrowRowWriter writes an Arrow IPC file. Every column is nullable; vector columns
+// are split into one Float64 column per dimension, named <column>_0 to
+// <column>_<common.PointDimension-1>.
+type arrowRowWriter struct {
+	out     io.Writer
+	columns []exportColumn
+	aw      *arrowFileWriter
+	row     []any
+}
+
+func (arw *arrowRowWriter) writeHeader(columns []exportColumn) error {
+	var fields []arrowField
+	for _, col := range columns {
+		switch col.kind {
+		case columnInt:
+			fields = append(fields, arrowField{col.name, arrowInt64})
+		case columnFloat:
+			fields = append(fields, arrowField{col.name, arrowFloat64})
+		case columnPoint:
+			for d := 0; d < common.PointDimension; d++ {
+				fields = append(fields, arrowField{fmt.Sprintf("%s_%d", col.name, d), arrowFloat64})
+			}
+		default:
+			fields = append(fields, arrowField{col.name, arrowUtf8})
+		}
+	}
+	aw, err := newArrowFileWriter(arw.out, fields, arrowRowBatch)
+	if err != nil {
+		return err
+	}
+	arw.columns, arw.aw = columns, aw
+	arw.row = make([]any, len(fields))
+	return nil
+}
+
+func (arw *arrowRowWriter) writeRow(values []any) error {
+	j := 0
+	for i, v := range values {
+		if arw.columns[i].kind != columnPoint {
+			arw.row[j] = v
+			j++
+			continue
+		}
+		var coords []float64
+		if p, ok := v.(exportPoint); ok {
+			if err := json.Unmarshal([]byte(p), &coords); err != nil {
+				return fmt.Errorf("invalid vector in column %s: %w", arw.columns[i].name, err)
+			}
+			if len(coords) != common.PointDimension {
+				return fmt.Errorf("vector in column %s has %d dimensions, expected %d",
+					arw.columns[i].name, len(coords), common.PointDimension)
+			}
+		}
+		for d := 0; d < common.PointDimension; d++ {
+			arw.row[j] = nil
+			if coords != nil {
+				arw.row[j] = coords[d]
+			}
+			j++
+		}
+	}
+	return arw.aw.appendRow(arw.row)
+}
+
+func (arw *arrowRowWriter) close() error {
+	return arw.aw.close()
+}
@@ -4,17 +4,15 @@ package storage
 
 import (
 	"database/sql"
-	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 
 	// "strings" // Unused import
 	// Import sqlite3 driver
 	_ "github.com/mattn/go-sqlite3"
 
-	"crownet/neuron" // For neuron.Type and neuron.State enums
+	"crownet/config"
 )
 
 // neuronTypeToString maps neuron.Type enum to its string representation
@@ -40,11 +38,14 @@ import (
 // ExportLogData connects to an SQLite database specified by dbPath,
 // reads data from the given tableName, and exports it in the specified format
 // to outputPath. If outputPath is empty, data is written to os.Stdout.
-// Currently, only "csv" format is supported, and valid tableNames are
-// "NetworkSnapshots" and "NeuronStates".
-func ExportLogData(dbPath, tableName, format, outputPath string) error {
-	if format != "csv" {
-		return fmt.Errorf("unsupported format '%s', only 'csv' is currently supported", format)
+// Supported formats are "csv", "ndjson" and "arrow" (see config.SupportedLogFormats),
+// and valid tableNames are "Runs", "NetworkSnapshots", "NeuronStates", "Spikes" and
+// "SynapseSnapshots". If runID is not empty, only rows belonging to that run are exported.
+func ExportLogData(dbPath, tableName, format, outputPath, runID string) error {
+	switch format {
+	case config.LogFormatCSV, config.LogFormatNDJSON, config.LogFormatArrow:
+	default:
+		return fmt.Errorf("unsupported format '%s', supported formats are 'csv', 'ndjson' and 'arrow'", format)
 	}
 
 	db, err := sql.Open("sqlite3", dbPath+"?mode=ro") // Open in read-only mode
@@ -57,7 +58,6 @@ func ExportLogData(dbPath, tableName, format, outputPath string) error {
 		return fmt.Errorf("failed to ping SQLite database at %s: %w", dbPath, err)
 	}
 
-	var writer *csv.Writer
 	var file *os.File
 	var out io.Writer
 
@@ -74,134 +74,203 @@ func ExportLogData(dbPath, tableName, format, outputPath string) error {
 	} else {
 		out = os.Stdout
 	}
-	writer = csv.NewWriter(out)
-	defer writer.Flush()
 
+	var export func(*sql.DB, string) (tableExport, error)
 	switch tableName {
+	case "Runs":
+		export = queryRuns
 	case "NetworkSnapshots":
-		return exportNetworkSnapshots(db, writer)
+		export = queryNetworkSnapshots
 	case "NeuronStates":
-		return exportNeuronStates(db, writer)
+		export = queryNeuronStates
+	case "Spikes":
+		export = querySpikes
+	case "SynapseSnapshots":
+		export = querySynapseSnapshots
 	default:
-		return fmt.Errorf("unsupported table '%s'. Supported tables are 'NetworkSnapshots', 'NeuronStates'", tableName)
+		return fmt.Errorf("unsupported table '%s'. Supported tables are 'Runs', 'NetworkSnapshots', 'NeuronStates', 'Spikes', 'SynapseSnapshots'", tableName)
 	}
-}
 
-// exportNetworkSnapshots exports the NetworkSnapshots table to CSV.
-func exportNetworkSnapshots(db *sql.DB, writer *csv.Writer) error {
-	headers := []string{
-		"SnapshotID", "CycleCount", "Timestamp", "CortisolLevel",
-		"DopamineLevel", "LearningRateModFactor", "SynaptogenesisModFactor",
+	te, err := export(db, runID)
+	if err != nil {
+		return err
+	}
+	if err := writeTable(db, te, newRowWriter(format, out)); err != nil {
+		return err
 	}
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write CSV headers for NetworkSnapshots: %w", err)
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to close output file %s: %w", outputPath, err)
+		}
 	}
+	return nil
+}
 
-	rows, err := db.Query("SELECT SnapshotID, CycleCount, Timestamp, CortisolLevel, DopamineLevel, LearningRateModFactor, SynaptogenesisModFactor FROM NetworkSnapshots ORDER BY SnapshotID")
+// runFilter returns the expression that selects the RunID column of table (NULL for
+// databases written before runs were tracked) and, if runID is not empty, a WHERE
+// clause and arguments restricting rows to that run. prefix qualifies the column
+// name when the query uses a table alias (e.g. "n.").
+func runFilter(db *sql.DB, table, prefix, runID string) (column, where string, args []any, err error) {
+	exists, err := hasColumn(db, table, "RunID")
 	if err != nil {
-		return fmt.Errorf("failed to query NetworkSnapshots: %w", err)
+		return "", "", nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var r [7]sql.NullString // Use NullString to handle potential NULLs gracefully, then convert
-		if err := rows.Scan(&r[0], &r[1], &r[2], &r[3], &r[4], &r[5], &r[6]); err != nil {
-			return fmt.Errorf("failed to scan row from NetworkSnapshots: %w", err)
-		}
-		record := make([]string, len(r))
-		for i, val := range r {
-			if val.Valid {
-				record[i] = val.String
-			} else {
-				record[i] = "" // Represent NULL as empty string in CSV
-			}
-		}
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write CSV record for NetworkSnapshots: %w", err)
+	if !exists {
+		if runID != "" {
+			return "", "", nil, fmt.Errorf("table %s has no RunID column (database written before runs were tracked)", table)
 		}
+		return "NULL", "", nil, nil
+	}
+	column = prefix + "RunID"
+	if runID != "" {
+		return column, "WHERE " + column + " = ?", []any{runID}, nil
 	}
-	return rows.Err() // Check for errors during iteration
+	return column, "", nil, nil
 }
 
-// exportNeuronStates exports the NeuronStates table to CSV.
-func exportNeuronStates(db *sql.DB, writer *csv.Writer) error {
-	headers := []string{
-		"StateID", "SnapshotID", "NeuronID", "Position", "Velocity", "Type", "CurrentState",
-		"AccumulatedPotential", "BaseFiringThreshold", "CurrentFiringThreshold",
-		"LastFiredCycle", "CyclesInCurrentState",
-	}
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write CSV headers for NeuronStates: %w", err)
+// tableExport is the query that exports one log table and the columns it selects,
+// in order.
+type tableExport struct {
+	table   string
+	columns []exportColumn
+	query   string
+	args    []any
+}
+
+// writeTable runs the export query and streams its rows to w.
+func writeTable(db *sql.DB, te tableExport, w rowWriter) error {
+	if err := w.writeHeader(te.columns); err != nil {
+		return fmt.Errorf("failed to write headers for %s: %w", te.table, err)
 	}
 
-	rows, err := db.Query(`SELECT StateID, SnapshotID, NeuronID, Position, Velocity, Type, CurrentState,
-                                AccumulatedPotential, BaseFiringThreshold, CurrentFiringThreshold,
-                                LastFiredCycle, CyclesInCurrentState
-                         FROM NeuronStates ORDER BY StateID`)
+	rows, err := db.Query(te.query, te.args...)
 	if err != nil {
-		return fmt.Errorf("failed to query NeuronStates: %w", err)
+		return fmt.Errorf("failed to query %s: %w", te.table, err)
 	}
 	defer rows.Close()
 
+	dest := make([]any, len(te.columns))
+	for i, col := range te.columns {
+		dest[i] = col.kind.scanTarget()
+	}
+	values := make([]any, len(te.columns))
 	for rows.Next() {
-		var stateID, snapshotID, neuronID, typeInt, currentStateInt, lastFiredCycle, cyclesInCurrentState sql.NullInt64
-		var position, velocity sql.NullString
-		var accPot, baseThr, currThr sql.NullFloat64
-
-		if err := rows.Scan(
-			&stateID, &snapshotID, &neuronID, &position, &velocity,
-			&typeInt, &currentStateInt, &accPot, &baseThr, &currThr,
-			&lastFiredCycle, &cyclesInCurrentState,
-		); err != nil {
-			return fmt.Errorf("failed to scan row from NeuronStates: %w", err)
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan row from %s: %w", te.table, err)
 		}
-
-		typeStr := ""
-		if typeInt.Valid {
-			typeStr = neuron.Type(typeInt.Int64).String()
+		for i, col := range te.columns {
+			values[i] = col.kind.value(dest[i])
 		}
-
-		currentStateStr := ""
-		if currentStateInt.Valid {
-			currentStateStr = neuron.State(currentStateInt.Int64).String()
+		if err := w.writeRow(values); err != nil {
+			return fmt.Errorf("failed to write record for %s: %w", te.table, err)
 		}
+	}
+	if err := rows.Err(); err != nil { // Check for errors during iteration
+		return fmt.Errorf("failed to read rows from %s: %w", te.table, err)
+	}
+	if err := w.close(); err != nil {
+		return fmt.Errorf("failed to finish export of %s: %w", te.table, err)
+	}
+	return nil
+}
 
-		record := []string{
-			intToString(stateID), intToString(snapshotID), intToString(neuronID),
-			nullStringToString(position), nullStringToString(velocity),
-			typeStr, currentStateStr,
-			floatToString(accPot), floatToString(baseThr), floatToString(currThr),
-			intToString(lastFiredCycle), intToString(cyclesInCurrentState),
-		}
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write CSV record for NeuronStates: %w", err)
-		}
+// queryRuns exports the Runs table, ordered by start time.
+func queryRuns(_ *sql.DB, runID string) (tableExport, error) {
+	query := "SELECT RunID, Mode, StartTime, EndTime, Seed, SoftwareVersion, Config FROM Runs"
+	var args []any
+	if runID != "" {
+		query += " WHERE RunID = ?"
+		args = append(args, runID)
 	}
-	return rows.Err()
+	return tableExport{
+		table: "Runs",
+		columns: []exportColumn{
+			{"RunID", columnText}, {"Mode", columnText}, {"StartTime", columnText}, {"EndTime", columnText},
+			{"Seed", columnInt}, {"SoftwareVersion", columnText}, {"Config", columnText},
+		},
+		query: query + " ORDER BY StartTime, RunID",
+		args:  args,
+	}, nil
 }
 
-// Helper functions to convert sql.Null types to string for CSV
-func nullStringToString(ns sql.NullString) string {
-	if ns.Valid {
-		return ns.String
+// queryNetworkSnapshots exports the NetworkSnapshots table.
+func queryNetworkSnapshots(db *sql.DB, runID string) (tableExport, error) {
+	runColumn, where, args, err := runFilter(db, "NetworkSnapshots", "", runID)
+	if err != nil {
+		return tableExport{}, err
 	}
-	return ""
+	return tableExport{
+		table: "NetworkSnapshots",
+		columns: []exportColumn{
+			{"SnapshotID", columnInt}, {"CycleCount", columnInt}, {"Timestamp", columnText},
+			{"CortisolLevel", columnFloat}, {"DopamineLevel", columnFloat},
+			{"LearningRateModFactor", columnFloat}, {"SynaptogenesisModFactor", columnFloat},
+			{"RunID", columnText},
+		},
+		query: "SELECT SnapshotID, CycleCount, Timestamp, CortisolLevel, DopamineLevel, LearningRateModFactor, SynaptogenesisModFactor, " +
+			runColumn + " FROM NetworkSnapshots " + where + " ORDER BY SnapshotID",
+		args: args,
+	}, nil
 }
 
-func intToString(ni sql.NullInt64) string {
-	if ni.Valid {
-		return strconv.FormatInt(ni.Int64, 10)
+// queryNeuronStates exports the NeuronStates table. Type and CurrentState are
+// exported by name; Position and Velocity are vectors.
+func queryNeuronStates(db *sql.DB, runID string) (tableExport, error) {
+	runColumn, where, args, err := runFilter(db, "NeuronStates", "", runID)
+	if err != nil {
+		return tableExport{}, err
 	}
-	return ""
+	return tableExport{
+		table: "NeuronStates",
+		columns: []exportColumn{
+			{"StateID", columnInt}, {"SnapshotID", columnInt}, {"NeuronID", columnInt},
+			{"Position", columnPoint}, {"Velocity", columnPoint},
+			{"Type", columnNeuronType}, {"CurrentState", columnNeuronState},
+			{"AccumulatedPotential", columnFloat}, {"BaseFiringThreshold", columnFloat},
+			{"CurrentFiringThreshold", columnFloat},
+			{"LastFiredCycle", columnInt}, {"CyclesInCurrentState", columnInt},
+			{"RunID", columnText},
+		},
+		query: `SELECT StateID, SnapshotID, NeuronID, Position, Velocity, Type, CurrentState,
+                       AccumulatedPotential, BaseFiringThreshold, CurrentFiringThreshold,
+                       LastFiredCycle, CyclesInCurrentState, ` + runColumn + `
+                FROM NeuronStates ` + where + ` ORDER BY StateID`,
+		args: args,
+	}, nil
 }
 
-func floatToString(nf sql.NullFloat64) string {
-	if nf.Valid {
-		return strconv.FormatFloat(nf.Float64, 'f', -1, 64) // Default precision
+// querySpikes exports the Spikes table, ordered by run, cycle and neuron so that
+// each run reads as a raster.
+func querySpikes(db *sql.DB, runID string) (tableExport, error) {
+	_, where, args, err := runFilter(db, "Spikes", "", runID)
+	if err != nil {
+		return tableExport{}, err
 	}
-	return ""
+	return tableExport{
+		table:   "Spikes",
+		columns: []exportColumn{{"RunID", columnText}, {"Cycle", columnInt}, {"NeuronID", columnInt}},
+		query:   "SELECT RunID, Cycle, NeuronID FROM Spikes " + where + " ORDER BY RunID, Cycle, NeuronID",
+		args:    args,
+	}, nil
 }
 
-// Placeholder for main.go or cli.Orchestrator to call RunExport.
-// This file assumes it's part of the 'storage' package.
-// The actual call will be from cli.runLogUtilMode().
+// querySynapseSnapshots exports the SynapseSnapshots table, joined with
+// NetworkSnapshots so that each row carries the cycle it was taken at. Rows are
+// filtered by run through the run of their network snapshot.
+func querySynapseSnapshots(db *sql.DB, runID string) (tableExport, error) {
+	_, where, args, err := runFilter(db, "NetworkSnapshots", "n.", runID)
+	if err != nil {
+		return tableExport{}, err
+	}
+	return tableExport{
+		table: "SynapseSnapshots",
+		columns: []exportColumn{
+			{"SnapshotID", columnInt}, {"CycleCount", columnInt}, {"PreNeuronID", columnInt},
+			{"PostNeuronID", columnInt}, {"Weight", columnFloat}, {"IsDelta", columnInt},
+		},
+		query: `SELECT s.SnapshotID, n.CycleCount, s.PreNeuronID, s.PostNeuronID, s.Weight, s.IsDelta
+                FROM SynapseSnapshots s LEFT JOIN NetworkSnapshots n ON n.SnapshotID = s.SnapshotID
+                ` + where + ` ORDER BY s.SnapshotID, s.PreNeuronID, s.PostNeuronID`,
+		args: args,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"crownet/common"
+	"crownet/config"
+)
+
+// countNDJSONStates checks every line of an NDJSON export of NeuronStates and returns
+// the number of lines.
+func countNDJSONStates(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open NDJSON export: %v", err)
+	}
+	defer f.Close()
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var row struct {
+			NeuronID *int64    `json:"NeuronID"`
+			Position []float64 `json:"Position"`
+			Type     string    `json:"Type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("NDJSON line %d is not a valid object: %v", lines+1, err)
+		}
+		if row.NeuronID == nil || row.Type == "" || len(row.Position) != common.PointDimension {
+			t.Fatalf("NDJSON line %d is incomplete: %s", lines+1, scanner.Text())
+		}
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read NDJSON export: %v", err)
+	}
+	return lines
+}
+
+func TestExportLogData_NDJSON(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "export.db")
+	firstRun := newTestLog(t, dbPath, 4, 2) // Snapshots at cycles 2 and 4.
+	newTestLog(t, dbPath, 3, 1)             // A second run sharing the database.
+
+	tests := []struct {
+		name  string
+		runID string
+		want  int
+	}{
+		{"one run", firstRun, 2 * loggerTestNeurons},
+		{"all runs", "", 5 * loggerTestNeurons},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(dir, tt.name+".ndjson")
+			if err := ExportLogData(dbPath, "NeuronStates", config.LogFormatNDJSON, outPath, tt.runID); err != nil {
+				t.Fatalf("ExportLogData() error = %v", err)
+			}
+			if lines := countNDJSONStates(t, outPath); lines != tt.want {
+				t.Errorf("NDJSON export has %d lines, want %d", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportLogData_Arrow(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "export.db")
+	newTestLog(t, dbPath, 4, 2)
+
+	arrowPath := filepath.Join(dir, "states.arrow")
+	if err := ExportLogData(dbPath, "NeuronStates", config.LogFormatArrow, arrowPath, ""); err != nil {
+		t.Fatalf("ExportLogData() error = %v", err)
+	}
+	data, err := os.ReadFile(arrowPath)
+	if err != nil {
+		t.Fatalf("Failed to read Arrow export: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("ARROW1")) || !bytes.HasSuffix(data, []byte("ARROW1")) {
+		t.Errorf("Arrow export is not framed by the ARROW1 magic (%d bytes)", len(data))
+	}
+	if !bytes.Contains(data, []byte(fmt.Sprintf("Position_%d", common.PointDimension-1))) {
+		t.Errorf("Arrow schema does not contain the split Position columns")
+	}
+}
+
+func TestExportLogData_RejectsInvalidArguments(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "export.db")
+	newTestLog(t, dbPath, 2, 1)
+
+	tests := []struct {
+		name, table, format string
+	}{
+		{"unknown format", "NeuronStates", "parquet"},
+		{"unknown table", "Neurons", config.LogFormatCSV},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ExportLogData(dbPath, tt.table, tt.format, filepath.Join(dir, "out"), ""); err == nil {
+				t.Errorf("ExportLogData(%q, %q): expected an error", tt.table, tt.format)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"crownet/neuron"
+)
+
+// ValueStats summarises one numeric column over the snapshots of a log.
+type ValueStats struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// LogStats is a summary of an SQLite log, as computed by ComputeLogStats.
+type LogStats struct {
+	DBPath    string `json:"db_path"`
+	RunID     string `json:"run_id,omitempty"` // Run the statistics are restricted to; empty for all runs.
+	Snapshots int    `json:"snapshots"`
+	// FirstCycle and LastCycle are the cycle range covered by the snapshots (zero if there are none).
+	FirstCycle int64 `json:"first_cycle"`
+	LastCycle  int64 `json:"last_cycle"`
+
+	Cortisol                ValueStats `json:"cortisol"`
+	Dopamine                ValueStats `json:"dopamine"`
+	LearningRateModFactor   ValueStats `json:"learning_rate_mod_factor"`
+	SynaptogenesisModFactor ValueStats `json:"synaptogenesis_mod_factor"`
+
+	// FiringCounts counts, per neuron type name, the NeuronStates rows in the Firing
+	// state, i.e. the neurons caught firing at a snapshot. Types present in the log
+	// but never firing have a count of zero.
+	FiringCounts map[string]int `json:"firing_counts"`
+
+	// MeanDisplacement is the mean Euclidean distance between each neuron's position
+	// in the first and in the last snapshot, over the DisplacedNeurons neurons that
+	// appear in both.
+	MeanDisplacement float64 `json:"mean_displacement"`
+	DisplacedNeurons int     `json:"displaced_neurons"`
+}
+
+// ComputeLogStats summarises the SQLite log at dbPath: the number of snapshots and
+// their cycle range, min/mean/max of the neurochemical levels and modulation factors,
+// firing counts per neuron type and the mean neuron displacement between the first
+// and last snapshot. If runID is not empty, only that run is considered; otherwise
+// the whole database is, and the first and last snapshots may belong to different runs.
+func ComputeLogStats(dbPath, runID string) (*LogStats, error) {
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database at %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping SQLite database at %s: %w", dbPath, err)
+	}
+
+	stats := &LogStats{DBPath: dbPath, RunID: runID, FiringCounts: make(map[string]int)}
+	firstSnapshot, lastSnapshot, err := stats.readSnapshotStats(db, runID)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Snapshots == 0 {
+		return stats, nil
+	}
+	if err := stats.readFiringCounts(db, runID); err != nil {
+		return nil, err
+	}
+	if err := stats.readDisplacement(db, firstSnapshot, lastSnapshot); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// readSnapshotStats fills the snapshot count, cycle range and neurochemical statistics
+// and returns the IDs of the first and last snapshot.
+func (s *LogStats) readSnapshotStats(db *sql.DB, runID string) (first, last int64, err error) {
+	_, where, args, err := runFilter(db, "NetworkSnapshots", "", runID)
+	if err != nil {
+		return 0, 0, err
+	}
+	var firstID, lastID, firstCycle, lastCycle sql.NullInt64
+	var levels [12]sql.NullFloat64
+	dest := []any{&s.Snapshots, &firstID, &lastID, &firstCycle, &lastCycle}
+	for i := range levels {
+		dest = append(dest, &levels[i])
+	}
+	err = db.QueryRow(`SELECT COUNT(*), MIN(SnapshotID), MAX(SnapshotID), MIN(CycleCount), MAX(CycleCount),
+                              MIN(CortisolLevel), AVG(CortisolLevel), MAX(CortisolLevel),
+                              MIN(DopamineLevel), AVG(DopamineLevel), MAX(DopamineLevel),
+                              MIN(LearningRateModFactor), AVG(LearningRateModFactor), MAX(LearningRateModFactor),
+                              MIN(SynaptogenesisModFactor), AVG(SynaptogenesisModFactor), MAX(SynaptogenesisModFactor)
+                       FROM NetworkSnapshots `+where, args...).Scan(dest...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query NetworkSnapshots statistics: %w", err)
+	}
+	s.FirstCycle, s.LastCycle = firstCycle.Int64, lastCycle.Int64
+	for i, vs := range []*ValueStats{&s.Cortisol, &s.Dopamine, &s.LearningRateModFactor, &s.SynaptogenesisModFactor} {
+		*vs = ValueStats{Min: levels[3*i].Float64, Mean: levels[3*i+1].Float64, Max: levels[3*i+2].Float64}
+	}
+	return firstID.Int64, lastID.Int64, nil
+}
+
+// readFiringCounts fills FiringCounts from the NeuronStates rows.
+func (s *LogStats) readFiringCounts(db *sql.DB, runID string) error {
+	_, where, args, err := runFilter(db, "NeuronStates", "", runID)
+	if err != nil {
+		return err
+	}
+	rows, err := db.Query(`SELECT Type, SUM(CASE WHEN CurrentState = ? THEN 1 ELSE 0 END)
+                           FROM NeuronStates `+where+` GROUP BY Type ORDER BY Type`,
+		append([]any{int(neuron.Firing)}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to query firing counts from NeuronStates: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var typeInt sql.NullInt64
+		var count int
+		if err := rows.Scan(&typeInt, &count); err != nil {
+			return fmt.Errorf("failed to scan firing counts from NeuronStates: %w", err)
+		}
+		if typeInt.Valid {
+			s.FiringCounts[neuron.Type(typeInt.Int64).String()] += count
+		}
+	}
+	return rows.Err()
+}
+
+// readDisplacement fills MeanDisplacement and DisplacedNeurons from the neuron
+// positions stored with the two given snapshots.
+func (s *LogStats) readDisplacement(db *sql.DB, firstSnapshot, lastSnapshot int64) error {
+	start, err := readPositions(db, firstSnapshot)
+	if err != nil {
+		return err
+	}
+	end, err := readPositions(db, lastSnapshot)
+	if err != nil {
+		return err
+	}
+	var total float64
+	for id, p0 := range start {
+		p1, ok := end[id]
+		if !ok || len(p0) != len(p1) {
+			continue
+		}
+		var sumSq float64
+		for d := range p0 {
+			diff := p1[d] - p0[d]
+			sumSq += diff * diff
+		}
+		total += math.Sqrt(sumSq)
+		s.DisplacedNeurons++
+	}
+	if s.DisplacedNeurons > 0 {
+		s.MeanDisplacement = total / float64(s.DisplacedNeurons)
+	}
+	return nil
+}
+
+// readPositions returns the position of every neuron stored with a snapshot, keyed by neuron ID.
+func readPositions(db *sql.DB, snapshotID int64) (map[int64][]float64, error) {
+	rows, err := db.Query("SELECT NeuronID, Position FROM NeuronStates WHERE SnapshotID = ?", snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions of snapshot %d: %w", snapshotID, err)
+	}
+	defer rows.Close()
+	positions := make(map[int64][]float64)
+	for rows.Next() {
+		var id int64
+		var position sql.NullString
+		if err := rows.Scan(&id, &position); err != nil {
+			return nil, fmt.Errorf("failed to scan position from snapshot %d: %w", snapshotID, err)
+		}
+		if !position.Valid {
+			continue
+		}
+		var coords []float64
+		if err := json.Unmarshal([]byte(position.String), &coords); err != nil {
+			return nil, fmt.Errorf("invalid position of neuron %d in snapshot %d: %w", id, snapshotID, err)
+		}
+		positions[id] = coords
+	}
+	return positions, rows.Err()
+}
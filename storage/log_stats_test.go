@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeLogStats(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "stats.db")
+	firstRun := newTestLog(t, dbPath, 4, 2)  // Snapshots at cycles 2 and 4.
+	secondRun := newTestLog(t, dbPath, 9, 3) // Snapshots at cycles 3, 6 and 9.
+
+	tests := []struct {
+		name                  string
+		runID                 string
+		snapshots             int
+		firstCycle, lastCycle int64
+	}{
+		{"first run", firstRun, 2, 2, 4},
+		{"second run", secondRun, 3, 3, 9},
+		{"all runs", "", 5, 2, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeLogStats(dbPath, tt.runID)
+			if err != nil {
+				t.Fatalf("ComputeLogStats() error = %v", err)
+			}
+			if stats.Snapshots != tt.snapshots || stats.FirstCycle != tt.firstCycle || stats.LastCycle != tt.lastCycle {
+				t.Errorf("got %d snapshots over cycles %d-%d, want %d over cycles %d-%d",
+					stats.Snapshots, stats.FirstCycle, stats.LastCycle, tt.snapshots, tt.firstCycle, tt.lastCycle)
+			}
+			if stats.Cortisol.Min > stats.Cortisol.Mean || stats.Cortisol.Mean > stats.Cortisol.Max {
+				t.Errorf("Cortisol statistics are not ordered: %+v", stats.Cortisol)
+			}
+			firing := 0
+			for _, count := range stats.FiringCounts {
+				firing += count
+			}
+			if states := tt.snapshots * loggerTestNeurons; firing > states {
+				t.Errorf("firing counts sum to %d, more than the %d logged neuron states", firing, states)
+			}
+			if stats.DisplacedNeurons != loggerTestNeurons || stats.MeanDisplacement < 0 {
+				t.Errorf("displacement over %d neurons = %f, want %d neurons and a non-negative mean",
+					stats.DisplacedNeurons, stats.MeanDisplacement, loggerTestNeurons)
+			}
+		})
+	}
+
+	stats, err := ComputeLogStats(dbPath, "no-such-run")
+	if err != nil {
+		t.Fatalf("ComputeLogStats() with an unknown run error = %v", err)
+	}
+	if stats.Snapshots != 0 || stats.DisplacedNeurons != 0 {
+		t.Errorf("unknown run: got %d snapshots and %d displaced neurons, want none", stats.Snapshots, stats.DisplacedNeurons)
+	}
+}
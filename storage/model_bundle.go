@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"crownet/common"
+	"crownet/config"
+	"crownet/network"
+	"crownet/neuron"
+	"crownet/synaptic"
+)
+
+// ModelFormatVersion is the version of the model bundle format written by
+// SaveModelBundle. LoadModelBundle rejects bundles with a different version.
+const ModelFormatVersion = 1
+
+// ModelBundle is a self-describing saved network: the neurons with their types,
+// positions and intrinsic parameters, every synapse, and the simulation parameters
+// the network was built and trained with. Unlike a weights file it does not depend
+// on the seed or neuron count to reproduce the layout.
+type ModelBundle struct {
+	FormatVersion   int                         `json:"format_version"`
+	SoftwareVersion string                      `json:"software_version"`
+	CreatedAt       time.Time                   `json:"created_at"`
+	Seed            int64                       `json:"seed"`  // Seed of the run that saved the model (informational).
+	Cycle           common.CycleCount           `json:"cycle"` // Network cycle count when the model was saved.
+	SimParams       config.SimulationParameters `json:"sim_params"`
+	Neurons         []ModelNeuron               `json:"neurons"`
+	Synapses        []ModelSynapse              `json:"synapses"`
+}
+
+// ModelNeuron is one neuron of a ModelBundle. Type is the neuron.Type name.
+type ModelNeuron struct {
+	ID         common.NeuronID   `json:"id"`
+	Type       string            `json:"type"`
+	Position   common.Point      `json:"position"`
+	Parameters neuron.Parameters `json:"parameters"`
+}
+
+// ModelSynapse is one directed synapse of a ModelBundle.
+type ModelSynapse struct {
+	From   common.NeuronID       `json:"from"`
+	To     common.NeuronID       `json:"to"`
+	Weight common.SynapticWeight `json:"weight"`
+}
+
+// NewModelBundle captures the current layout, weights and simulation parameters
+// of net. Neurons and synapses are sorted by ID so that equal networks produce
+// identical files.
+func NewModelBundle(net *network.CrowNet) (*ModelBundle, error) {
+	if net == nil || net.SimParams == nil || net.SynapticWeights == nil {
+		return nil, fmt.Errorf("cannot build model bundle from an uninitialized network")
+	}
+	bundle := &ModelBundle{
+		FormatVersion:   ModelFormatVersion,
+		SoftwareVersion: SoftwareVersion(),
+		CreatedAt:       time.Now().UTC(),
+		Seed:            net.SimParams.Cli.Seed,
+		Cycle:           net.CycleCount,
+		SimParams:       net.SimParams.SimParams,
+	}
+	for _, spec := range net.Layout() {
+		bundle.Neurons = append(bundle.Neurons, ModelNeuron{
+			ID:         spec.ID,
+			Type:       spec.Type.String(),
+			Position:   spec.Position,
+			Parameters: spec.Parameters,
+		})
+	}
+	for fromID, toMap := range net.SynapticWeights.GetAllWeights() {
+		for toID, weight := range toMap {
+			bundle.Synapses = append(bundle.Synapses, ModelSynapse{From: fromID, To: toID, Weight: weight})
+		}
+	}
+	sort.Slice(bundle.Synapses, func(i, j int) bool {
+		a, b := bundle.Synapses[i], bundle.Synapses[j]
+		return a.From < b.From || (a.From == b.From && a.To < b.To)
+	})
+	return bundle, nil
+}
+
+// Layout converts the bundle into the neuron specs and weights expected by
+// network.NewCrowNetFromLayout.
+func (b *ModelBundle) Layout() ([]network.NeuronSpec, map[common.NeuronID]synaptic.WeightMap, error) {
+	layout := make([]network.NeuronSpec, 0, len(b.Neurons))
+	for _, n := range b.Neurons {
+		t, err := neuron.ParseType(n.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("neuron %d: %w", n.ID, err)
+		}
+		layout = append(layout, network.NeuronSpec{ID: n.ID, Type: t, Position: n.Position, Parameters: n.Parameters})
+	}
+	weights := make(map[common.NeuronID]synaptic.WeightMap)
+	for _, s := range b.Synapses {
+		if weights[s.From] == nil {
+			weights[s.From] = make(synaptic.WeightMap)
+		}
+		weights[s.From][s.To] = s.Weight
+	}
+	return layout, weights, nil
+}
+
+// SaveModelBundle writes the bundle as indented JSON to filePath.
+// File permissions are set to 0644.
+func SaveModelBundle(bundle *ModelBundle, filePath string) error {
+	if bundle == nil {
+		return fmt.Errorf("cannot save nil model bundle")
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize model bundle to JSON: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write model bundle %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadModelBundle reads a bundle written by SaveModelBundle and checks its format
+// version. Returns an error wrapping os.ErrNotExist if the file is not found.
+func LoadModelBundle(filePath string) (*ModelBundle, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("model bundle %s not found: %w", filePath, err)
+		}
+		return nil, fmt.Errorf("failed to read model bundle %s: %w", filePath, err)
+	}
+	var bundle ModelBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal model bundle from %s: %w", filePath, err)
+	}
+	if bundle.FormatVersion != ModelFormatVersion {
+		return nil, fmt.Errorf("model bundle %s has format version %d, this build reads version %d",
+			filePath, bundle.FormatVersion, ModelFormatVersion)
+	}
+	if len(bundle.Neurons) == 0 {
+		return nil, fmt.Errorf("model bundle %s contains no neurons", filePath)
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"crownet/config"
+)
+
+// RunInfo describes one run recorded in the 'Runs' table of an SQLite log.
+type RunInfo struct {
+	RunID           string    // Identifier referenced by the run's snapshots, neuron states and spikes.
+	Mode            string    // Operation mode of the run (e.g. "sim", "expose").
+	StartTime       time.Time // When the logger recorded the run.
+	EndTime         time.Time // When the logger was closed; zero if the run did not finish cleanly.
+	Seed            int64     // Random seed of the run.
+	Config          string    // Full AppConfig of the run, encoded as TOML.
+	SoftwareVersion string    // Version of the binary that wrote the run (see SoftwareVersion).
+	Snapshots       int       // Number of NetworkSnapshots rows of the run.
+}
+
+// SoftwareVersion returns the version of the running binary as recorded by the Go
+// toolchain: the module version ("(devel)" for local builds) followed by the VCS
+// revision, and "-dirty" if the working tree had uncommitted changes.
+func SoftwareVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version := info.Main.Version
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" {
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		version += "+" + revision
+		if modified {
+			version += "-dirty"
+		}
+	}
+	return version
+}
+
+// StartRun records this logger's run in the 'Runs' table: its mode, seed, start time,
+// the full configuration and the software version. The end time is filled in by Close.
+func (sl *SQLiteLogger) StartRun(appCfg *config.AppConfig) error {
+	if sl.db == nil {
+		return fmt.Errorf("SQLiteLogger not initialized (db is nil)")
+	}
+	if appCfg == nil {
+		return fmt.Errorf("cannot start run: AppConfig is nil")
+	}
+	var encoded bytes.Buffer
+	if err := toml.NewEncoder(&encoded).Encode(appCfg); err != nil {
+		return fmt.Errorf("failed to encode configuration of run %s: %w", sl.runID, err)
+	}
+	_, err := sl.db.Exec(`INSERT INTO Runs (RunID, Mode, StartTime, Seed, Config, SoftwareVersion)
+                          VALUES (?, ?, ?, ?, ?, ?)`,
+		sl.runID, appCfg.Cli.Mode, time.Now(), appCfg.Cli.Seed, encoded.String(), SoftwareVersion())
+	if err != nil {
+		return fmt.Errorf("failed to insert run %s into Runs: %w", sl.runID, err)
+	}
+	sl.runStarted = true
+	return nil
+}
+
+// endRun records the end time of the run started with StartRun, if any.
+func (sl *SQLiteLogger) endRun() error {
+	if !sl.runStarted {
+		return nil
+	}
+	if _, err := sl.db.Exec(`UPDATE Runs SET EndTime = ? WHERE RunID = ?`, time.Now(), sl.runID); err != nil {
+		return fmt.Errorf("failed to record end time of run %s: %w", sl.runID, err)
+	}
+	sl.runStarted = false
+	return nil
+}
+
+// ListRuns returns the runs recorded in the SQLite database at dbPath, ordered by
+// start time. A database written before runs were tracked yields no runs.
+func ListRuns(dbPath string) ([]RunInfo, error) {
+	db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database at %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping SQLite database at %s: %w", dbPath, err)
+	}
+	exists, err := hasTable(db, "Runs")
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT r.RunID, r.Mode, r.StartTime, r.EndTime, r.Seed, r.Config, r.SoftwareVersion,
+                                 (SELECT COUNT(*) FROM NetworkSnapshots n WHERE n.RunID = r.RunID)
+                          FROM Runs r ORDER BY r.StartTime, r.RunID`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []RunInfo
+	for rows.Next() {
+		var run RunInfo
+		var endTime sql.NullTime
+		var seed sql.NullInt64
+		var cfg, version sql.NullString
+		if err := rows.Scan(&run.RunID, &run.Mode, &run.StartTime, &endTime, &seed, &cfg, &version,
+			&run.Snapshots); err != nil {
+			return nil, fmt.Errorf("failed to scan row from Runs: %w", err)
+		}
+		run.EndTime = endTime.Time
+		run.Seed = seed.Int64
+		run.Config = cfg.String
+		run.SoftwareVersion = version.String
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// hasTable reports whether the database contains a table with the given name.
+func hasTable(db *sql.DB, table string) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	return true, nil
+}
+
+// hasColumn reports whether the given table has a column with the given name.
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
@@ -1,11 +1,18 @@
 package storage
 
 import (
+	"crypto/rand"
 	"database/sql"
-	"encoding/json" // Added for LogNetworkState
+	"encoding/hex"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 
+	"crownet/common"
+	"crownet/config"
 	"crownet/network"
+	"crownet/synaptic"
 
 	// "os"      // Unused
 	// "strings" // Unused
@@ -14,18 +21,58 @@ import (
 	// "crownet/common" // Unused, neuron types are handled via int casting
 )
 
-// SQLiteLogger provides functionality to log network snapshots and neuron states
-// to an SQLite database.
+// spikeFlushRows is the number of buffered spike rows that are handed to the writer
+// as one batch. Writing many cycles in one transaction keeps the per-cycle cost of
+// spike logging to an append in memory.
+const spikeFlushRows = 10000
+
+// spikeRow is a buffered row of the Spikes table.
+type spikeRow struct {
+	cycle    common.CycleCount
+	neuronID common.NeuronID
+}
+
+// SQLiteLogger provides functionality to log network snapshots, neuron states,
+// individual spikes and synaptic weights to an SQLite database.
+//
+// The Log methods copy what they log and hand it to a background goroutine, which
+// writes it with multi-row inserts in WAL mode, so the simulation only waits for the
+// copy. A write that fails is reported by the next Log call or by Close.
 type SQLiteLogger struct {
-	db *sql.DB // db holds the active database connection.
+	db            *sql.DB    // db holds the active database connection.
+	runID         string     // runID identifies the rows written by this logger (see RunID).
+	runStarted    bool       // runStarted is set once StartRun has recorded the run in the Runs table.
+	pendingSpikes []spikeRow // pendingSpikes holds spikes not yet handed to the writer.
+
+	// lastSnapshotCycle is the cycle of the most recent network snapshot queued by this
+	// logger (hasSnapshot is false until the first one).
+	lastSnapshotCycle common.CycleCount
+	hasSnapshot       bool
+	// storedWeights holds the last weight stored for each synapse, used by delta synapse snapshots.
+	storedWeights map[common.NeuronID]synaptic.WeightMap
+
+	// Background writer (see sqlite_writer.go).
+	jobs         chan logJob
+	writerDone   chan struct{}
+	dropWhenFull bool
+	dropped      atomic.Int64 // Read by Dropped, possibly from another goroutine.
+	errMu        sync.Mutex
+	writeErr     error // writeErr is the first error of the writer goroutine.
+	statsMu      sync.Mutex
+	stats        WriteStats // stats times the writes of the writer goroutine (see WriteStats).
 }
 
 // NewSQLiteLogger creates or opens an SQLite database file specified by dataSourceName
 // and prepares it for logging network snapshots.
-// It ensures the necessary tables ('NetworkSnapshots', 'NeuronStates') are created if they don't exist.
+// It ensures the necessary tables ('Runs', 'NetworkSnapshots', 'NeuronStates', 'Spikes',
+// 'SynapseSnapshots') are created if they don't exist, and adds the RunID columns to
+// databases written before runs were tracked.
+// Each logger gets a new run ID, so several runs can share one database file; call
+// StartRun to record the run's metadata in the 'Runs' table.
 // Unlike previous versions, this function will NOT delete an existing database file.
 // It will open an existing one or create a new one if it's not found.
-func NewSQLiteLogger(dataSourceName string) (*SQLiteLogger, error) {
+// The database is switched to WAL mode and opts configures the background writer.
+func NewSQLiteLogger(dataSourceName string, opts LoggerOptions) (*SQLiteLogger, error) {
 	// The database file will be created by sql.Open if it doesn't exist.
 	// os.Remove has been removed to allow persistence of logs across runs.
 	dbConn, err := sql.Open("sqlite3", dataSourceName)
@@ -37,23 +84,69 @@ func NewSQLiteLogger(dataSourceName string) (*SQLiteLogger, error) {
 		dbConn.Close()
 		return nil, fmt.Errorf("failed to ping SQLite database at %s: %w", dataSourceName, err)
 	}
+	// A single connection serializes StartRun, the writer goroutine and Close, so they
+	// never contend for SQLite's write lock. WAL lets readers run while the log grows.
+	dbConn.SetMaxOpenConns(1)
+	if _, err = dbConn.Exec(`PRAGMA journal_mode=WAL; PRAGMA synchronous=NORMAL;`); err != nil {
+		dbConn.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode on SQLite database at %s: %w", dataSourceName, err)
+	}
 
-	logger := &SQLiteLogger{db: dbConn}
+	runID, err := newRunID()
+	if err != nil {
+		dbConn.Close()
+		return nil, err
+	}
+
+	logger := &SQLiteLogger{db: dbConn, runID: runID, dropWhenFull: opts.DropWhenFull}
 	if err = logger.createTables(); err != nil {
 		dbConn.Close()
 		return nil, fmt.Errorf("failed to create tables in SQLite: %w", err)
 	}
+	logger.startWriter(opts.QueueSize)
 
 	return logger, nil
 }
 
-// createTables ensures that the necessary tables (NetworkSnapshots, NeuronStates) exist in the database.
+// newRunID returns a run identifier made of the UTC start time and a random suffix,
+// so IDs sort by start time and do not collide between runs started in the same second.
+func newRunID() (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix), nil
+}
+
+// RunID returns the identifier stored with every snapshot, neuron state and spike written by this logger.
+func (sl *SQLiteLogger) RunID() string {
+	return sl.runID
+}
+
+// createTables ensures that the necessary tables (Runs, NetworkSnapshots, NeuronStates,
+// Spikes, SynapseSnapshots) exist in the database.
 // If they don't exist, they are created.
 // Position and Velocity are now stored as TEXT columns containing JSON arrays.
 func (sl *SQLiteLogger) createTables() error {
+	// Config holds the full AppConfig encoded as TOML, so a run can be repeated with --config.
+	runsTableSQL := `
+    CREATE TABLE IF NOT EXISTS Runs (
+        RunID TEXT PRIMARY KEY,
+        Mode TEXT NOT NULL,
+        StartTime DATETIME NOT NULL,
+        EndTime DATETIME,
+        Seed INTEGER,
+        Config TEXT,
+        SoftwareVersion TEXT
+    );`
+	if _, err := sl.db.Exec(runsTableSQL); err != nil {
+		return fmt.Errorf("failed to create Runs table: %w", err)
+	}
+
 	networkSnapshotsTableSQL := `
     CREATE TABLE IF NOT EXISTS NetworkSnapshots (
         SnapshotID INTEGER PRIMARY KEY AUTOINCREMENT,
+        RunID TEXT REFERENCES Runs (RunID),
         CycleCount INTEGER NOT NULL,
         Timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
         CortisolLevel REAL,
@@ -70,6 +163,7 @@ func (sl *SQLiteLogger) createTables() error {
     CREATE TABLE IF NOT EXISTS NeuronStates (
         StateID INTEGER PRIMARY KEY AUTOINCREMENT,
         SnapshotID INTEGER NOT NULL,
+        RunID TEXT REFERENCES Runs (RunID),
         NeuronID INTEGER NOT NULL,
         Position TEXT,
         Velocity TEXT,
@@ -86,6 +180,59 @@ func (sl *SQLiteLogger) createTables() error {
 	if _, err := sl.db.Exec(neuronStatesTableSQL); err != nil {
 		return fmt.Errorf("failed to create NeuronStates table: %w", err)
 	}
+
+	// Databases created before runs were tracked lack the RunID columns; their old rows keep RunID NULL.
+	for _, table := range []string{"NetworkSnapshots", "NeuronStates"} {
+		if err := sl.ensureColumn(table, "RunID", "TEXT REFERENCES Runs (RunID)"); err != nil {
+			return err
+		}
+	}
+	if _, err := sl.db.Exec(`CREATE INDEX IF NOT EXISTS idx_network_snapshots_run ON NetworkSnapshots (RunID);`); err != nil {
+		return fmt.Errorf("failed to create NetworkSnapshots run index: %w", err)
+	}
+
+	// One row per firing. Rows carry no surrogate key to keep the table small.
+	spikesTableSQL := `
+    CREATE TABLE IF NOT EXISTS Spikes (
+        RunID TEXT NOT NULL,
+        Cycle INTEGER NOT NULL,
+        NeuronID INTEGER NOT NULL
+    );
+    CREATE INDEX IF NOT EXISTS idx_spikes_run_cycle ON Spikes (RunID, Cycle);`
+	if _, err := sl.db.Exec(spikesTableSQL); err != nil {
+		return fmt.Errorf("failed to create Spikes table: %w", err)
+	}
+
+	// IsDelta is 0 for rows of a full matrix and 1 for rows of a delta snapshot,
+	// which only contains synapses that changed since their previously stored value.
+	synapseSnapshotsTableSQL := `
+    CREATE TABLE IF NOT EXISTS SynapseSnapshots (
+        SnapshotID INTEGER NOT NULL,
+        PreNeuronID INTEGER NOT NULL,
+        PostNeuronID INTEGER NOT NULL,
+        Weight REAL NOT NULL,
+        IsDelta INTEGER NOT NULL,
+        FOREIGN KEY (SnapshotID) REFERENCES NetworkSnapshots (SnapshotID) ON DELETE CASCADE
+    );
+    CREATE INDEX IF NOT EXISTS idx_synapse_snapshots_snapshot ON SynapseSnapshots (SnapshotID);`
+	if _, err := sl.db.Exec(synapseSnapshotsTableSQL); err != nil {
+		return fmt.Errorf("failed to create SynapseSnapshots table: %w", err)
+	}
+	return nil
+}
+
+// ensureColumn adds a column to an existing table if it is not there yet.
+func (sl *SQLiteLogger) ensureColumn(table, column, definition string) error {
+	exists, err := hasColumn(sl.db, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if _, err := sl.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s to %s: %w", column, table, err)
+	}
 	return nil
 }
 
@@ -107,14 +254,15 @@ func (sl *SQLiteLogger) DBForTest() *sql.DB {
 //  2. For each neuron in the network, inserting its detailed state into the 'NeuronStates' table,
 //     linking it to the snapshot ID. Position and Velocity are stored as JSON strings.
 //
-// All database operations are performed within a single transaction. If any part fails,
-// the transaction is rolled back.
+// The state is copied and written by the background writer in a single transaction,
+// after any spikes logged before it. If the queue is full and the logger drops writes,
+// the snapshot is discarded (see Dropped).
 //
 // Parameters:
 //   - net: A pointer to the CrowNet instance whose state is to be logged.
 //
 // Returns:
-//   - error: An error if any database operation fails or if the logger is not initialized, nil otherwise.
+//   - error: An error if the logger is not initialized or an earlier write failed, nil otherwise.
 func (sl *SQLiteLogger) LogNetworkState(net *network.CrowNet) error {
 	if sl.db == nil {
 		return fmt.Errorf("SQLiteLogger not initialized (db is nil)")
@@ -128,90 +276,172 @@ func (sl *SQLiteLogger) LogNetworkState(net *network.CrowNet) error {
 	if net.SimParams == nil { // SimParams is accessed for CortisolGlandPosition
 		return fmt.Errorf("cannot log network state: SimParams in CrowNet is nil")
 	}
+	// Hand buffered spikes over first so the Spikes table is complete up to every snapshot.
+	if err := sl.FlushSpikes(); err != nil {
+		return err
+	}
 
-	tx, err := sl.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin SQLite transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	snapshotRes, err := tx.Exec(`INSERT INTO NetworkSnapshots
-                                     (CycleCount, Timestamp, CortisolLevel, DopamineLevel, LearningRateModFactor, SynaptogenesisModFactor)
-                                 VALUES (?, ?, ?, ?, ?, ?)`,
-		net.CycleCount,
-		time.Now(),
-		net.ChemicalEnv.CortisolLevel,
-		net.ChemicalEnv.DopamineLevel,
-		net.ChemicalEnv.LearningRateModulationFactor,
-		net.ChemicalEnv.SynaptogenesisModulationFactor,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to insert into NetworkSnapshots: %w", err)
+	job := &stateJob{
+		cycle:                   net.CycleCount,
+		timestamp:               time.Now(),
+		cortisol:                float64(net.ChemicalEnv.CortisolLevel),
+		dopamine:                float64(net.ChemicalEnv.DopamineLevel),
+		learningRateModFactor:   float64(net.ChemicalEnv.LearningRateModulationFactor),
+		synaptogenesisModFactor: float64(net.ChemicalEnv.SynaptogenesisModulationFactor),
+		neurons:                 make([]neuronStateRow, 0, len(net.Neurons)),
 	}
-	snapshotID, err := snapshotRes.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get LastInsertId for snapshot: %w", err)
+	for _, n := range net.Neurons {
+		job.neurons = append(job.neurons, neuronStateRow{
+			id:                     n.ID,
+			position:               n.Position,
+			velocity:               n.Velocity,
+			neuronType:             n.Type,
+			currentState:           n.CurrentState,
+			accumulatedPotential:   float64(n.AccumulatedPotential),
+			baseFiringThreshold:    float64(n.BaseFiringThreshold),
+			currentFiringThreshold: float64(n.CurrentFiringThreshold),
+			lastFiredCycle:         int(n.LastFiredCycle),
+			cyclesInCurrentState:   int(n.CyclesInCurrentState),
+		})
 	}
 
-	// SQL query for inserting neuron states. Position and Velocity are now single TEXT columns.
-	neuronStateSQL := `INSERT INTO NeuronStates (
-		SnapshotID, NeuronID, Position, Velocity,
-		Type, CurrentState, AccumulatedPotential, BaseFiringThreshold,
-		CurrentFiringThreshold, LastFiredCycle, CyclesInCurrentState
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	stmt, err := tx.Prepare(neuronStateSQL)
+	queued, err := sl.enqueue(job, true)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement for NeuronStates: %w", err)
+		return err
 	}
-	defer stmt.Close()
+	if queued {
+		sl.lastSnapshotCycle = net.CycleCount
+		sl.hasSnapshot = true
+	}
+	return nil
+}
 
-	for _, n := range net.Neurons {
-		// Serialize Position and Velocity to JSON strings.
-		posJSON, err := json.Marshal(n.Position)
-		if err != nil {
-			return fmt.Errorf("failed to serialize Position to JSON for neuron %d: %w", n.ID, err)
+// LogSynapseSnapshot stores the synaptic weights of 'net' in the 'SynapseSnapshots' table,
+// linked to the NetworkSnapshots row of the current cycle. If LogNetworkState has not
+// been called for this cycle, it is called first so that the link always exists.
+//
+// With mode config.SynapseLogFull every existing synapse is stored. With mode
+// config.SynapseLogDelta only synapses whose weight differs by more than threshold
+// from the value last stored for them are stored; the first snapshot of a logger is
+// always full. Comparing against the last stored value (rather than the previous
+// snapshot) means that a matrix rebuilt from the rows is never off by more than threshold.
+// Synapses are visited in neuron ID order, and all rows are written in one transaction.
+// A snapshot discarded because the queue was full does not count as stored.
+func (sl *SQLiteLogger) LogSynapseSnapshot(net *network.CrowNet, mode string, threshold float64) error {
+	if sl.db == nil {
+		return fmt.Errorf("SQLiteLogger not initialized (db is nil)")
+	}
+	if net == nil || net.SynapticWeights == nil {
+		return fmt.Errorf("cannot log synapse snapshot: CrowNet or its SynapticWeights is nil")
+	}
+	if !sl.hasSnapshot || sl.lastSnapshotCycle != net.CycleCount {
+		if err := sl.LogNetworkState(net); err != nil {
+			return fmt.Errorf("failed to log network state for synapse snapshot: %w", err)
 		}
-		velJSON, err := json.Marshal(n.Velocity)
-		if err != nil {
-			return fmt.Errorf("failed to serialize Velocity to JSON for neuron %d: %w", n.ID, err)
+		if !sl.hasSnapshot || sl.lastSnapshotCycle != net.CycleCount {
+			sl.dropped.Add(1) // The network snapshot was dropped, so there is nothing to link to.
+			return nil
 		}
+	}
+	job := &synapseJob{
+		cycle:   net.CycleCount,
+		isDelta: mode == config.SynapseLogDelta && sl.storedWeights != nil,
+	}
 
-		_, err = stmt.Exec(
-			snapshotID,
-			n.ID,
-			string(posJSON), // Store as JSON string
-			string(velJSON), // Store as JSON string
-			int(n.Type),
-			int(n.CurrentState),
-			float64(n.AccumulatedPotential),
-			float64(n.BaseFiringThreshold),
-			float64(n.CurrentFiringThreshold),
-			int(n.LastFiredCycle),
-			int(n.CyclesInCurrentState),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert state for neuron %d: %w", n.ID, err)
+	stored := make(map[common.NeuronID]synaptic.WeightMap, len(net.Neurons))
+	for _, pre := range net.Neurons {
+		for _, post := range net.Neurons {
+			if pre.ID == post.ID || !net.SynapticWeights.HasConnection(pre.ID, post.ID) {
+				continue
+			}
+			w := net.SynapticWeights.GetWeight(pre.ID, post.ID)
+			write := true
+			if job.isDelta {
+				if prev, ok := sl.storedWeights[pre.ID][post.ID]; ok && math.Abs(float64(w-prev)) <= threshold {
+					w, write = prev, false // Keep comparing against the value already stored.
+				}
+			}
+			if write {
+				job.rows = append(job.rows, synapseRow{pre: pre.ID, post: post.ID, weight: w})
+			}
+			if stored[pre.ID] == nil {
+				stored[pre.ID] = make(synaptic.WeightMap)
+			}
+			stored[pre.ID][post.ID] = w
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit SQLite transaction: %w", err)
+	queued, err := sl.enqueue(job, true)
+	if err != nil {
+		return err
+	}
+	if queued {
+		sl.storedWeights = stored
 	}
 	return nil
 }
 
-// Close closes the underlying SQLite database connection.
-// It's important to call this when the logger is no longer needed to free resources.
-// Returns an error if closing the database fails. Sets sl.db to nil on successful close.
+// LogSpikes records that the given neurons fired in the given cycle, typically the
+// values of CrowNet.FiredLastCycle() and CycleCount-1 after each RunCycle.
+// Spikes are buffered and handed to the writer in batches of whole cycles,
+// once spikeFlushRows rows have accumulated, before each LogNetworkState snapshot,
+// and on Close. neuronIDs is copied, so the caller may reuse the slice.
+func (sl *SQLiteLogger) LogSpikes(cycle common.CycleCount, neuronIDs []common.NeuronID) error {
+	if sl.db == nil {
+		return fmt.Errorf("SQLiteLogger not initialized (db is nil)")
+	}
+	for _, id := range neuronIDs {
+		sl.pendingSpikes = append(sl.pendingSpikes, spikeRow{cycle: cycle, neuronID: id})
+	}
+	if len(sl.pendingSpikes) >= spikeFlushRows {
+		return sl.FlushSpikes()
+	}
+	return nil
+}
+
+// FlushSpikes hands all buffered spikes to the writer, which stores them in the
+// 'Spikes' table in a single transaction. If the queue is full and the logger drops
+// writes, the buffered spikes are discarded as one batch (see Dropped).
+func (sl *SQLiteLogger) FlushSpikes() error {
+	return sl.flushSpikes(true)
+}
+
+func (sl *SQLiteLogger) flushSpikes(mayDrop bool) error {
+	if sl.db == nil {
+		return fmt.Errorf("SQLiteLogger not initialized (db is nil)")
+	}
+	if len(sl.pendingSpikes) == 0 {
+		return nil
+	}
+	if _, err := sl.enqueue(&spikeJob{rows: sl.pendingSpikes}, mayDrop); err != nil {
+		return err
+	}
+	sl.pendingSpikes = nil // The writer owns the handed-over slice.
+	return nil
+}
+
+// Close hands any buffered spikes to the writer, waits until everything queued has
+// been written, records the end time of the run (if StartRun was called) and closes
+// the underlying SQLite database connection.
+// It's important to call this when the logger is no longer needed, or queued writes are lost.
+// Returns the first write error, or an error if closing the database fails. Sets sl.db to nil on close.
 func (sl *SQLiteLogger) Close() error {
 	if sl.db != nil {
+		errFlush := sl.flushSpikes(false)
+		close(sl.jobs)
+		<-sl.writerDone
+		if errWrite := sl.writeError(); errWrite != nil {
+			errFlush = fmt.Errorf("SQLite log writer failed: %w", errWrite)
+		}
+		if errEnd := sl.endRun(); errFlush == nil {
+			errFlush = errEnd
+		}
 		err := sl.db.Close()
 		sl.db = nil // Set to nil even if close fails, to prevent further use of potentially bad connection
 		if err != nil {
 			return fmt.Errorf("failed to close SQLite database: %w", err)
 		}
-		return nil
+		return errFlush
 	}
 	return nil // No error if db is already nil
 }
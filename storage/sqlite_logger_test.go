@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"crownet/config"
+	"crownet/network"
+)
+
+// loggerTestNeurons is the size of the networks built by newLoggerTestNet.
+const loggerTestNeurons = 60
+
+// newLoggerTestNet builds a network of loggerTestNeurons neurons with a fixed seed.
+func newLoggerTestNet(t *testing.T) *network.CrowNet {
+	t.Helper()
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	appCfg.Cli.TotalNeurons = loggerTestNeurons
+	appCfg.Cli.Seed = 1
+	net, err := network.NewCrowNet(appCfg)
+	if err != nil {
+		t.Fatalf("NewCrowNet() error = %v", err)
+	}
+	return net
+}
+
+// runAndLog runs net for cycles cycles, logging its spikes after every cycle, its
+// state every stateEvery cycles and its synapses every synapseEvery cycles (0: never),
+// as sim mode does.
+func runAndLog(t *testing.T, logger *SQLiteLogger, net *network.CrowNet, cycles, stateEvery, synapseEvery int) {
+	t.Helper()
+	for i := 0; i < cycles; i++ {
+		net.RunCycle()
+		if err := logger.LogSpikes(net.CycleCount-1, net.FiredLastCycle()); err != nil {
+			t.Fatalf("LogSpikes() error = %v", err)
+		}
+		if int(net.CycleCount)%stateEvery == 0 {
+			if err := logger.LogNetworkState(net); err != nil {
+				t.Fatalf("LogNetworkState() error = %v", err)
+			}
+		}
+		if synapseEvery > 0 && int(net.CycleCount)%synapseEvery == 0 {
+			if err := logger.LogSynapseSnapshot(net, config.SynapseLogFull, 0); err != nil {
+				t.Fatalf("LogSynapseSnapshot() error = %v", err)
+			}
+		}
+	}
+}
+
+// newTestLog writes a log of one run at dbPath: cycles cycles of a fresh network,
+// with a snapshot every stateEvery cycles. It returns the run ID.
+func newTestLog(t *testing.T, dbPath string, cycles, stateEvery int) string {
+	t.Helper()
+	logger, err := NewSQLiteLogger(dbPath, LoggerOptions{})
+	if err != nil {
+		t.Fatalf("NewSQLiteLogger() error = %v", err)
+	}
+	appCfg := config.DefaultAppConfig(config.ModeSim)
+	if err := logger.StartRun(appCfg); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	runAndLog(t, logger, newLoggerTestNet(t), cycles, stateEvery, 0)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return logger.RunID()
+}
+
+func TestSQLiteLogger_Backpressure(t *testing.T) {
+	const cycles, synapseEvery = 20, 5
+	for _, dropWhenFull := range []bool{false, true} {
+		name := config.LogBackpressureBlock
+		if dropWhenFull {
+			name = config.LogBackpressureDrop
+		}
+		t.Run(name, func(t *testing.T) {
+			dbPath := filepath.Join(t.TempDir(), "async.db")
+			logger, err := NewSQLiteLogger(dbPath, LoggerOptions{QueueSize: 1, DropWhenFull: dropWhenFull})
+			if err != nil {
+				t.Fatalf("NewSQLiteLogger() error = %v", err)
+			}
+			runAndLog(t, logger, newLoggerTestNet(t), cycles, 1, synapseEvery)
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if dropped := logger.Dropped(); !dropWhenFull && dropped != 0 {
+				t.Errorf("Dropped() = %d with a blocking queue, want 0", dropped)
+			}
+
+			db, err := sql.Open("sqlite3", dbPath)
+			if err != nil {
+				t.Fatalf("Failed to open %s: %v", dbPath, err)
+			}
+			defer db.Close()
+			var journalMode string
+			if err := db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode); err != nil || journalMode != "wal" {
+				t.Errorf("journal_mode = %q (err %v), want wal", journalMode, err)
+			}
+			var snapshots, states, orphans int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM NetworkSnapshots`).Scan(&snapshots); err != nil {
+				t.Fatalf("Failed to count snapshots: %v", err)
+			}
+			if err := db.QueryRow(`SELECT COUNT(*) FROM NeuronStates`).Scan(&states); err != nil {
+				t.Fatalf("Failed to count neuron states: %v", err)
+			}
+			if err := db.QueryRow(`SELECT COUNT(*) FROM SynapseSnapshots s
+                                   LEFT JOIN NetworkSnapshots n ON n.SnapshotID = s.SnapshotID
+                                   WHERE n.CycleCount IS NULL OR n.CycleCount % 5 != 0`).Scan(&orphans); err != nil {
+				t.Fatalf("Failed to check synapse snapshot links: %v", err)
+			}
+			if !dropWhenFull && snapshots != cycles {
+				t.Errorf("Got %d snapshots, want %d", snapshots, cycles)
+			}
+			if snapshots == 0 || states != snapshots*loggerTestNeurons {
+				t.Errorf("Got %d neuron states for %d snapshots, want %d per snapshot", states, snapshots, loggerTestNeurons)
+			}
+			if orphans != 0 {
+				t.Errorf("%d synapse rows are not linked to the snapshot of their cycle", orphans)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"crownet/common"
+	"crownet/neuron"
+)
+
+// DefaultLogQueueSize is the number of pending snapshots (and spike or synapse batches)
+// the background writer of an SQLiteLogger holds when LoggerOptions.QueueSize is 0.
+const DefaultLogQueueSize = 16
+
+// sqliteMaxVariables is the number of '?' parameters allowed in one statement by
+// SQLite builds older than 3.32. Multi-row inserts stay below it so they work with
+// any build of the driver.
+const sqliteMaxVariables = 999
+
+// LoggerOptions configures the background writer of an SQLiteLogger.
+type LoggerOptions struct {
+	// QueueSize is the number of writes that may wait for the writer; 0 uses DefaultLogQueueSize.
+	QueueSize int
+	// DropWhenFull discards a write when the queue is full instead of blocking the caller.
+	// Discarded writes are counted by Dropped; a discarded spike batch loses its rows of
+	// the Spikes table. Close always waits for the queue to drain.
+	DropWhenFull bool
+}
+
+// WriteStats describes the writes completed by the background writer of an SQLiteLogger.
+type WriteStats struct {
+	Writes    int           // Jobs written (network snapshots, synapse snapshots and spike batches).
+	TotalTime time.Duration // Time spent writing them, including their transactions' commits.
+	LastTime  time.Duration // Duration of the most recent write.
+}
+
+// logJob is one unit of work for the background writer. Jobs own all their data,
+// copied from the network when they were queued, and run in the order they were queued.
+type logJob interface {
+	write(w *logWriter) error
+}
+
+// logWriter holds the state used only by the writer goroutine.
+type logWriter struct {
+	db    *sql.DB
+	runID string
+	// snapshotID and snapshotCycle identify the last NetworkSnapshots row written,
+	// which synapse snapshots of the same cycle are linked to.
+	snapshotID    int64
+	snapshotCycle common.CycleCount
+	hasSnapshot   bool
+}
+
+// neuronStateRow is a copy of the fields of one neuron stored in the NeuronStates table.
+type neuronStateRow struct {
+	id                     common.NeuronID
+	position               common.Point
+	velocity               common.Point
+	neuronType             neuron.Type
+	currentState           neuron.State
+	accumulatedPotential   float64
+	baseFiringThreshold    float64
+	currentFiringThreshold float64
+	lastFiredCycle         int
+	cyclesInCurrentState   int
+}
+
+// stateJob writes one NetworkSnapshots row and the NeuronStates rows of its neurons.
+type stateJob struct {
+	cycle                   common.CycleCount
+	timestamp               time.Time
+	cortisol                float64
+	dopamine                float64
+	learningRateModFactor   float64
+	synaptogenesisModFactor float64
+	neurons                 []neuronStateRow
+}
+
+func (j *stateJob) write(w *logWriter) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	snapshotRes, err := tx.Exec(`INSERT INTO NetworkSnapshots
+                                     (RunID, CycleCount, Timestamp, CortisolLevel, DopamineLevel, LearningRateModFactor, SynaptogenesisModFactor)
+                                 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		w.runID, j.cycle, j.timestamp, j.cortisol, j.dopamine, j.learningRateModFactor, j.synaptogenesisModFactor)
+	if err != nil {
+		return fmt.Errorf("failed to insert into NetworkSnapshots: %w", err)
+	}
+	snapshotID, err := snapshotRes.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get LastInsertId for snapshot: %w", err)
+	}
+
+	insert := newMultiRowInsert(tx, "NeuronStates", []string{
+		"SnapshotID", "RunID", "NeuronID", "Position", "Velocity",
+		"Type", "CurrentState", "AccumulatedPotential", "BaseFiringThreshold",
+		"CurrentFiringThreshold", "LastFiredCycle", "CyclesInCurrentState",
+	})
+	defer insert.close()
+	for _, n := range j.neurons {
+		// Position and Velocity are stored as JSON arrays.
+		posJSON, err := json.Marshal(n.position)
+		if err != nil {
+			return fmt.Errorf("failed to serialize Position to JSON for neuron %d: %w", n.id, err)
+		}
+		velJSON, err := json.Marshal(n.velocity)
+		if err != nil {
+			return fmt.Errorf("failed to serialize Velocity to JSON for neuron %d: %w", n.id, err)
+		}
+		if err := insert.add(snapshotID, w.runID, n.id, string(posJSON), string(velJSON),
+			int(n.neuronType), int(n.currentState), n.accumulatedPotential, n.baseFiringThreshold,
+			n.currentFiringThreshold, n.lastFiredCycle, n.cyclesInCurrentState); err != nil {
+			return fmt.Errorf("failed to insert neuron states of cycle %d: %w", j.cycle, err)
+		}
+	}
+	if err := insert.flush(); err != nil {
+		return fmt.Errorf("failed to insert neuron states of cycle %d: %w", j.cycle, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit SQLite transaction: %w", err)
+	}
+	w.snapshotID = snapshotID
+	w.snapshotCycle = j.cycle
+	w.hasSnapshot = true
+	return nil
+}
+
+// synapseRow is one row of the SynapseSnapshots table.
+type synapseRow struct {
+	pre, post common.NeuronID
+	weight    common.SynapticWeight
+}
+
+// synapseJob writes a synapse snapshot linked to the NetworkSnapshots row of its cycle,
+// which an earlier stateJob has written.
+type synapseJob struct {
+	cycle   common.CycleCount
+	isDelta bool
+	rows    []synapseRow
+}
+
+func (j *synapseJob) write(w *logWriter) error {
+	if !w.hasSnapshot || w.snapshotCycle != j.cycle {
+		return fmt.Errorf("synapse snapshot of cycle %d has no network snapshot to link to", j.cycle)
+	}
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction for synapse snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := newMultiRowInsert(tx, "SynapseSnapshots",
+		[]string{"SnapshotID", "PreNeuronID", "PostNeuronID", "Weight", "IsDelta"})
+	defer insert.close()
+	for _, row := range j.rows {
+		if err := insert.add(w.snapshotID, int64(row.pre), int64(row.post), float64(row.weight), j.isDelta); err != nil {
+			return fmt.Errorf("failed to insert synapse snapshot of cycle %d: %w", j.cycle, err)
+		}
+	}
+	if err := insert.flush(); err != nil {
+		return fmt.Errorf("failed to insert synapse snapshot of cycle %d: %w", j.cycle, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit synapse snapshot transaction: %w", err)
+	}
+	return nil
+}
+
+// spikeJob writes a batch of buffered spikes to the Spikes table.
+type spikeJob struct {
+	rows []spikeRow
+}
+
+func (j *spikeJob) write(w *logWriter) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction for spikes: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := newMultiRowInsert(tx, "Spikes", []string{"RunID", "Cycle", "NeuronID"})
+	defer insert.close()
+	for _, row := range j.rows {
+		if err := insert.add(w.runID, int64(row.cycle), int64(row.neuronID)); err != nil {
+			return fmt.Errorf("failed to insert spikes: %w", err)
+		}
+	}
+	if err := insert.flush(); err != nil {
+		return fmt.Errorf("failed to insert spikes: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit spikes transaction: %w", err)
+	}
+	return nil
+}
+
+// multiRowInsert accumulates rows for one table and writes them with
+// "INSERT ... VALUES (...), (...), ..." statements of as many rows as
+// sqliteMaxVariables allows. The statement for a full batch is prepared once.
+type multiRowInsert struct {
+	tx          *sql.Tx
+	table       string
+	columns     []string
+	rowsPerStmt int
+	full        *sql.Stmt
+	args        []any
+	rows        int
+}
+
+func newMultiRowInsert(tx *sql.Tx, table string, columns []string) *multiRowInsert {
+	return &multiRowInsert{
+		tx:          tx,
+		table:       table,
+		columns:     columns,
+		rowsPerStmt: sqliteMaxVariables / len(columns),
+	}
+}
+
+// add appends one row, whose values are in column order, and writes a full batch.
+func (m *multiRowInsert) add(values ...any) error {
+	m.args = append(m.args, values...)
+	m.rows++
+	if m.rows < m.rowsPerStmt {
+		return nil
+	}
+	if m.full == nil {
+		stmt, err := m.tx.Prepare(m.statement(m.rowsPerStmt))
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert into %s: %w", m.table, err)
+		}
+		m.full = stmt
+	}
+	if _, err := m.full.Exec(m.args...); err != nil {
+		return fmt.Errorf("failed to insert into %s: %w", m.table, err)
+	}
+	m.args, m.rows = m.args[:0], 0
+	return nil
+}
+
+// flush writes the rows of an incomplete batch.
+func (m *multiRowInsert) flush() error {
+	if m.rows == 0 {
+		return nil
+	}
+	if _, err := m.tx.Exec(m.statement(m.rows), m.args...); err != nil {
+		return fmt.Errorf("failed to insert into %s: %w", m.table, err)
+	}
+	m.args, m.rows = m.args[:0], 0
+	return nil
+}
+
+func (m *multiRowInsert) close() {
+	if m.full != nil {
+		m.full.Close()
+	}
+}
+
+func (m *multiRowInsert) statement(rows int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(m.columns)), ", ") + ")"
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO " + m.table + " (" + strings.Join(m.columns, ", ") + ") VALUES ")
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(row)
+	}
+	return sb.String()
+}
+
+// startWriter starts the goroutine that runs queued jobs until the queue is closed.
+func (sl *SQLiteLogger) startWriter(queueSize int) {
+	if queueSize <= 0 {
+		queueSize = DefaultLogQueueSize
+	}
+	sl.jobs = make(chan logJob, queueSize)
+	sl.writerDone = make(chan struct{})
+	w := &logWriter{db: sl.db, runID: sl.runID}
+	go func() {
+		defer close(sl.writerDone)
+		for job := range sl.jobs {
+			if sl.writeError() != nil {
+				continue // Drain the queue; the first error is reported to the caller.
+			}
+			start := time.Now()
+			err := job.write(w)
+			elapsed := time.Since(start)
+			sl.statsMu.Lock()
+			sl.stats.Writes++
+			sl.stats.TotalTime += elapsed
+			sl.stats.LastTime = elapsed
+			sl.statsMu.Unlock()
+			if err != nil {
+				sl.errMu.Lock()
+				sl.writeErr = err
+				sl.errMu.Unlock()
+			}
+		}
+	}()
+}
+
+// writeError returns the first error of the background writer, if any.
+func (sl *SQLiteLogger) writeError() error {
+	sl.errMu.Lock()
+	defer sl.errMu.Unlock()
+	return sl.writeErr
+}
+
+// enqueue hands a job to the background writer. With mayDrop and a logger created
+// with DropWhenFull, a job that does not fit in the queue is discarded and counted;
+// otherwise the call waits for room. It reports whether the job was queued, and
+// returns the first error of the writer, so a failed write surfaces on the next call.
+func (sl *SQLiteLogger) enqueue(job logJob, mayDrop bool) (bool, error) {
+	if err := sl.writeError(); err != nil {
+		return false, fmt.Errorf("SQLite log writer failed: %w", err)
+	}
+	if mayDrop && sl.dropWhenFull {
+		select {
+		case sl.jobs <- job:
+			return true, nil
+		default:
+			sl.dropped.Add(1)
+			return false, nil
+		}
+	}
+	sl.jobs <- job
+	return true, nil
+}
+
+// Dropped returns the number of writes (network snapshots, synapse snapshots and
+// spike batches) discarded because the queue was full. It is always 0 unless the
+// logger was created with DropWhenFull. It is safe to call while the logger is in use.
+func (sl *SQLiteLogger) Dropped() int {
+	return int(sl.dropped.Load())
+}
+
+// WriteStats returns the number and duration of the writes completed so far. It
+// may be called while the writer is running.
+func (sl *SQLiteLogger) WriteStats() WriteStats {
+	sl.statsMu.Lock()
+	defer sl.statsMu.Unlock()
+	return sl.stats
+}
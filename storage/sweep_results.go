@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SweepParamValue is the value one sweep parameter took in a run.
+type SweepParamValue struct {
+	Name  string
+	Value any
+}
+
+// SweepResult holds the configuration and metrics of one run of a hyperparameter sweep.
+type SweepResult struct {
+	Index               int               // Configuration number, starting at 1.
+	Repeat              int               // Repeat of the configuration, starting at 0.
+	Seed                int64             // Seed the run's network was built with.
+	Params              []SweepParamValue // Values of the swept parameters, in spec order.
+	DistinctWinnersFrac *float64          // Expose runs: distinct winning output neurons over 10 digits.
+	Cortisol            float64           // Final cortisol level.
+	Dopamine            float64           // Final dopamine level.
+	LRModFactor         float64           // Final learning rate modulation factor.
+	MeanAbsWeight       float64           // Mean absolute synaptic weight at the end of the run.
+	Duration            time.Duration     // Wall time of the run.
+	Error               string            // Why the run failed; empty on success.
+}
+
+// IsSQLitePath reports whether path names an SQLite database (.db, .sqlite or .sqlite3).
+func IsSQLitePath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	}
+	return false
+}
+
+// WriteSweepResults writes sweep results to filePath. SQLite paths (see IsSQLitePath)
+// get the rows appended to a 'SweepResults' table, tagged with sweepID; any other
+// path is (over)written as CSV with one column per swept parameter.
+func WriteSweepResults(filePath, sweepID string, results []SweepResult) error {
+	if IsSQLitePath(filePath) {
+		return writeSweepResultsSQLite(filePath, sweepID, results)
+	}
+	return writeSweepResultsCSV(filePath, results)
+}
+
+func writeSweepResultsCSV(filePath string, results []SweepResult) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create sweep results file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	header := []string{"run", "repeat", "seed"}
+	if len(results) > 0 {
+		for _, p := range results[0].Params {
+			header = append(header, p.Name)
+		}
+	}
+	header = append(header, "distinct_winners_frac", "cortisol", "dopamine", "lr_mod_factor",
+		"mean_abs_weight", "duration_s", "error")
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write sweep results header: %w", err)
+	}
+	for _, r := range results {
+		record := []string{strconv.Itoa(r.Index), strconv.Itoa(r.Repeat), strconv.FormatInt(r.Seed, 10)}
+		for _, p := range r.Params {
+			record = append(record, fmt.Sprint(p.Value))
+		}
+		winners := ""
+		if r.DistinctWinnersFrac != nil {
+			winners = formatFloat(*r.DistinctWinnersFrac)
+		}
+		record = append(record, winners, formatFloat(r.Cortisol), formatFloat(r.Dopamine),
+			formatFloat(r.LRModFactor), formatFloat(r.MeanAbsWeight),
+			formatFloat(r.Duration.Seconds()), r.Error)
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write sweep result %d: %w", r.Index, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write sweep results to %s: %w", filePath, err)
+	}
+	return file.Close()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeSweepResultsSQLite(filePath, sweepID string, results []SweepResult) error {
+	db, err := sql.Open("sqlite3", filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database at %s: %w", filePath, err)
+	}
+	defer db.Close()
+
+	// Parameters holds a JSON object of the swept values, queryable with json_extract.
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS SweepResults (
+        SweepID TEXT NOT NULL,
+        Run INTEGER NOT NULL,
+        Repeat INTEGER NOT NULL,
+        Seed INTEGER,
+        Parameters TEXT,
+        DistinctWinnersFrac REAL,
+        Cortisol REAL,
+        Dopamine REAL,
+        LRModFactor REAL,
+        MeanAbsWeight REAL,
+        DurationSeconds REAL,
+        Error TEXT
+    );`)
+	if err != nil {
+		return fmt.Errorf("failed to create SweepResults table: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction for sweep results: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO SweepResults (SweepID, Run, Repeat, Seed, Parameters, DistinctWinnersFrac,
+                                 Cortisol, Dopamine, LRModFactor, MeanAbsWeight, DurationSeconds, Error)
+                             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement for SweepResults: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range results {
+		params := make(map[string]any, len(r.Params))
+		for _, p := range r.Params {
+			params[p.Name] = p.Value
+		}
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to serialize parameters of sweep run %d: %w", r.Index, err)
+		}
+		var winners, errText any
+		if r.DistinctWinnersFrac != nil {
+			winners = *r.DistinctWinnersFrac
+		}
+		if r.Error != "" {
+			errText = r.Error
+		}
+		if _, err := stmt.Exec(sweepID, r.Index, r.Repeat, r.Seed, string(paramsJSON), winners,
+			r.Cortisol, r.Dopamine, r.LRModFactor, r.MeanAbsWeight, r.Duration.Seconds(), errText); err != nil {
+			return fmt.Errorf("failed to insert sweep result %d: %w", r.Index, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sweep results: %w", err)
+	}
+	return nil
+}
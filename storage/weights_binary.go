@@ -0,0 +1,419 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"crownet/common"
+	"crownet/synaptic"
+)
+
+// Binary weights format ("CNWB"), little endian:
+//
+//	offset  size  field
+//	0       4     magic "CNWB"
+//	4       2     format version (BinaryWeightsVersion)
+//	6       1     weight dtype (WeightDTypeFloat32 or WeightDTypeFloat64)
+//	7       1     reserved, zero
+//	8       4     neuron count: number of presynaptic rows that follow
+//	12      8     synapse count: total number of entries in all rows
+//	20      4     CRC-32C (Castagnoli) of everything after the header
+//	24      ...   rows, in ascending presynaptic ID order
+//
+// Each row is the int32 presynaptic neuron ID, the uint32 number of entries, and
+// per entry the int32 postsynaptic neuron ID followed by the weight (4 or 8 bytes
+// depending on the dtype), in ascending postsynaptic ID order.
+
+// BinaryWeightsVersion is the version of the binary weights format written by
+// WriteBinaryWeights.
+const BinaryWeightsVersion = 1
+
+// binaryWeightsMagic identifies a binary weights file.
+const binaryWeightsMagic = "CNWB"
+
+// binaryWeightsHeaderSize is the size in bytes of the binary weights header.
+const binaryWeightsHeaderSize = 24
+
+// binaryWeightsChunkEntries is the number of row entries read at a time. Counts in
+// a file are only trusted once the data they announce has actually been read, so a
+// corrupt count cannot make the reader allocate more than one chunk ahead.
+const binaryWeightsChunkEntries = 4096
+
+// WeightDType is the storage type of the weights in a binary weights file.
+type WeightDType uint8
+
+const (
+	// WeightDTypeFloat32 stores weights as IEEE 754 single precision (half the size, ~7 significant digits).
+	WeightDTypeFloat32 WeightDType = 1
+	// WeightDTypeFloat64 stores weights as IEEE 754 double precision, exactly as held in memory.
+	WeightDTypeFloat64 WeightDType = 2
+)
+
+// ParseWeightDType converts "float32" or "float64" into a WeightDType.
+func ParseWeightDType(s string) (WeightDType, error) {
+	switch strings.ToLower(s) {
+	case "float32":
+		return WeightDTypeFloat32, nil
+	case "float64":
+		return WeightDTypeFloat64, nil
+	}
+	return 0, fmt.Errorf("unknown weight dtype '%s', expected 'float32' or 'float64'", s)
+}
+
+// String returns the name accepted by ParseWeightDType.
+func (d WeightDType) String() string {
+	switch d {
+	case WeightDTypeFloat32:
+		return "float32"
+	case WeightDTypeFloat64:
+		return "float64"
+	}
+	return fmt.Sprintf("WeightDType(%d)", uint8(d))
+}
+
+func (d WeightDType) size() int {
+	if d == WeightDTypeFloat32 {
+		return 4
+	}
+	return 8
+}
+
+// BinaryWeightsHeader is the decoded header of a binary weights file.
+type BinaryWeightsHeader struct {
+	Version      uint16
+	DType        WeightDType
+	NeuronCount  uint32
+	SynapseCount uint64
+	Checksum     uint32
+}
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// WriteBinaryWeights writes weights to w in the binary format. The rows are
+// encoded twice, once to compute the checksum stored in the header and once to
+// write them, so the output is streamed without holding it in memory.
+func WriteBinaryWeights(w io.Writer, weights map[common.NeuronID]synaptic.WeightMap, dtype WeightDType) error {
+	if dtype != WeightDTypeFloat32 && dtype != WeightDTypeFloat64 {
+		return fmt.Errorf("unsupported weight dtype %d", dtype)
+	}
+	fromIDs := make([]common.NeuronID, 0, len(weights))
+	var synapses uint64
+	for fromID, row := range weights {
+		if err := checkInt32ID(fromID); err != nil {
+			return err
+		}
+		fromIDs = append(fromIDs, fromID)
+		synapses += uint64(len(row))
+	}
+	sort.Slice(fromIDs, func(i, j int) bool { return fromIDs[i] < fromIDs[j] })
+
+	forEachRow := func(fn func([]byte) error) error {
+		var buf []byte
+		for _, fromID := range fromIDs {
+			var err error
+			buf, err = appendBinaryRow(buf[:0], fromID, weights[fromID], dtype)
+			if err != nil {
+				return err
+			}
+			if err := fn(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	crc := crc32.New(crc32c)
+	if err := forEachRow(func(row []byte) error { _, err := crc.Write(row); return err }); err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, binaryWeightsHeaderSize)
+	header = append(header, binaryWeightsMagic...)
+	header = binary.LittleEndian.AppendUint16(header, BinaryWeightsVersion)
+	header = append(header, byte(dtype), 0)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(fromIDs)))
+	header = binary.LittleEndian.AppendUint64(header, synapses)
+	header = binary.LittleEndian.AppendUint32(header, crc.Sum32())
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header); err != nil {
+		return fmt.Errorf("failed to write binary weights header: %w", err)
+	}
+	if err := forEachRow(func(row []byte) error { _, err := bw.Write(row); return err }); err != nil {
+		return fmt.Errorf("failed to write binary weights: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write binary weights: %w", err)
+	}
+	return nil
+}
+
+// appendBinaryRow encodes one presynaptic row, with entries in ascending target ID order.
+func appendBinaryRow(buf []byte, fromID common.NeuronID, row synaptic.WeightMap, dtype WeightDType) ([]byte, error) {
+	toIDs := make([]common.NeuronID, 0, len(row))
+	for toID := range row {
+		if err := checkInt32ID(toID); err != nil {
+			return nil, err
+		}
+		toIDs = append(toIDs, toID)
+	}
+	sort.Slice(toIDs, func(i, j int) bool { return toIDs[i] < toIDs[j] })
+
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(int32(fromID)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(toIDs)))
+	for _, toID := range toIDs {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(int32(toID)))
+		if dtype == WeightDTypeFloat32 {
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(row[toID])))
+		} else {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(float64(row[toID])))
+		}
+	}
+	return buf, nil
+}
+
+func checkInt32ID(id common.NeuronID) error {
+	if int64(id) < math.MinInt32 || int64(id) > math.MaxInt32 {
+		return fmt.Errorf("neuron ID %d does not fit the binary weights format (int32)", id)
+	}
+	return nil
+}
+
+// BinaryWeightsReader reads a binary weights file one presynaptic row at a time.
+type BinaryWeightsReader struct {
+	r        *bufio.Reader
+	header   BinaryWeightsHeader
+	crc      hash.Hash32
+	rows     uint32
+	synapses uint64
+	done     bool
+	chunk    []byte // Buffer for up to binaryWeightsChunkEntries entries.
+}
+
+// NewBinaryWeightsReader reads and validates the header from r.
+func NewBinaryWeightsReader(r io.Reader) (*BinaryWeightsReader, error) {
+	br := &BinaryWeightsReader{r: bufio.NewReader(r), crc: crc32.New(crc32c)}
+	header := make([]byte, binaryWeightsHeaderSize)
+	if _, err := io.ReadFull(br.r, header); err != nil {
+		return nil, fmt.Errorf("failed to read binary weights header: %w", err)
+	}
+	if string(header[:4]) != binaryWeightsMagic {
+		return nil, fmt.Errorf("not a binary weights file (magic %q)", header[:4])
+	}
+	br.header = BinaryWeightsHeader{
+		Version:      binary.LittleEndian.Uint16(header[4:]),
+		DType:        WeightDType(header[6]),
+		NeuronCount:  binary.LittleEndian.Uint32(header[8:]),
+		SynapseCount: binary.LittleEndian.Uint64(header[12:]),
+		Checksum:     binary.LittleEndian.Uint32(header[20:]),
+	}
+	if br.header.Version != BinaryWeightsVersion {
+		return nil, fmt.Errorf("binary weights format version %d is not supported (expected %d)",
+			br.header.Version, BinaryWeightsVersion)
+	}
+	if br.header.DType != WeightDTypeFloat32 && br.header.DType != WeightDTypeFloat64 {
+		return nil, fmt.Errorf("binary weights file has unknown dtype %d", br.header.DType)
+	}
+	return br, nil
+}
+
+// Header returns the file header.
+func (br *BinaryWeightsReader) Header() BinaryWeightsHeader {
+	return br.header
+}
+
+// Next returns the next presynaptic row. After the last row it verifies the
+// counts and checksum against the header and returns io.EOF if they match.
+func (br *BinaryWeightsReader) Next() (common.NeuronID, synaptic.WeightMap, error) {
+	if br.done {
+		return 0, nil, io.EOF
+	}
+	if br.rows == br.header.NeuronCount {
+		br.done = true
+		if _, err := br.r.ReadByte(); err != io.EOF {
+			return 0, nil, fmt.Errorf("binary weights file has data after its %d rows", br.rows)
+		}
+		if br.synapses != br.header.SynapseCount {
+			return 0, nil, fmt.Errorf("binary weights file has %d synapses, header says %d",
+				br.synapses, br.header.SynapseCount)
+		}
+		if sum := br.crc.Sum32(); sum != br.header.Checksum {
+			return 0, nil, fmt.Errorf("binary weights checksum mismatch: computed %08x, header has %08x",
+				sum, br.header.Checksum)
+		}
+		return 0, nil, io.EOF
+	}
+
+	var rowHeader [8]byte
+	if err := br.read(rowHeader[:]); err != nil {
+		return 0, nil, err
+	}
+	fromID := common.NeuronID(int32(binary.LittleEndian.Uint32(rowHeader[:])))
+	count := binary.LittleEndian.Uint32(rowHeader[4:])
+	if uint64(count) > br.header.SynapseCount-br.synapses {
+		return 0, nil, fmt.Errorf("row of neuron %d has %d entries, more than the header allows", fromID, count)
+	}
+
+	entrySize := 4 + br.header.DType.size()
+	if br.chunk == nil {
+		br.chunk = make([]byte, binaryWeightsChunkEntries*entrySize)
+	}
+	row := make(synaptic.WeightMap, min(count, binaryWeightsChunkEntries))
+	for remaining := int(count); remaining > 0; {
+		n := min(remaining, binaryWeightsChunkEntries)
+		entries := br.chunk[:n*entrySize]
+		if err := br.read(entries); err != nil {
+			return 0, nil, err
+		}
+		for i := 0; i < n; i++ {
+			entry := entries[i*entrySize:]
+			toID := common.NeuronID(int32(binary.LittleEndian.Uint32(entry)))
+			if br.header.DType == WeightDTypeFloat32 {
+				row[toID] = common.SynapticWeight(math.Float32frombits(binary.LittleEndian.Uint32(entry[4:])))
+			} else {
+				row[toID] = common.SynapticWeight(math.Float64frombits(binary.LittleEndian.Uint64(entry[4:])))
+			}
+		}
+		remaining -= n
+	}
+	br.rows++
+	br.synapses += uint64(count)
+	return fromID, row, nil
+}
+
+func (br *BinaryWeightsReader) read(p []byte) error {
+	if _, err := io.ReadFull(br.r, p); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("binary weights file is truncated after %d of %d rows", br.rows, br.header.NeuronCount)
+		}
+		return fmt.Errorf("failed to read binary weights: %w", err)
+	}
+	br.crc.Write(p)
+	return nil
+}
+
+// ReadBinaryWeights reads a complete binary weights file from r.
+func ReadBinaryWeights(r io.Reader) (map[common.NeuronID]synaptic.WeightMap, error) {
+	br, err := NewBinaryWeightsReader(r)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[common.NeuronID]synaptic.WeightMap, min(br.Header().NeuronCount, binaryWeightsChunkEntries))
+	for {
+		fromID, row, err := br.Next()
+		if err == io.EOF {
+			return weights, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := weights[fromID]; dup {
+			return nil, fmt.Errorf("binary weights file contains neuron %d more than once", fromID)
+		}
+		weights[fromID] = row
+	}
+}
+
+// IsBinaryWeightsPath reports whether path names a binary weights file: the
+// extension, ignoring a trailing ".gz", is ".bin". Any other path is JSON.
+func IsBinaryWeightsPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(strings.TrimSuffix(path, ".gz")), ".bin")
+}
+
+// isGzipPath reports whether path is compressed with gzip (a ".gz" extension).
+func isGzipPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gz")
+}
+
+// SaveNetworkWeights saves weights to filePath in the format chosen by its
+// extension: binary (float64) for ".bin", JSON otherwise, gzip-compressed if
+// the path ends in ".gz".
+func SaveNetworkWeights(networkWeights *synaptic.NetworkWeights, filePath string) error {
+	if networkWeights == nil {
+		return fmt.Errorf("cannot save nil NetworkWeights")
+	}
+	if !IsBinaryWeightsPath(filePath) && !isGzipPath(filePath) {
+		return SaveNetworkWeightsToJSON(networkWeights, filePath)
+	}
+	return SaveWeightsMap(networkWeights.GetAllWeights(), filePath, WeightDTypeFloat64)
+}
+
+// SaveWeightsMap writes a weights map to filePath in the format chosen by its
+// extension (see SaveNetworkWeights). dtype applies to binary files only.
+func SaveWeightsMap(weights map[common.NeuronID]synaptic.WeightMap, filePath string, dtype WeightDType) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create weights file %s: %w", filePath, err)
+	}
+	var w io.Writer = file
+	var gz *gzip.Writer
+	if isGzipPath(filePath) {
+		gz = gzip.NewWriter(file)
+		w = gz
+	}
+
+	if IsBinaryWeightsPath(filePath) {
+		err = WriteBinaryWeights(w, weights, dtype)
+	} else {
+		err = WriteWeightsJSON(w, weights)
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write weights file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadNetworkWeights loads weights from filePath in the format chosen by its
+// extension (see SaveNetworkWeights), decompressing ".gz" files transparently.
+// Returns an error wrapping os.ErrNotExist if the file is not found.
+func LoadNetworkWeights(filePath string) (map[common.NeuronID]synaptic.WeightMap, error) {
+	if !IsBinaryWeightsPath(filePath) && !isGzipPath(filePath) {
+		return LoadNetworkWeightsFromJSON(filePath)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("weights file %s not found: %w", filePath, err)
+		}
+		return nil, fmt.Errorf("failed to open weights file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if isGzipPath(filePath) {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream of %s: %w", filePath, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var weights map[common.NeuronID]synaptic.WeightMap
+	if IsBinaryWeightsPath(filePath) {
+		weights, err = ReadBinaryWeights(r)
+	} else {
+		weights, err = ReadWeightsJSON(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load weights from %s: %w", filePath, err)
+	}
+	return weights, nil
+}
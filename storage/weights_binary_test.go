@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"crownet/common"
+	"crownet/synaptic"
+)
+
+func testWeights() map[common.NeuronID]synaptic.WeightMap {
+	return map[common.NeuronID]synaptic.WeightMap{
+		0: {1: 0.25, 2: -0.5},
+		1: {0: 0.75},
+		2: {},
+	}
+}
+
+func TestBinaryWeights_RoundTrip(t *testing.T) {
+	for _, dtype := range []WeightDType{WeightDTypeFloat32, WeightDTypeFloat64} {
+		var buf bytes.Buffer
+		if err := WriteBinaryWeights(&buf, testWeights(), dtype); err != nil {
+			t.Fatalf("WriteBinaryWeights(%s) error = %v", dtype, err)
+		}
+		got, err := ReadBinaryWeights(&buf)
+		if err != nil {
+			t.Fatalf("ReadBinaryWeights(%s) error = %v", dtype, err)
+		}
+		if !reflect.DeepEqual(got, testWeights()) {
+			t.Errorf("%s round trip = %v, want %v", dtype, got, testWeights())
+		}
+	}
+}
+
+func TestBinaryWeights_RowLongerThanOneChunk(t *testing.T) {
+	row := make(synaptic.WeightMap, 3*binaryWeightsChunkEntries+1)
+	for i := range 3*binaryWeightsChunkEntries + 1 {
+		row[common.NeuronID(i+1)] = common.SynapticWeight(i) / 1024
+	}
+	weights := map[common.NeuronID]synaptic.WeightMap{0: row}
+	var buf bytes.Buffer
+	if err := WriteBinaryWeights(&buf, weights, WeightDTypeFloat64); err != nil {
+		t.Fatalf("WriteBinaryWeights() error = %v", err)
+	}
+	got, err := ReadBinaryWeights(&buf)
+	if err != nil {
+		t.Fatalf("ReadBinaryWeights() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, weights) {
+		t.Error("a row spanning several read chunks did not round trip")
+	}
+}
+
+// TestBinaryWeights_OversizedCounts checks that counts claiming far more data than
+// the file holds are reported as truncation instead of being allocated up front.
+func TestBinaryWeights_OversizedCounts(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBinaryWeights(&buf, testWeights(), WeightDTypeFloat32); err != nil {
+		t.Fatalf("WriteBinaryWeights() error = %v", err)
+	}
+	valid := buf.Bytes()
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte)
+		wantErr string
+	}{
+		{
+			name: "row entry count",
+			corrupt: func(data []byte) {
+				// Synapse count in the header and entry count of the first row.
+				binary.LittleEndian.PutUint64(data[12:], 1<<40)
+				binary.LittleEndian.PutUint32(data[binaryWeightsHeaderSize+4:], 0xFFFFFFFF)
+			},
+			wantErr: "truncated",
+		},
+		{
+			name: "neuron count",
+			corrupt: func(data []byte) {
+				binary.LittleEndian.PutUint32(data[8:], 0xFFFFFFFF)
+			},
+			wantErr: "truncated",
+		},
+		{
+			name: "row entry count above the header's synapse count",
+			corrupt: func(data []byte) {
+				binary.LittleEndian.PutUint32(data[binaryWeightsHeaderSize+4:], 0xFFFFFFFF)
+			},
+			wantErr: "more than the header allows",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bytes.Clone(valid)
+			tt.corrupt(data)
+			_, err := ReadBinaryWeights(bytes.NewReader(data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadBinaryWeights() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBinaryWeights_ChecksumMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBinaryWeights(&buf, testWeights(), WeightDTypeFloat64); err != nil {
+		t.Fatalf("WriteBinaryWeights() error = %v", err)
+	}
+	data := buf.Bytes()
+	data[binaryWeightsHeaderSize+8+4] ^= 0xFF // Flip bits of the first weight.
+	if _, err := ReadBinaryWeights(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("ReadBinaryWeights() error = %v, want a checksum mismatch", err)
+	}
+}
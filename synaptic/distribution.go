@@ -0,0 +1,21 @@
+package synaptic
+
+import (
+	"math"
+	"math/rand"
+
+	"crownet/config"
+)
+
+// SampleWeight draws one value from the given weight distribution using rng.
+// An empty distribution name is treated as uniform.
+func SampleWeight(dist *config.WeightDistribution, rng *rand.Rand) float64 {
+	switch dist.Distribution {
+	case config.WeightNormal:
+		return dist.Mean + dist.StdDev*rng.NormFloat64()
+	case config.WeightLogNormal:
+		return math.Exp(dist.Mean + dist.StdDev*rng.NormFloat64())
+	default: // config.WeightUniform or empty
+		return dist.Min + rng.Float64()*(dist.Max-dist.Min)
+	}
+}
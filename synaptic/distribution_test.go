@@ -0,0 +1,64 @@
+package synaptic
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"crownet/config"
+)
+
+func TestSampleWeight(t *testing.T) {
+	const samples = 5000
+	tests := []struct {
+		name     string
+		dist     config.WeightDistribution
+		wantMean float64
+		check    func(w float64) bool
+	}{
+		{"uniform", config.WeightDistribution{Distribution: config.WeightUniform, Min: 0.2, Max: 0.4}, 0.3,
+			func(w float64) bool { return w >= 0.2 && w < 0.4 }},
+		{"empty defaults to uniform", config.WeightDistribution{Min: 0.1, Max: 0.1}, 0.1,
+			func(w float64) bool { return w == 0.1 }},
+		{"normal", config.WeightDistribution{Distribution: config.WeightNormal, Mean: 0.5, StdDev: 0.05}, 0.5,
+			func(float64) bool { return true }},
+		{"log_normal", config.WeightDistribution{Distribution: config.WeightLogNormal, Mean: -1.0, StdDev: 0.2},
+			math.Exp(-1.0 + 0.2*0.2/2), func(w float64) bool { return w > 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(7))
+			sum := 0.0
+			for i := 0; i < samples; i++ {
+				w := SampleWeight(&tt.dist, rng)
+				if !tt.check(w) {
+					t.Fatalf("SampleWeight() = %f, outside the support of %s", w, tt.name)
+				}
+				sum += w
+			}
+			if mean := sum / samples; math.Abs(mean-tt.wantMean) > 0.01 {
+				t.Errorf("SampleWeight() sample mean = %f, want about %f", mean, tt.wantMean)
+			}
+		})
+	}
+}
+
+func TestInitializeConnectionsWithDistribution(t *testing.T) {
+	simParams := defaultTestSimParams()
+	nw, _ := NewNetworkWeights(simParams, rand.New(rand.NewSource(42)))
+
+	fixed := &config.WeightDistribution{Distribution: config.WeightUniform, Min: 0.7, Max: 0.7}
+	tooLarge := &config.WeightDistribution{Distribution: config.WeightUniform, Min: 5.0, Max: 5.0}
+	nw.InitializeConnections([]Connection{
+		{From: 0, To: 1, Distribution: fixed},
+		{From: 1, To: 0, Distribution: tooLarge},
+	})
+
+	if got := nw.GetWeight(0, 1); math.Abs(float64(got)-0.7) > 1e-9 {
+		t.Errorf("GetWeight(0, 1) = %f, want 0.7 from the rule's distribution", got)
+	}
+	if got := nw.GetWeight(1, 0); got != simParams.Learning.MaxSynapticWeight {
+		t.Errorf("GetWeight(1, 0) = %f, want it clamped to MaxSynapticWeight %f", got, simParams.Learning.MaxSynapticWeight)
+	}
+}
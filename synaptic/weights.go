@@ -15,6 +15,15 @@ import (
 // The key is the NeuronID of the postsynaptic (target) neuron.
 type WeightMap map[common.NeuronID]common.SynapticWeight
 
+// Connection identifies a directed synapse from a presynaptic (From) to a postsynaptic (To) neuron.
+// If Distribution is set, the initial weight is drawn from it instead of the
+// default [InitialSynapticWeightMin, InitialSynapticWeightMax) range.
+type Connection struct {
+	From         common.NeuronID
+	To           common.NeuronID
+	Distribution *config.WeightDistribution
+}
+
 // NetworkWeights stores and manages all synaptic weights in the network.
 // It encapsulates the weight map and relevant simulation parameters.
 type NetworkWeights struct {
@@ -45,6 +54,43 @@ func NewNetworkWeights(simParams *config.SimulationParameters, rng *rand.Rand) (
 // Weights are randomly assigned within the bounds defined in simParams (InitialSynapticWeightMin/Max).
 // Self-connections (from a neuron to itself) are initialized with a weight of zero.
 func (nw *NetworkWeights) InitializeAllToAllWeights(neuronIDs []common.NeuronID) {
+	for _, fromID := range neuronIDs {
+		if _, exists := nw.weights[fromID]; !exists {
+			nw.weights[fromID] = make(WeightMap)
+		}
+		for _, toID := range neuronIDs {
+			if fromID == toID {
+				nw.weights[fromID][toID] = 0.0 // Self-connections are zero.
+			} else {
+				nw.weights[fromID][toID] = nw.randomInitialWeight()
+			}
+		}
+	}
+}
+
+// InitializeConnections creates exactly the given synapses, each with a random initial
+// weight. Connections with a Distribution draw from it (clamped by SetWeight); the
+// others are drawn like InitializeAllToAllWeights does. Weights are drawn in the order
+// of the connections slice, so a deterministic slice gives deterministic weights.
+// Self-connections in the slice are ignored.
+func (nw *NetworkWeights) InitializeConnections(connections []Connection) {
+	for _, c := range connections {
+		if c.From == c.To {
+			continue
+		}
+		if c.Distribution != nil {
+			nw.SetWeight(c.From, c.To, common.SynapticWeight(SampleWeight(c.Distribution, nw.rng)))
+			continue
+		}
+		if _, exists := nw.weights[c.From]; !exists {
+			nw.weights[c.From] = make(WeightMap)
+		}
+		nw.weights[c.From][c.To] = nw.randomInitialWeight()
+	}
+}
+
+// randomInitialWeight draws a weight uniformly from [InitialSynapticWeightMin, InitialSynapticWeightMax).
+func (nw *NetworkWeights) randomInitialWeight() common.SynapticWeight {
 	minW := nw.simParams.Learning.InitialSynapticWeightMin
 	maxW := nw.simParams.Learning.InitialSynapticWeightMax
 
@@ -59,23 +105,21 @@ func (nw *NetworkWeights) InitializeAllToAllWeights(neuronIDs []common.NeuronID)
 		// nw.simParams.InitialSynapticWeightMin, nw.simParams.InitialSynapticWeightMax, minW, maxW)
 	}
 
-	for _, fromID := range neuronIDs {
-		if _, exists := nw.weights[fromID]; !exists {
-			nw.weights[fromID] = make(WeightMap)
-		}
-		for _, toID := range neuronIDs {
-			if fromID == toID {
-				nw.weights[fromID][toID] = 0.0 // Self-connections are zero.
-			} else {
-				randomFactor := nw.rng.Float64() // Use the struct's rng
-				// Ensure arithmetic operations use consistent float64 types before converting to SynapticWeight
-				base := float64(minW)
-				diff := float64(maxW) - float64(minW)
-				calculatedWeight := base + randomFactor*diff
-				nw.weights[fromID][toID] = common.SynapticWeight(calculatedWeight)
-			}
-		}
+	randomFactor := nw.rng.Float64() // Use the struct's rng
+	// Ensure arithmetic operations use consistent float64 types before converting to SynapticWeight
+	base := float64(minW)
+	diff := float64(maxW) - float64(minW)
+	return common.SynapticWeight(base + randomFactor*diff)
+}
+
+// HasConnection reports whether a synapse from `fromID` to `toID` exists.
+// Learning rules use it to avoid creating synapses the topology did not generate.
+func (nw *NetworkWeights) HasConnection(fromID, toID common.NeuronID) bool {
+	if fromMap, ok := nw.weights[fromID]; ok {
+		_, exists := fromMap[toID]
+		return exists
 	}
+	return false
 }
 
 // GetWeight returns the synaptic weight from neuron `fromID` to neuron `toID`.
@@ -204,6 +248,24 @@ func (nw *NetworkWeights) GetAllWeights() map[common.NeuronID]WeightMap {
 	return copiedWeights
 }
 
+// Stats returns the number of synapses and the mean and population variance of
+// their weights, without copying the weight maps.
+func (nw *NetworkWeights) Stats() (count int, mean, variance float64) {
+	var m2 float64
+	for _, toMap := range nw.weights {
+		for _, weight := range toMap {
+			count++
+			delta := float64(weight) - mean
+			mean += delta / float64(count)
+			m2 += delta * (float64(weight) - mean)
+		}
+	}
+	if count > 0 {
+		variance = m2 / float64(count)
+	}
+	return count, mean, variance
+}
+
 // LoadWeights loads a map of weights into the NetworkWeights structure,
 // replacing any existing weights.
 // It uses the SetWeight method to ensure that loaded weights adhere to current
@@ -104,6 +104,39 @@ func TestInitializeAllToAllWeights(t *testing.T) {
 	})
 }
 
+func TestInitializeConnections(t *testing.T) {
+	simParams := defaultTestSimParams()
+	rng := rand.New(rand.NewSource(42))
+	nw, _ := NewNetworkWeights(simParams, rng)
+
+	connections := []Connection{{From: 0, To: 1}, {From: 2, To: 0}, {From: 1, To: 1}}
+	nw.InitializeConnections(connections)
+
+	for _, c := range connections[:2] {
+		if !nw.HasConnection(c.From, c.To) {
+			t.Errorf("HasConnection(%d, %d) = false after InitializeConnections, want true", c.From, c.To)
+		}
+		weight := nw.GetWeight(c.From, c.To)
+		if weight < simParams.Learning.InitialSynapticWeightMin || weight > simParams.Learning.InitialSynapticWeightMax {
+			t.Errorf("InitializeConnections() weight %d->%d = %f, not in range [%f, %f]", c.From, c.To, weight,
+				simParams.Learning.InitialSynapticWeightMin, simParams.Learning.InitialSynapticWeightMax)
+		}
+	}
+	if nw.HasConnection(1, 1) {
+		t.Errorf("InitializeConnections() created a self-connection for neuron 1")
+	}
+	if nw.HasConnection(1, 0) {
+		t.Errorf("HasConnection(1, 0) = true, want false for a connection that was not generated")
+	}
+
+	// The same connections and seed must give the same weights.
+	nwSame, _ := NewNetworkWeights(simParams, rand.New(rand.NewSource(42)))
+	nwSame.InitializeConnections(connections)
+	if nw.GetWeight(2, 0) != nwSame.GetWeight(2, 0) {
+		t.Errorf("InitializeConnections() is not deterministic: %f != %f", nw.GetWeight(2, 0), nwSame.GetWeight(2, 0))
+	}
+}
+
 func TestGetSetWeight(t *testing.T) {
 	simParams := defaultTestSimParams()
 	rng := rand.New(rand.NewSource(42))
@@ -337,3 +370,27 @@ func TestGetAllLoadWeights(t *testing.T) {
 		t.Errorf("LoadWeights should clamp overweight values, got %f want %f", nw2.GetWeight(0, 1), simParams.Learning.MaxSynapticWeight)
 	}
 }
+
+func TestStats(t *testing.T) {
+	simParams := defaultTestSimParams()
+	nw, _ := NewNetworkWeights(simParams, rand.New(rand.NewSource(42)))
+
+	if count, mean, variance := nw.Stats(); count != 0 || mean != 0 || variance != 0 {
+		t.Errorf("Stats of an empty network: got (%d, %f, %f), want zeros", count, mean, variance)
+	}
+
+	nw.LoadWeights(map[common.NeuronID]WeightMap{
+		0: {1: 0.2, 2: 0.4},
+		1: {0: 0.6},
+	})
+	count, mean, variance := nw.Stats()
+	if count != 3 {
+		t.Errorf("Stats count: got %d, want 3", count)
+	}
+	if math.Abs(mean-0.4) > 1e-9 {
+		t.Errorf("Stats mean: got %f, want 0.4", mean)
+	}
+	if wantVariance := 0.08 / 3; math.Abs(variance-wantVariance) > 1e-9 {
+		t.Errorf("Stats variance: got %f, want %f", variance, wantVariance)
+	}
+}